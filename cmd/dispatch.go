@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"isollm/internal/airyra"
+	"isollm/internal/claude"
+	"isollm/internal/config"
+	"isollm/internal/dispatch"
+	"isollm/internal/logs"
+	"isollm/internal/worker"
+)
+
+var (
+	dispatchInterval time.Duration
+	dispatchOnce     bool
+	dispatchNoLaunch bool
+)
+
+var dispatchCmd = &cobra.Command{
+	Use:   "dispatch",
+	Short: "Feed ready tasks to idle workers",
+	Long: `Run the dispatch loop that assigns airyra tasks to idle workers.
+
+On every pass the dispatcher:
+1. Reclaims tasks from dead or silent workers (see 'isollm watchdog')
+2. Frees workers whose task is done or was released
+3. Claims the next ready task on behalf of each idle running worker
+4. Checks out the isollm/<task-id> branch inside the worker
+5. Rewrites CLAUDE.md with the task title and description
+6. Launches Claude in the worker's zellij pane with the task as its prompt,
+   recording the pane to .isollm/logs/<worker>/<task-id>.log
+
+Start the session with 'isollm up --dispatch' so worker panes wait for
+the dispatcher. A worker whose pane is still running a command is not
+given a task, and Claude is stopped once its task is done or taken away.
+
+Use --once to run a single pass and exit.
+Use --no-launch to claim and prepare tasks without starting Claude.`,
+	RunE: runDispatch,
+}
+
+func init() {
+	dispatchCmd.Flags().DurationVar(&dispatchInterval, "interval", dispatch.DefaultInterval, "Time between dispatch passes")
+	dispatchCmd.Flags().BoolVar(&dispatchOnce, "once", false, "Run a single dispatch pass and exit")
+	dispatchCmd.Flags().BoolVar(&dispatchNoLaunch, "no-launch", false, "Prepare tasks without launching Claude")
+
+	rootCmd.AddCommand(dispatchCmd)
+}
+
+func runDispatch(cmd *cobra.Command, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectDir, err := config.FindProjectRoot(dir)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+
+	mgr, err := worker.NewManager(projectDir, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create worker manager: %w", err)
+	}
+
+	client, err := airyra.NewClientFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	launcher, err := claude.NewLauncher(cfg, mgr)
+	if err != nil {
+		return fmt.Errorf("failed to create Claude launcher: %w", err)
+	}
+
+	var launch dispatch.LaunchFunc
+	if !dispatchNoLaunch {
+		launch = mgr.RunInPane
+	}
+
+	wd, err := newWatchdog(projectDir, cfg, mgr, client)
+	if err != nil {
+		return err
+	}
+
+	d := dispatch.New(mgr, client, launcher, launch, os.Stdout)
+	d.SetInterval(dispatchInterval)
+	d.SetReclaimer(wd)
+
+	d.SetLogs(openLogStore(projectDir, cfg))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if dispatchOnce {
+		assignments, err := d.Tick(ctx)
+		if err != nil {
+			return err
+		}
+		if len(assignments) == 0 {
+			fmt.Println("Nothing to dispatch")
+		}
+		return nil
+	}
+
+	fmt.Printf("Dispatching tasks every %s (Ctrl-C to stop)\n", dispatchInterval)
+	return d.Run(ctx)
+}
+
+// openLogStore returns the project's task log store, rotating logs past
+// logs.max_size_mb, after pruning logs older than logs.retention
+func openLogStore(projectDir string, cfg *config.Config) *logs.Store {
+	store := logs.New(projectDir)
+	store.SetMaxSize(cfg.LogMaxBytes())
+	if removed, err := store.Prune(cfg.LogRetentionDuration()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to prune old logs: %v\n", err)
+	} else if removed > 0 {
+		fmt.Printf("Pruned %d old log file(s)\n", removed)
+	}
+	return store
+}
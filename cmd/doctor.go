@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"isollm/internal/airyra"
+	"isollm/internal/barerepo"
+	"isollm/internal/config"
+	"isollm/internal/doctor"
+	"isollm/internal/state"
+	"isollm/internal/worker"
+)
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Find and repair drift between workers, airyra and the bare repo",
+	Long: `Cross-check worker state (.isollm/workers), lxc containers, airyra task
+ownership and the isollm/* branches of the bare repo, and print every
+inconsistency with its repair:
+
+- state for a worker whose container is gone
+- a worker assigned a task that is done, open again or deleted
+- an in-progress task held by a stopped or missing worker, or by nobody
+- a task branch merged into the base branch whose task is done or deleted
+- a session whose isollm up or down died part way
+- the airyra server not running
+
+With --fix the repairs are applied: orphaned tasks are released back to
+the queue, dead state is deleted, merged branches are pruned and the
+airyra server is started. Branches with unmerged commits are only reported.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Apply the repairs")
+
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectDir, err := config.FindProjectRoot(dir)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+
+	mgr, err := worker.NewManager(projectDir, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create worker manager: %w", err)
+	}
+
+	// The server may be down; doctor reports that itself
+	var client airyra.TaskClient
+	if c, err := airyra.NewClientFromConfig(cfg); err == nil {
+		client = c
+	}
+
+	barePath, err := barerepo.GetMountPath(cfg.Project)
+	if err != nil {
+		return err
+	}
+	var repo doctor.Repo
+	if barerepo.Exists(barePath) {
+		repo = barerepo.New(barePath)
+	}
+
+	d := doctor.New(state.New(projectDir), mgr, client, repo, cfg.Git.BaseBranch)
+	d.SetAiryraStarter(func(ctx context.Context) error {
+		return ensureAiryraRunning(ctx, projectDir, cfg)
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	issues, err := d.Check(ctx)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Println("No issues found")
+		return nil
+	}
+
+	fixable, left := 0, 0
+	recheck := false
+	for _, issue := range issues {
+		fmt.Printf("✗ [%s] %s\n", issue.Source, issue.Problem)
+		if !issue.Fixable() {
+			fmt.Printf("    by hand: %s\n", issue.Repair)
+			left++
+			continue
+		}
+		fixable++
+
+		if !doctorFix {
+			fmt.Printf("    fix: %s\n", issue.Repair)
+			left++
+			continue
+		}
+		if err := issue.Fix(ctx); err != nil {
+			fmt.Printf("    failed to %s: %v\n", issue.Repair, err)
+			left++
+			continue
+		}
+		fmt.Printf("    fixed: %s\n", issue.Repair)
+		if issue.Source == doctor.SourceServices {
+			recheck = true
+		}
+	}
+
+	fmt.Println()
+	switch {
+	case !doctorFix && fixable > 0:
+		fmt.Printf("%d issue(s) found. Run 'isollm doctor --fix' to repair %d of them.\n", len(issues), fixable)
+	case doctorFix:
+		fmt.Printf("%d issue(s) found, %d fixed.\n", len(issues), len(issues)-left)
+	default:
+		fmt.Printf("%d issue(s) found.\n", len(issues))
+	}
+	if recheck {
+		fmt.Println("Run 'isollm doctor' again to check the tasks now that airyra is running.")
+	}
+
+	if left > 0 {
+		return fmt.Errorf("%d issue(s) left", left)
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ var (
 	downDestroy bool
 	downSave    bool
 	downYes     bool
+	downStop    bool
 )
 
 var downCmd = &cobra.Command{
@@ -29,9 +30,12 @@ This command will:
 5. Save snapshots if --save is specified
 6. Stop or destroy containers based on flags
 7. Run garbage collection on the bare repo
+8. Stop the airyra server if --stop-server is specified
 
 Use --destroy to remove containers after stopping.
 Use --save to snapshot all workers before stopping.
+Use --stop-server to stop the airyra server isollm started, unless
+another project with a running session still uses it.
 Use --yes to skip all confirmations.`,
 	RunE: runDown,
 }
@@ -39,6 +43,7 @@ Use --yes to skip all confirmations.`,
 func init() {
 	downCmd.Flags().BoolVar(&downDestroy, "destroy", false, "Remove containers after stopping")
 	downCmd.Flags().BoolVar(&downSave, "save", false, "Snapshot all workers before stopping")
+	downCmd.Flags().BoolVar(&downStop, "stop-server", false, "Stop the airyra server if no other project uses it")
 	downCmd.Flags().BoolVarP(&downYes, "yes", "y", false, "Skip confirmations")
 
 	rootCmd.AddCommand(downCmd)
@@ -55,7 +60,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	cfg, err := config.Load(projectDir)
+	cfg, err := loadProjectConfig(projectDir)
 	if err != nil {
 		return err
 	}
@@ -64,6 +69,7 @@ func runDown(cmd *cobra.Command, args []string) error {
 		Destroy:       downDestroy,
 		SaveSnapshots: downSave,
 		SkipConfirm:   downYes,
+		StopServer:    downStop,
 	}
 
 	shutdown, err := session.NewShutdown(projectDir, cfg, opts)
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"isollm/internal/config"
+	"isollm/internal/events"
+	"isollm/internal/worker"
+)
+
+var (
+	eventsFollow bool
+	eventsTypes  []string
+	eventsSince  string
+	eventsWorker string
+	eventsTask   string
+	eventsJSON   bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Show the session event history",
+	Long: `Show what happened during sessions: workers created, started, stopped
+and reset, tasks claimed, released, reclaimed, blocked and completed,
+branches salvaged and merged, and sync pushes and pulls.
+
+Events are kept in .isollm/events/ as one JSONL file per day.
+
+--type takes an event type (task.claimed) or a subject (task) and can be
+repeated. --since takes a duration (2h) or a time (2006-01-02 or RFC3339).
+
+Examples:
+  isollm events --since 1h
+  isollm events --type task --worker worker-2
+  isollm events -f --json | jq 'select(.type == "task.blocked")'`,
+	Args: cobra.NoArgs,
+	RunE: runEvents,
+}
+
+func init() {
+	eventsCmd.Flags().BoolVarP(&eventsFollow, "follow", "f", false, "Keep printing new events")
+	eventsCmd.Flags().StringSliceVarP(&eventsTypes, "type", "t", nil, "Only show events of this type or subject")
+	eventsCmd.Flags().StringVar(&eventsSince, "since", "", "Only show events since a duration ago or a time")
+	eventsCmd.Flags().StringVarP(&eventsWorker, "worker", "w", "", "Only show events for a worker")
+	eventsCmd.Flags().StringVar(&eventsTask, "task", "", "Only show events for a task")
+	eventsCmd.Flags().BoolVar(&eventsJSON, "json", false, "Output one JSON object per line")
+
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEvents(cmd *cobra.Command, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectDir, err := config.FindProjectRoot(dir)
+	if err != nil {
+		return err
+	}
+
+	filter := events.Filter{Types: eventsTypes, TaskID: eventsTask}
+	if eventsWorker != "" {
+		filter.Worker = eventsWorker
+		if !strings.HasPrefix(filter.Worker, worker.WorkerPrefix) {
+			filter.Worker = worker.WorkerPrefix + filter.Worker
+		}
+	}
+	if eventsSince != "" {
+		if filter.Since, err = parseSince(eventsSince, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	log := events.New(projectDir)
+	show := printEvent
+	if eventsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		show = func(e events.Event) error { return enc.Encode(e) }
+	}
+
+	history, pos, err := log.ReadPosition(filter)
+	if err != nil {
+		return err
+	}
+	for _, e := range history {
+		if err := show(e); err != nil {
+			return err
+		}
+	}
+
+	if !eventsFollow {
+		if len(history) == 0 && !eventsJSON {
+			fmt.Println("No events")
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Carry on where the history ended, so events written meanwhile are
+	// neither lost nor shown twice
+	return log.FollowFrom(ctx, filter, pos, time.Second, show)
+}
+
+// parseSince reads --since as a duration before now, a date or an
+// RFC3339 time
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: want a duration (2h) or a time (2006-01-02 or RFC3339)", s)
+}
+
+// printEvent prints an event as one line
+func printEvent(e events.Event) error {
+	subject := e.Worker
+	if subject == "" {
+		subject = "-"
+	}
+	detail := e.TaskID
+	if e.Message != "" {
+		if detail != "" {
+			detail += "  "
+		}
+		detail += e.Message
+	} else if e.TaskID == "" && e.Branch != "" {
+		detail = e.Branch
+	}
+
+	_, err := fmt.Printf("%s  %-16s %-10s %s\n",
+		e.Time.Local().Format("2006-01-02 15:04:05"), e.Type, subject, detail)
+	return err
+}
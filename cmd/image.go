@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage the cached worker image",
+	Long: `Manage the project's cached worker image.
+
+The first worker is provisioned from the configured image and published as
+an LXD image keyed on a hash of the image, provision block and setup
+script. Later workers are copied from it instead of being provisioned
+again. Changing any of those settings builds a new image automatically.`,
+}
+
+// imageRebuildCmd drops the cached image
+var imageRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Invalidate the cached worker image",
+	Long: `Delete the project's cached worker image.
+
+The next worker is provisioned from scratch and cached again. Use this when
+something the hash does not cover has changed, such as a new release of the
+packages or tools installed by setup_script.`,
+	Args: cobra.NoArgs,
+	RunE: runImageRebuild,
+}
+
+func init() {
+	rootCmd.AddCommand(imageCmd)
+	imageCmd.AddCommand(imageRebuildCmd)
+}
+
+func runImageRebuild(cmd *cobra.Command, args []string) error {
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	removed, err := mgr.RemoveCachedImages()
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		fmt.Println("No cached image")
+		return nil
+	}
+	for _, alias := range removed {
+		fmt.Printf("Removed %s\n", alias)
+	}
+	fmt.Println("The next worker will be provisioned from scratch and cached again")
+	return nil
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"isollm/internal/airyra"
+	"isollm/internal/barerepo"
+	"isollm/internal/config"
+	"isollm/internal/events"
+	"isollm/internal/merge"
+)
+
+var (
+	mergeStrategy string
+	mergeVerify   string
+	mergeDryRun   bool
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge [task-id...]",
+	Short: "Land completed task branches onto the base branch",
+	Long: `Merge completed task branches from the bare repo into the base branch.
+
+Without arguments, every task branch whose task is done in airyra is merged.
+Tasks are landed one at a time, each on top of the previous one:
+
+1. The branch is merged, rebased or squashed onto git.base_branch in a
+   scratch worktree (your checkout is not touched)
+2. The verification command (merge.verify in isollm.yaml) runs there
+3. On success the host base branch is fast-forwarded and the task branch
+   is deleted from the bare repo
+
+Branches that conflict or fail verification are reported and left alone.
+The updated base branch is pushed to the bare repo so workers fork from it.
+
+Examples:
+  isollm merge                       # Merge all done tasks
+  isollm merge ar-a1b2 ar-c3d4       # Merge specific tasks
+  isollm merge --strategy squash     # One commit per task
+  isollm merge --dry-run             # Show what would conflict`,
+	RunE: runMerge,
+}
+
+func init() {
+	mergeCmd.Flags().StringVar(&mergeStrategy, "strategy", "", "Merge strategy: merge, rebase, squash (default from merge.strategy)")
+	mergeCmd.Flags().StringVar(&mergeVerify, "verify", "", "Verification command (default from merge.verify)")
+	mergeCmd.Flags().BoolVar(&mergeDryRun, "dry-run", false, "Check which branches apply cleanly without changing anything")
+
+	rootCmd.AddCommand(mergeCmd)
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectDir, err := config.FindProjectRoot(dir)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+
+	strategyName := cfg.Merge.Strategy
+	if mergeStrategy != "" {
+		strategyName = mergeStrategy
+	}
+	strategy, err := merge.ParseStrategy(strategyName)
+	if err != nil {
+		return err
+	}
+
+	verify := cfg.Merge.Verify
+	if mergeVerify != "" {
+		verify = mergeVerify
+	}
+
+	barePath, err := barerepo.GetMountPath(cfg.Project)
+	if err != nil {
+		return err
+	}
+	if !barerepo.Exists(barePath) {
+		return fmt.Errorf("bare repo does not exist: %s\nRun 'isollm up' first", barePath)
+	}
+	repo := barerepo.New(barePath)
+
+	client, err := airyra.NewClientFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tasks, err := mergeCandidates(ctx, cfg, client, repo, args)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		fmt.Println("No completed task branches to merge")
+		return nil
+	}
+
+	if mergeDryRun {
+		fmt.Printf("Dry run: checking %d task branch(es) against %s (%s)\n", len(tasks), cfg.Git.BaseBranch, strategy)
+	} else {
+		fmt.Printf("Merging %d task branch(es) into %s (%s)\n", len(tasks), cfg.Git.BaseBranch, strategy)
+	}
+	fmt.Println()
+
+	m := merge.New(projectDir, cfg.Git.BaseBranch, repo, merge.Options{
+		Strategy: strategy,
+		Verify:   verify,
+		DryRun:   mergeDryRun,
+	})
+
+	results, runErr := m.Run(tasks)
+	failed := printMergeResults(results)
+
+	eventLog := events.New(projectDir)
+	for _, r := range results {
+		if r.Outcome == merge.OutcomeMerged {
+			eventLog.Emit(events.Event{
+				Type:    events.BranchMerged,
+				TaskID:  r.Task.ID,
+				Branch:  r.Task.Branch,
+				Message: fmt.Sprintf("into %s (%s)", cfg.Git.BaseBranch, strategy),
+			})
+		}
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d task branch(es) could not be merged", failed, len(results))
+	}
+	return nil
+}
+
+// mergeCandidates returns the task branches to merge: the given task IDs,
+// or every task branch in the bare repo whose task is done
+func mergeCandidates(ctx context.Context, cfg *config.Config, client airyra.TaskClient, repo *barerepo.BareRepo, ids []string) ([]merge.Task, error) {
+	explicit := len(ids) > 0
+	if !explicit {
+		branches, err := repo.ListTaskBranches()
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range branches {
+			ids = append(ids, b.TaskID)
+		}
+	}
+
+	var tasks []merge.Task
+	for _, id := range ids {
+		task, err := client.GetTask(ctx, id)
+		if err != nil {
+			if airyra.IsTaskNotFound(err) {
+				fmt.Printf("  - %s: not found in airyra, skipping\n", id)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get task %s: %s", id, airyra.FormatError(err))
+		}
+		if task.Status != airyra.StatusDone {
+			if explicit {
+				fmt.Printf("  - %s: %s, skipping (only done tasks are merged)\n", id, task.Status)
+			}
+			continue
+		}
+		tasks = append(tasks, merge.Task{
+			ID:     task.ID,
+			Title:  task.Title,
+			Branch: cfg.Git.BranchPrefix + task.ID,
+		})
+	}
+
+	return tasks, nil
+}
+
+// printMergeResults prints one line per task and returns how many failed
+func printMergeResults(results []merge.Result) int {
+	failed := 0
+	for _, r := range results {
+		switch r.Outcome {
+		case merge.OutcomeMerged:
+			fmt.Printf("  ✓ %s  merged  %s\n", r.Task.ID, r.Task.Title)
+			if r.Err != nil {
+				fmt.Printf("      Warning: %v\n", r.Err)
+			}
+		case merge.OutcomeClean:
+			fmt.Printf("  ✓ %s  applies cleanly  %s\n", r.Task.ID, r.Task.Title)
+		case merge.OutcomeConflict:
+			failed++
+			fmt.Printf("  ✗ %s  conflict  %s\n", r.Task.ID, r.Task.Title)
+			for _, path := range r.Conflicts {
+				fmt.Printf("      %s\n", path)
+			}
+		case merge.OutcomeVerifyFailed:
+			failed++
+			fmt.Printf("  ✗ %s  verification failed  %s\n", r.Task.ID, r.Task.Title)
+			for _, line := range tailLines(r.Output, 20) {
+				fmt.Printf("      %s\n", line)
+			}
+		default:
+			failed++
+			fmt.Printf("  ✗ %s  failed: %v\n", r.Task.ID, r.Err)
+		}
+	}
+	return failed
+}
+
+// tailLines returns the last n lines of s
+func tailLines(s string, n int) []string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"isollm/internal/airyra"
+	"isollm/internal/barerepo"
+	"isollm/internal/events"
+	"isollm/internal/report"
+	"isollm/internal/state"
+)
+
+var (
+	reportFormat string
+	reportOutput string
+	reportSince  string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarise what the workers did",
+	Long: `Write a report of the project's tasks: status, who worked on them and
+when they were claimed and completed, with the commits and diffstat of
+each task branch and the history from the event log.
+
+The report is markdown by default; --format html writes a standalone page
+and --format json an export for other tools. --since takes a duration (8h)
+or a time (2006-01-02 or RFC3339) and leaves out tasks untouched since.
+
+Examples:
+  isollm report
+  isollm report --since 8h -o session.md
+  isollm report --format html -o report.html
+  isollm report --format json | jq '.tasks[] | select(.status == "done")'`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportFormat, "format", "f", "markdown", "Output format: markdown, html or json")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	reportCmd.Flags().StringVar(&reportSince, "since", "", "Only include tasks touched since a duration ago or a time")
+
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	format, err := report.ParseFormat(reportFormat)
+	if err != nil {
+		return err
+	}
+
+	projectDir, err := findProjectDir()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+
+	client, err := airyra.NewClientFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	workers, err := state.New(projectDir).LoadAllWorkers()
+	if err != nil {
+		return fmt.Errorf("failed to read worker assignments: %w", err)
+	}
+
+	src := report.Sources{
+		Project:    cfg.Project,
+		BaseBranch: cfg.Git.BaseBranch,
+		Tasks:      client,
+		Events:     events.New(projectDir),
+		Workers:    workers.Workers,
+	}
+	if reportSince != "" {
+		if src.Since, err = parseSince(reportSince, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	barePath, err := barerepo.GetMountPath(cfg.Project)
+	if err != nil {
+		return err
+	}
+	if barerepo.Exists(barePath) {
+		src.Repo = barerepo.New(barePath)
+	}
+
+	r, err := report.Build(ctx, src)
+	if err != nil {
+		return fmt.Errorf("failed to build report: %s", airyra.FormatError(err))
+	}
+
+	var out io.Writer = os.Stdout
+	if reportOutput != "" {
+		f, err := os.Create(reportOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", reportOutput, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := report.Write(out, r, format); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if reportOutput != "" {
+		fmt.Printf("Wrote %s report of %d tasks to %s\n", format, len(r.Tasks), reportOutput)
+	}
+	return nil
+}
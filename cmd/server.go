@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"isollm/internal/airyra"
+	"isollm/internal/config"
+	"isollm/internal/logs"
+)
+
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Manage the airyra server",
+	Long: `Start, stop and inspect the airyra server isollm runs for this project.
+
+The server runs detached from the terminal, with its PID in
+.isollm/airyra.pid and its output appended to .isollm/airyra.log.
+'isollm up' starts it when nothing answers on the configured address.`,
+}
+
+var serverStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the airyra server",
+	RunE:  runServerStart,
+}
+
+var serverStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the airyra server started by this project",
+	RunE:  runServerStop,
+}
+
+var serverRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the airyra server",
+	RunE:  runServerRestart,
+}
+
+var serverLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the airyra server log",
+	RunE:  runServerLogs,
+}
+
+var (
+	serverLogsFollow bool
+	serverLogsLines  int
+)
+
+func init() {
+	serverLogsCmd.Flags().BoolVarP(&serverLogsFollow, "follow", "f", false, "Stream new output as it is written")
+	serverLogsCmd.Flags().IntVarP(&serverLogsLines, "lines", "n", 100, "Lines to show from the end of the log (0 for all)")
+
+	serverCmd.AddCommand(serverStartCmd)
+	serverCmd.AddCommand(serverStopCmd)
+	serverCmd.AddCommand(serverRestartCmd)
+	serverCmd.AddCommand(serverLogsCmd)
+	rootCmd.AddCommand(serverCmd)
+}
+
+// loadServer returns the managed airyra server of the current project
+func loadServer() (*airyra.Server, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectDir, err := config.FindProjectRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	server := airyra.NewServer(projectDir, cfg.Airyra.Host, cfg.Airyra.Port)
+	server.Project = cfg.Airyra.Project
+	return server, nil
+}
+
+func runServerStart(cmd *cobra.Command, args []string) error {
+	server, err := loadServer()
+	if err != nil {
+		return err
+	}
+	return startServer(server)
+}
+
+func runServerStop(cmd *cobra.Command, args []string) error {
+	server, err := loadServer()
+	if err != nil {
+		return err
+	}
+
+	if err := stopServer(server); err != nil {
+		if errors.Is(err, airyra.ErrNotManaged) && !errors.Is(server.Probe(context.Background()), airyra.ErrServerNotRunning) {
+			return fmt.Errorf("%w; stop the server on %s by hand", err, server.Addr())
+		}
+		return err
+	}
+	return nil
+}
+
+func runServerRestart(cmd *cobra.Command, args []string) error {
+	server, err := loadServer()
+	if err != nil {
+		return err
+	}
+
+	if err := stopServer(server); err != nil && !errors.Is(err, airyra.ErrNotManaged) {
+		return err
+	}
+	return startServer(server)
+}
+
+func runServerLogs(cmd *cobra.Command, args []string) error {
+	server, err := loadServer()
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(server.LogPath())
+	if os.IsNotExist(err) {
+		fmt.Println("No airyra server log yet")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read airyra log: %w", err)
+	}
+
+	text, err := logs.Tail(server.LogPath(), serverLogsLines)
+	if err != nil {
+		return err
+	}
+	if text != "" {
+		fmt.Println(text)
+	}
+
+	if !serverLogsFollow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return logs.Follow(ctx, server.LogPath(), info.Size(), os.Stdout, 500*time.Millisecond)
+}
+
+// startServer starts server and waits for it to answer
+func startServer(server *airyra.Server) error {
+	if pid := server.PID(); pid != 0 {
+		if port := server.ManagedPort(); port != 0 {
+			server.Port = port
+		}
+		fmt.Printf("airyra server already running on %s (pid %d)\n", server.Addr(), pid)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), airyra.DefaultStartTimeout)
+	defer cancel()
+	if err := server.Probe(ctx); err == nil {
+		fmt.Printf("airyra server already running on %s (not started by isollm)\n", server.Addr())
+		return nil
+	} else if !errors.Is(err, airyra.ErrServerNotRunning) {
+		return withPortHint(err)
+	}
+
+	fmt.Printf("Starting airyra server on %s... ", server.Addr())
+	if err := server.EnsureRunning(ctx); err != nil {
+		fmt.Println("failed")
+		return err
+	}
+	fmt.Printf("ok (pid %d)\n", server.PID())
+	fmt.Printf("Log: %s\n", server.LogPath())
+	return nil
+}
+
+// stopServer stops server, giving it DefaultStopTimeout to exit
+func stopServer(server *airyra.Server) error {
+	pid := server.PID()
+
+	ctx, cancel := context.WithTimeout(context.Background(), airyra.DefaultStopTimeout)
+	defer cancel()
+	if err := server.Stop(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("Stopped airyra server (pid %d)\n", pid)
+	return nil
+}
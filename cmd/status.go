@@ -5,18 +5,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
 	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"isollm/internal/config"
+	"isollm/internal/dashboard"
 	"isollm/internal/status"
+	"isollm/internal/worker"
 )
 
 var (
-	statusBrief bool
-	statusJSON  bool
+	statusBrief    bool
+	statusJSON     bool
+	statusWatch    bool
+	statusInterval time.Duration
 )
 
 var statusCmd = &cobra.Command{
@@ -27,13 +35,20 @@ var statusCmd = &cobra.Command{
 Output formats:
   (default)  Full dashboard with detailed worker list
   --brief    One-line summary
-  --json     Machine-readable JSON output`,
+  --json     Machine-readable JSON output
+  --watch    Live dashboard, refreshed every --interval
+
+In watch mode, select a worker with the arrow keys (or j/k) and press
+r to release its task, x to reset it to its clean snapshot, or s to open
+a shell in it. Inside zellij the shell opens in a floating pane.`,
 	RunE: runStatus,
 }
 
 func init() {
 	statusCmd.Flags().BoolVarP(&statusBrief, "brief", "b", false, "Show one-line summary")
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output as JSON")
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Live dashboard with worker actions")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", dashboard.DefaultInterval, "Refresh interval for --watch")
 
 	rootCmd.AddCommand(statusCmd)
 }
@@ -49,7 +64,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	cfg, err := config.Load(projectDir)
+	cfg, err := loadProjectConfig(projectDir)
 	if err != nil {
 		return err
 	}
@@ -59,6 +74,10 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create status collector: %w", err)
 	}
 
+	if statusWatch {
+		return runStatusWatch(collector)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -78,12 +97,65 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	return printStatusFull(s)
 }
 
+// watchOverlapInterval is how often the dashboard looks for task branches
+// touching the same files, which test-merges every pair of them
+const watchOverlapInterval = time.Minute
+
+// runStatusWatch shows the live dashboard until q or Ctrl-C
+func runStatusWatch(collector *status.Collector) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Without a terminal the dashboard still refreshes, without keys
+	term, _ := dashboard.OpenTerminal()
+
+	collector.SetOverlapInterval(watchOverlapInterval)
+
+	d := dashboard.New(collector, &dashboardActions{mgr: collector.GetManager()}, term, os.Stdin, os.Stdout)
+	d.SetInterval(statusInterval)
+	return d.Run(ctx)
+}
+
+// dashboardActions performs the dashboard's worker actions
+type dashboardActions struct {
+	mgr *worker.Manager
+}
+
+func (a *dashboardActions) Release(ctx context.Context, name string) error {
+	return a.mgr.ReleaseWorkerTask(ctx, name)
+}
+
+func (a *dashboardActions) Reset(ctx context.Context, name string) error {
+	if task, err := a.mgr.GetTask(name); err == nil && task != nil && task.TaskID != "" {
+		if err := a.mgr.ReleaseWorkerTask(ctx, name); err != nil {
+			return err
+		}
+	}
+	return a.mgr.Reset(name)
+}
+
+func (a *dashboardActions) Shell(name string) error {
+	if status.IsInZellijSession() {
+		// Leave the dashboard pane as it is and open the shell beside it
+		return exec.Command("zellij", "run", "--floating", "--close-on-exit",
+			"--name", name, "--", "isollm", "worker", "shell", name).Run()
+	}
+	return a.mgr.Shell(name)
+}
+
 func printStatusFull(s *status.Status) error {
 	// Header
 	fmt.Printf("Project: %s\n", s.Project)
 	fmt.Println("─────────────────────────────────────────────────")
 	fmt.Println()
 
+	// Session section
+	if s.Session != nil {
+		fmt.Printf("Session: %s, started %s ago\n",
+			strings.ReplaceAll(s.Session.Status, "_", " "), formatDuration(time.Since(s.Session.StartedAt)))
+		fmt.Println()
+	}
+
 	// Services section
 	fmt.Print("Services: ")
 	airyraSymbol := serviceSymbol(s.Services.Airyra.Running)
@@ -109,6 +181,9 @@ func printStatusFull(s *status.Status) error {
 	if s.Sync.TotalBranches > 0 {
 		fmt.Printf("      └─ %d task branches in bare repo\n", s.Sync.TotalBranches)
 	}
+	for _, o := range s.Sync.Conflicts() {
+		fmt.Printf("      ⚠ %s ↔ %s will conflict: %s\n", o.BranchA, o.BranchB, strings.Join(o.Paths, ", "))
+	}
 	fmt.Println()
 
 	// Workers section
@@ -127,7 +202,9 @@ func printStatusFull(s *status.Status) error {
 			}
 
 			taskInfo := "-"
-			if worker.TaskID != "" {
+			if worker.Error != "" {
+				taskInfo = "provisioning failed"
+			} else if worker.TaskID != "" {
 				if worker.TaskTitle != "" {
 					taskInfo = fmt.Sprintf("%s: %s", worker.TaskID, truncate(worker.TaskTitle, 30))
 				} else {
@@ -199,6 +276,8 @@ func workerSymbol(status string) string {
 		return "●"
 	case "STOPPED":
 		return "○"
+	case "ERROR":
+		return "✗"
 	default:
 		return "?"
 	}
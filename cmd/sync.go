@@ -1,13 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"isollm/internal/airyra"
 	"isollm/internal/barerepo"
 	"isollm/internal/config"
+	"isollm/internal/events"
+	"isollm/internal/status"
 )
 
 var syncCmd = &cobra.Command{
@@ -52,7 +58,7 @@ func runSyncStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	cfg, err := config.Load(projectDir)
+	cfg, err := loadProjectConfig(projectDir)
 	if err != nil {
 		return err
 	}
@@ -108,9 +114,56 @@ func runSyncStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	printBranchOverlaps(cfg, repo, branches)
+
 	return nil
 }
 
+// printBranchOverlaps reports in-progress task branches that touch the same
+// files, flagging the pairs that will not merge cleanly
+func printBranchOverlaps(cfg *config.Config, repo *barerepo.BareRepo, branches []barerepo.BranchInfo) {
+	if len(branches) < 2 {
+		return
+	}
+
+	// Without airyra every task branch is compared
+	var client airyra.TaskClient
+	if c, err := airyra.NewClientFromConfig(cfg); err == nil {
+		client = c
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	active := status.ActiveBranches(ctx, client, branches)
+	overlaps, err := repo.FindOverlaps(active, cfg.Git.BaseBranch)
+	if err != nil {
+		fmt.Printf("  Could not check for overlapping changes: %v\n", err)
+		return
+	}
+	if len(overlaps) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Overlapping work:")
+	conflicts := 0
+	for _, o := range overlaps {
+		if o.Conflict {
+			conflicts++
+			fmt.Printf("  ⚠ %s ↔ %s will conflict\n", o.BranchA, o.BranchB)
+		} else {
+			fmt.Printf("  • %s ↔ %s touch the same files (merge cleanly)\n", o.BranchA, o.BranchB)
+		}
+		fmt.Printf("      %s\n", strings.Join(o.Paths, ", "))
+	}
+	if conflicts > 0 {
+		fmt.Println()
+		fmt.Println("  Consider pausing one of the conflicting tasks, or merging one")
+		fmt.Println("  first so the other can rebase onto it.")
+	}
+}
+
 func runSyncPull(cmd *cobra.Command, args []string) error {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -122,7 +175,7 @@ func runSyncPull(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	cfg, err := config.Load(projectDir)
+	cfg, err := loadProjectConfig(projectDir)
 	if err != nil {
 		return err
 	}
@@ -149,6 +202,11 @@ func runSyncPull(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	events.New(projectDir).Emit(events.Event{
+		Type:    events.SyncPulled,
+		Message: fmt.Sprintf("%d task branches", len(branches)),
+	})
+
 	if len(branches) == 0 {
 		fmt.Println("No task branches to fetch")
 	} else {
@@ -157,7 +215,7 @@ func runSyncPull(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s\n", branch.Name)
 		}
 		fmt.Println()
-		fmt.Println("Merge with standard git:")
+		fmt.Println("Merge done tasks with 'isollm merge', or with standard git:")
 		fmt.Printf("  git merge %s<task-id>\n", barerepo.BranchPrefix)
 	}
 
@@ -175,7 +233,7 @@ func runSyncPush(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	cfg, err := config.Load(projectDir)
+	cfg, err := loadProjectConfig(projectDir)
 	if err != nil {
 		return err
 	}
@@ -195,6 +253,7 @@ func runSyncPush(cmd *cobra.Command, args []string) error {
 	if err := repo.PushToBare(projectDir, cfg.Git.BaseBranch); err != nil {
 		return err
 	}
+	events.New(projectDir).Emit(events.Event{Type: events.SyncPushed, Branch: cfg.Git.BaseBranch})
 
 	fmt.Println("Done. Workers can now pull your changes.")
 
@@ -3,8 +3,12 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -14,7 +18,15 @@ import (
 	sdk "airyra/pkg/airyra"
 
 	"isollm/internal/airyra"
+	"isollm/internal/claude"
 	"isollm/internal/config"
+	"isollm/internal/notes"
+	"isollm/internal/plans"
+	"isollm/internal/state"
+	"isollm/internal/taskspec"
+	"isollm/internal/templates"
+	"isollm/internal/verify"
+	"isollm/internal/worker"
 )
 
 var taskCmd = &cobra.Command{
@@ -31,6 +43,11 @@ var (
 	addPriority    string
 	addDescription string
 	addDependsOn   string
+	addPlan        bool
+	addTemplate    string
+	addSets        []string
+	addEach        []string
+	addDryRun      bool
 )
 
 var taskAddCmd = &cobra.Command{
@@ -41,12 +58,36 @@ var taskAddCmd = &cobra.Command{
 Examples:
   isollm task add "Implement login endpoint"
   isollm task add "Fix critical bug" -p critical
-  isollm task add "Write tests" --depends-on ar-abc1`,
-	Args: cobra.ExactArgs(1),
+  isollm task add "Write tests" --depends-on ar-abc1
+  isollm task add "Build the billing system" --plan
+  isollm task add --template lint --set pkg=internal/cache
+  isollm task add --template lint --each 'pkg=internal/*/' --dry-run
+
+With --plan the worker that picks the task up splits it into subtasks
+instead of implementing it. The task is completed once all of them are.
+
+--template adds tasks from a template in isollm.yaml instead of a title.
+--set gives a parameter a value; --each takes a glob (relative to the
+project root, a trailing / matches only directories) or a comma separated
+list and adds one task per value.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runTaskAdd,
 }
 
 func runTaskAdd(cmd *cobra.Command, args []string) error {
+	if addTemplate != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("a template gives the title, leave it out with --template")
+		}
+		return runTaskAddTemplate()
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("a title is required (or use --template)")
+	}
+	if len(addSets) > 0 || len(addEach) > 0 || addDryRun {
+		return fmt.Errorf("--set, --each and --dry-run need --template")
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -57,6 +98,15 @@ func runTaskAdd(cmd *cobra.Command, args []string) error {
 
 	title := args[0]
 
+	var planStore *plans.Store
+	if addPlan {
+		projectDir, err := findProjectDir()
+		if err != nil {
+			return err
+		}
+		planStore = plans.New(projectDir)
+	}
+
 	// Build options
 	var opts []sdk.CreateTaskOption
 
@@ -86,16 +136,150 @@ func runTaskAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if planStore != nil {
+		// Unmarked, a worker would implement the task instead of planning it
+		if err := planStore.Mark(task.ID); err != nil {
+			client.DeleteTask(ctx, task.ID)
+			return fmt.Errorf("failed to mark task for planning: %w", err)
+		}
+	}
+
 	fmt.Printf("Created task: %s\n", task.ID)
 	fmt.Printf("  Title: %s\n", task.Title)
 	fmt.Printf("  Priority: %s\n", airyra.PriorityToString(task.Priority))
 	if addDependsOn != "" {
 		fmt.Printf("  Depends on: %s\n", addDependsOn)
 	}
+	if addPlan {
+		fmt.Println("  Plan: a worker will split it into subtasks")
+	}
+
+	return nil
+}
+
+// runTaskAddTemplate adds the tasks a template expands to, all or none
+func runTaskAddTemplate() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	projectDir, err := findProjectDir()
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := templates.Get(cfg, addTemplate)
+	if err != nil {
+		return err
+	}
+	sets, err := templates.ParseSets(addSets)
+	if err != nil {
+		return err
+	}
+	each, err := templates.ParseSets(addEach)
+	if err != nil {
+		return err
+	}
+
+	spec, err := templates.Expand(addTemplate, tmpl, sets, each, projectDir)
+	if err != nil {
+		return err
+	}
+	for i := range spec.Tasks {
+		if addPriority != "" {
+			spec.Tasks[i].Priority = addPriority
+		}
+		if addDescription != "" {
+			spec.Tasks[i].Description = addDescription
+		}
+	}
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	client, err := getAiryraClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := taskspec.Import(ctx, client, nil, "template:"+addTemplate, spec, addDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to add tasks from template %s: %s", addTemplate, airyra.FormatError(err))
+	}
+
+	if !addDryRun {
+		if err := recordVerifyCommands(projectDir, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		planStore := plans.New(projectDir)
+		for _, t := range result.Tasks {
+			if addDependsOn != "" {
+				if err := client.AddDependency(ctx, t.TaskID, addDependsOn); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to add dependency of %s: %s\n", t.TaskID, airyra.FormatError(err))
+				}
+			}
+			if addPlan {
+				if err := planStore.Mark(t.TaskID); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to mark %s for planning: %v\n", t.TaskID, err)
+				}
+			}
+		}
+	}
 
+	printImportResult(result)
+	if addDryRun {
+		fmt.Printf("\nDry run: would create %d tasks from template %s\n", result.Created(), addTemplate)
+	} else {
+		fmt.Printf("\nCreated %d tasks from template %s\n", result.Created(), addTemplate)
+	}
 	return nil
 }
 
+// --- task templates ---
+
+var taskTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List the task templates in isollm.yaml",
+	Args:  cobra.NoArgs,
+	RunE:  runTaskTemplates,
+}
+
+func runTaskTemplates(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if taskJSON {
+		return printJSON(cfg.Templates)
+	}
+
+	if len(cfg.Templates) == 0 {
+		fmt.Println("No templates. Add them under templates: in isollm.yaml.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range templates.Names(cfg) {
+		t := cfg.Templates[name]
+		var each []string
+		for _, param := range slices.Sorted(maps.Keys(t.Each)) {
+			each = append(each, param+"="+t.Each[param])
+		}
+		detail := ""
+		if len(each) > 0 {
+			detail = "each " + strings.Join(each, " ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, t.Title, detail)
+	}
+	return w.Flush()
+}
+
 // --- task list ---
 
 var (
@@ -320,8 +504,712 @@ func runTaskClear(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// --- task show / edit ---
+
+// taskJSON switches the task commands to JSON output
+var taskJSON bool
+
+// taskDetail is everything 'task show' knows about a task
+type taskDetail struct {
+	Task         *airyra.Task        `json:"task"`
+	Dependencies []airyra.Dependency `json:"dependencies"`
+	Notes        []notes.Note        `json:"notes"`
+}
+
+var taskShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a task in full",
+	Long: `Show a task's status, priority, description, dependencies and notes
+(blocker questions and answers, verification failures, reclaims).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskShow,
+}
+
+func runTaskShow(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := getAiryraClient()
+	if err != nil {
+		return err
+	}
+
+	projectDir, err := findProjectDir()
+	if err != nil {
+		return err
+	}
+
+	task, err := client.GetTask(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get task %s: %s", args[0], airyra.FormatError(err))
+	}
+
+	deps, err := client.ListDependencies(ctx, task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list dependencies: %s", airyra.FormatError(err))
+	}
+
+	taskNotes, err := notes.New(projectDir).List(task.ID)
+	if err != nil {
+		return err
+	}
+
+	if taskJSON {
+		return printJSON(taskDetail{Task: task, Dependencies: deps, Notes: taskNotes})
+	}
+
+	fmt.Printf("%s  %s\n", task.ID, task.Title)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	status := formatStatus(task.Status)
+	if task.ClaimedBy != nil {
+		status += " -> " + *task.ClaimedBy
+		if task.ClaimedAt != nil {
+			status += fmt.Sprintf(" (%v)", time.Since(*task.ClaimedAt).Round(time.Minute))
+		}
+	}
+	fmt.Fprintf(w, "  Status:\t%s\n", status)
+	fmt.Fprintf(w, "  Priority:\t%s\n", airyra.PriorityToString(task.Priority))
+	fmt.Fprintf(w, "  Created:\t%s\n", task.CreatedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "  Updated:\t%s\n", task.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	if len(deps) > 0 {
+		fmt.Fprintf(w, "  Depends on:\t%s\n", strings.Join(dependencyLabels(ctx, client, deps), ", "))
+	}
+	w.Flush()
+
+	if task.Description != "" {
+		fmt.Println()
+		fmt.Println(task.Description)
+	}
+
+	if len(taskNotes) > 0 {
+		fmt.Println()
+		fmt.Println("Notes:")
+		for _, n := range taskNotes {
+			fmt.Printf("  %s  %s", n.Time.Local().Format("2006-01-02 15:04"), n.Kind)
+			if n.Author != "" {
+				fmt.Printf(" (%s)", n.Author)
+			}
+			fmt.Println()
+			for _, line := range strings.Split(strings.TrimSpace(n.Text), "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+	return nil
+}
+
+// dependencyLabels describes the tasks a task depends on as "id (status)"
+func dependencyLabels(ctx context.Context, client *airyra.Client, deps []airyra.Dependency) []string {
+	labels := make([]string, 0, len(deps))
+	for _, d := range deps {
+		label := d.ParentID
+		if parent, err := client.GetTask(ctx, d.ParentID); err == nil {
+			label += " (" + string(parent.Status) + ")"
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+var (
+	editTitle       string
+	editDescription string
+	editPriority    string
+)
+
+var taskEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Change a task's title, description or priority",
+	Long: `Change a task's title, description or priority. Only the flags given
+are changed.
+
+Examples:
+  isollm task edit ar-abc1 -p high
+  isollm task edit ar-abc1 --title "Add login endpoint" -D "POST /login returning a JWT"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskEdit,
+}
+
+func runTaskEdit(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var update airyra.TaskUpdate
+	flags := cmd.Flags()
+	if flags.Changed("title") {
+		if strings.TrimSpace(editTitle) == "" {
+			return fmt.Errorf("--title cannot be empty")
+		}
+		update.Title = &editTitle
+	}
+	if flags.Changed("description") {
+		update.Description = &editDescription
+	}
+	if flags.Changed("priority") {
+		p, err := airyra.PriorityFromString(editPriority)
+		if err != nil {
+			return err
+		}
+		update.Priority = &p
+	}
+	if update.Title == nil && update.Description == nil && update.Priority == nil {
+		return fmt.Errorf("nothing to change: use --title, --description or --priority")
+	}
+
+	client, err := getAiryraClient()
+	if err != nil {
+		return err
+	}
+
+	task, err := client.UpdateTask(ctx, args[0], update)
+	if err != nil {
+		return fmt.Errorf("failed to edit %s: %s", args[0], airyra.FormatError(err))
+	}
+
+	return printTaskResult("Updated", args[0], task)
+}
+
+// --- task release / reopen / delete ---
+
+var releaseForce bool
+
+var taskReleaseCmd = &cobra.Command{
+	Use:   "release <id>",
+	Short: "Give a task back to the queue",
+	Long: `Release a claimed task so it can be picked up again. A task held by a
+worker is released as that worker, and the worker is freed.
+
+Use --force to release a task claimed by another agent.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskRelease,
+}
+
+func runTaskRelease(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	task, err := mgr.ReleaseTask(ctx, args[0], releaseForce)
+	if err != nil {
+		return fmt.Errorf("failed to release %s: %s", args[0], airyra.FormatError(err))
+	}
+
+	return printTaskResult("Released", args[0], task)
+}
+
+var taskReopenCmd = &cobra.Command{
+	Use:   "reopen <id>",
+	Short: "Put a blocked, in-progress or done task back in the queue",
+	Long: `Reopen a task so it is worked on again from the queue. The worker
+holding it, if any, is freed. Its branch is kept, so the next worker
+continues from the work already pushed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskReopen,
+}
+
+func runTaskReopen(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	task, err := mgr.ReopenTask(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to reopen %s: %s", args[0], airyra.FormatError(err))
+	}
+
+	return printTaskResult("Reopened", args[0], task)
+}
+
+var taskDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Delete a task",
+	Long: `Delete a task from the queue along with its notes. The worker holding
+it, if any, is freed. Its branch is left for 'isollm merge' or cleanup.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskDelete,
+}
+
+func runTaskDelete(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	if err := mgr.DeleteTask(ctx, args[0]); err != nil {
+		return fmt.Errorf("failed to delete %s: %s", args[0], airyra.FormatError(err))
+	}
+
+	if taskJSON {
+		return printJSON(map[string]string{"deleted": args[0]})
+	}
+	fmt.Printf("Deleted %s\n", args[0])
+	return nil
+}
+
+// --- task deps ---
+
+var taskDepsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Manage task dependencies",
+	Long: `Manage task dependencies. A task is not ready until every task it
+depends on is done.`,
+}
+
+var taskDepsAddCmd = &cobra.Command{
+	Use:   "add <id> <depends-on>",
+	Short: "Make a task depend on another",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runTaskDepsAdd,
+}
+
+var taskDepsRemoveCmd = &cobra.Command{
+	Use:     "remove <id> <depends-on>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a dependency",
+	Args:    cobra.ExactArgs(2),
+	RunE:    runTaskDepsRemove,
+}
+
+var taskDepsListCmd = &cobra.Command{
+	Use:     "list <id>",
+	Aliases: []string{"ls"},
+	Short:   "List the tasks a task depends on",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runTaskDepsList,
+}
+
+func runTaskDepsAdd(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := getAiryraClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.AddDependency(ctx, args[0], args[1]); err != nil {
+		return fmt.Errorf("failed to add dependency: %s", airyra.FormatError(err))
+	}
+
+	if taskJSON {
+		return printJSON(airyra.Dependency{ChildID: args[0], ParentID: args[1]})
+	}
+	fmt.Printf("%s now depends on %s\n", args[0], args[1])
+	return nil
+}
+
+func runTaskDepsRemove(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := getAiryraClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.RemoveDependency(ctx, args[0], args[1]); err != nil {
+		return fmt.Errorf("failed to remove dependency: %s", airyra.FormatError(err))
+	}
+
+	if taskJSON {
+		return printJSON(airyra.Dependency{ChildID: args[0], ParentID: args[1]})
+	}
+	fmt.Printf("%s no longer depends on %s\n", args[0], args[1])
+	return nil
+}
+
+func runTaskDepsList(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := getAiryraClient()
+	if err != nil {
+		return err
+	}
+
+	deps, err := client.ListDependencies(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to list dependencies: %s", airyra.FormatError(err))
+	}
+
+	if taskJSON {
+		if deps == nil {
+			deps = []airyra.Dependency{}
+		}
+		return printJSON(deps)
+	}
+
+	if len(deps) == 0 {
+		fmt.Printf("%s has no dependencies\n", args[0])
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, d := range deps {
+		parent, err := client.GetTask(ctx, d.ParentID)
+		if err != nil {
+			fmt.Fprintf(w, "  %s\t?\t%s\n", d.ParentID, airyra.FormatError(err))
+			continue
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", parent.ID, formatStatus(parent.Status), parent.Title)
+	}
+	w.Flush()
+	return nil
+}
+
+// --- task block / unblock / blocked / answer ---
+
+var blockReason string
+
+var taskBlockCmd = &cobra.Command{
+	Use:   "block <id>",
+	Short: "Block a task, recording why",
+	Long: `Mark a task as blocked and record the reason. The worker holding the
+task keeps it until the blocker is answered.
+
+Workers block their task by writing a question to ~/.isollm-blocked;
+see 'isollm task blocked' for open questions.
+
+Examples:
+  isollm task block ar-abc1 --reason "Waiting on the API design review"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskBlock,
+}
+
+func runTaskBlock(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	task, err := mgr.BlockTask(ctx, args[0], blockReason)
+	if err != nil {
+		return fmt.Errorf("failed to block %s: %s", args[0], airyra.FormatError(err))
+	}
+
+	return printTaskResult("Blocked", args[0], task)
+}
+
+var taskUnblockCmd = &cobra.Command{
+	Use:   "unblock <id>",
+	Short: "Unblock a task without answering it",
+	Long: `Unblock a task so the worker holding it carries on. To send the worker
+an answer to its question, use 'isollm task answer' instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskUnblock,
+}
+
+func runTaskUnblock(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	task, err := mgr.UnblockTask(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to unblock %s: %s", args[0], airyra.FormatError(err))
+	}
+
+	return printTaskResult("Unblocked", args[0], task)
+}
+
+var taskBlockedCmd = &cobra.Command{
+	Use:   "blocked",
+	Short: "List blocked tasks and their open questions",
+	Long: `List blocked tasks with the worker holding them and the question or
+failure that blocked them. Answer with 'isollm task answer'.`,
+	Args: cobra.NoArgs,
+	RunE: runTaskBlocked,
+}
+
+func runTaskBlocked(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	blocked, err := mgr.BlockedTasks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list blocked tasks: %s", airyra.FormatError(err))
+	}
+
+	if taskJSON {
+		return printJSON(blocked)
+	}
+
+	if len(blocked) == 0 {
+		fmt.Println("No blocked tasks")
+		return nil
+	}
+
+	for i, b := range blocked {
+		if i > 0 {
+			fmt.Println()
+		}
+		holder := ""
+		if b.Worker != "" {
+			holder = " (" + b.Worker + ")"
+		}
+		fmt.Printf("%s%s  %s\n", b.Task.ID, holder, b.Task.Title)
+		if b.Question == nil {
+			fmt.Println("  No reason given")
+			continue
+		}
+		fmt.Printf("  %s, %s ago:\n", b.Question.Kind, time.Since(b.Question.Time).Round(time.Minute))
+		for _, line := range strings.Split(strings.TrimSpace(b.Question.Text), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	return nil
+}
+
+var taskAnswerCmd = &cobra.Command{
+	Use:   "answer <id> <answer>",
+	Short: "Answer a blocked task's question and unblock it",
+	Long: `Record an answer to a blocked task's question and unblock the task.
+
+The answer is appended to CLAUDE.md in the worker holding the task, and
+Claude's conversation is resumed in the worker's pane with the answer.
+
+Examples:
+  isollm task answer ar-abc1 "Target the v2 API; v1 is being removed"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTaskAnswer,
+}
+
+func runTaskAnswer(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	taskID, answer := args[0], args[1]
+	answered, err := mgr.AnswerTask(ctx, taskID, answer)
+	if err != nil {
+		return fmt.Errorf("failed to answer %s: %s", taskID, airyra.FormatError(err))
+	}
+
+	if answered.Worker != "" {
+		deliverAnswer(cfg, mgr, taskID, answered, answer)
+	}
+
+	if taskJSON {
+		return printJSON(answered)
+	}
+	if answered.Worker != "" {
+		fmt.Printf("Unblocked %s and sent the answer to %s\n", taskID, answered.Worker)
+	} else {
+		fmt.Printf("Unblocked %s\n", taskID)
+	}
+	return nil
+}
+
+// deliverAnswer gets an answer to the worker waiting for it. Failures are
+// warnings: the answer is recorded and the task unblocked either way.
+func deliverAnswer(cfg *config.Config, mgr *worker.Manager, taskID string, answered *worker.BlockedTask, answer string) {
+	question := ""
+	if answered.Question != nil {
+		question = answered.Question.Text
+	}
+	if err := mgr.DeliverAnswer(answered.Worker, taskID, question, answer); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if err := sendAnswer(cfg, mgr, answered.Worker, taskID, answer); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to resume Claude in %s: %v\n", answered.Worker, err)
+	}
+}
+
+// sendAnswer resumes Claude's conversation in the worker's pane with the
+// answer, recording it to the task's log
+func sendAnswer(cfg *config.Config, mgr *worker.Manager, workerName, taskID, answer string) error {
+	launcher, err := claude.NewLauncher(cfg, mgr)
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Answer to your blocker on %s: %s", taskID, answer)
+	return mgr.RunInPane(workerName, claude.WithLog(launcher.GetResumeCommand(msg), taskID))
+}
+
+// --- task import ---
+
+var importDryRun bool
+
+var taskImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add tasks from a markdown checklist, YAML or JSON file",
+	Long: `Add a set of tasks and their dependencies to the queue in one go.
+
+A markdown file (.md) is read as a checklist: every unchecked "- [ ]" item
+is a task, and items nested under it are tasks it depends on. Indented
+text under an item becomes its description. Checked items are skipped.
+
+A YAML (.yaml, .yml) or JSON (.json) file lists tasks with title,
+description, priority, labels and depends_on, which names other tasks by
+their id. Without an id a task is known by its slugged title.
+
+The import is all or nothing: if any task or dependency cannot be added,
+the tasks already created are deleted again. Tasks can be claimed as soon
+as they are added, before their dependencies are, so stop 'isollm dispatch'
+first if a worker must not pick one up mid-import. Imported tasks are
+recorded in .isollm/imports.json under the file's path in the project and
+the task id, so importing the same file again only adds tasks that are new
+or were deleted.
+
+Examples:
+  isollm task import plan.md --dry-run
+  isollm task import tasks.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskImport,
+}
+
+func runTaskImport(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	spec, err := taskspec.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	projectDir, err := findProjectDir()
+	if err != nil {
+		return err
+	}
+
+	client, err := getAiryraClient()
+	if err != nil {
+		return err
+	}
+
+	ledger, err := taskspec.LoadLedger(filepath.Join(projectDir, config.StateDir))
+	if err != nil {
+		return err
+	}
+
+	result, err := taskspec.Import(ctx, client, ledger, importSource(projectDir, args[0]), spec, importDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %s", args[0], airyra.FormatError(err))
+	}
+
+	if !importDryRun {
+		if err := recordVerifyCommands(projectDir, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	if taskJSON {
+		return printJSON(result)
+	}
+
+	printImportResult(result)
+
+	existing := len(result.Tasks) - result.Created()
+	if importDryRun {
+		fmt.Printf("\nDry run: would create %d tasks, %d already imported\n", result.Created(), existing)
+	} else {
+		fmt.Printf("\nCreated %d tasks, %d already imported\n", result.Created(), existing)
+	}
+	return nil
+}
+
 // --- Helper functions ---
 
+// importSource names an imported file in the ledger by its path relative
+// to the project, so files of the same name in different directories are
+// told apart. Files outside the project are named by their absolute path.
+func importSource(projectDir, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	rel, err := filepath.Rel(projectDir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// printImportResult lists the tasks of an import, one per line
+func printImportResult(result *taskspec.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, t := range result.Tasks {
+		id, action := t.TaskID, "created"
+		switch {
+		case t.Existing:
+			action = "exists"
+		case result.DryRun:
+			id, action = "-", "would create"
+		}
+		deps := ""
+		if len(t.DependsOn) > 0 {
+			deps = "after " + strings.Join(t.DependsOn, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", id, action, t.Title, deps)
+	}
+	w.Flush()
+}
+
+// recordVerifyCommands keeps the checks of newly imported tasks for the
+// verification gate
+func recordVerifyCommands(projectDir string, result *taskspec.Result) error {
+	store := verify.NewStore(projectDir)
+	for _, t := range result.Tasks {
+		if t.Existing || len(t.Verify) == 0 {
+			continue
+		}
+		if err := store.Set(t.TaskID, t.Verify); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printTaskResult reports a task changed by a command, as JSON with --json
+func printTaskResult(verb, id string, task *airyra.Task) error {
+	if taskJSON {
+		return printJSON(task)
+	}
+	fmt.Printf("%s %s\n", verb, id)
+	return nil
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
 func getAiryraClient() (*airyra.Client, error) {
 	cfg, err := loadConfig()
 	if err != nil {
@@ -331,17 +1219,35 @@ func getAiryraClient() (*airyra.Client, error) {
 }
 
 func loadConfig() (*config.Config, error) {
-	dir, err := os.Getwd()
+	projectDir, err := findProjectDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current directory: %w", err)
+		return nil, err
 	}
 
-	projectDir, err := config.FindProjectRoot(dir)
+	return loadProjectConfig(projectDir)
+}
+
+// loadProjectConfig loads the project's config, pointing airyra at the port
+// recorded by the running session, which differs from the configured one
+// when isollm up had to pick a free port
+func loadProjectConfig(projectDir string) (*config.Config, error) {
+	cfg, err := config.Load(projectDir)
 	if err != nil {
 		return nil, err
 	}
+	if session, err := state.New(projectDir).LoadSession(); err == nil && session.AiryraPort != 0 {
+		cfg.Airyra.Port = session.AiryraPort
+	}
+	return cfg, nil
+}
+
+func findProjectDir() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
 
-	return config.Load(projectDir)
+	return config.FindProjectRoot(dir)
 }
 
 func init() {
@@ -349,6 +1255,11 @@ func init() {
 	taskAddCmd.Flags().StringVarP(&addPriority, "priority", "p", "", "Priority: critical, high, normal, low, lowest")
 	taskAddCmd.Flags().StringVarP(&addDescription, "description", "D", "", "Task description")
 	taskAddCmd.Flags().StringVarP(&addDependsOn, "depends-on", "d", "", "Task ID this depends on")
+	taskAddCmd.Flags().BoolVar(&addPlan, "plan", false, "Have a worker split the task into subtasks")
+	taskAddCmd.Flags().StringVarP(&addTemplate, "template", "t", "", "Add tasks from a template in isollm.yaml")
+	taskAddCmd.Flags().StringArrayVar(&addSets, "set", nil, "Template parameter as key=value (repeatable)")
+	taskAddCmd.Flags().StringArrayVar(&addEach, "each", nil, "Add a task per value of a parameter, as key=glob or key=a,b (repeatable)")
+	taskAddCmd.Flags().BoolVar(&addDryRun, "dry-run", false, "Show the tasks a template would add without adding them")
 
 	// task list flags
 	taskListCmd.Flags().BoolVar(&listReady, "ready", false, "Show only ready tasks")
@@ -359,10 +1270,48 @@ func init() {
 	// task clear flags
 	taskClearCmd.Flags().BoolVar(&clearAll, "all", false, "Clear all tasks (with confirmation)")
 
+	// task block flags
+	taskBlockCmd.Flags().StringVarP(&blockReason, "reason", "r", "", "Why the task is blocked")
+
+	// task edit flags
+	taskEditCmd.Flags().StringVarP(&editTitle, "title", "t", "", "New title")
+	taskEditCmd.Flags().StringVarP(&editDescription, "description", "D", "", "New description")
+	taskEditCmd.Flags().StringVarP(&editPriority, "priority", "p", "", "New priority: critical, high, normal, low, lowest")
+
+	// task release flags
+	taskReleaseCmd.Flags().BoolVarP(&releaseForce, "force", "f", false, "Release even if another agent claimed it")
+
+	// task import flags
+	taskImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be created without changing the queue")
+
+	// --json on every command that shows or changes a task
+	for _, c := range []*cobra.Command{
+		taskShowCmd, taskEditCmd, taskReleaseCmd, taskBlockCmd, taskUnblockCmd,
+		taskBlockedCmd, taskAnswerCmd, taskDeleteCmd, taskReopenCmd,
+		taskDepsAddCmd, taskDepsRemoveCmd, taskDepsListCmd, taskImportCmd, taskTemplatesCmd,
+	} {
+		c.Flags().BoolVar(&taskJSON, "json", false, "Output as JSON")
+	}
+
 	// Add subcommands
 	taskCmd.AddCommand(taskAddCmd)
 	taskCmd.AddCommand(taskListCmd)
+	taskCmd.AddCommand(taskTemplatesCmd)
 	taskCmd.AddCommand(taskClearCmd)
+	taskCmd.AddCommand(taskShowCmd)
+	taskCmd.AddCommand(taskEditCmd)
+	taskCmd.AddCommand(taskReleaseCmd)
+	taskCmd.AddCommand(taskBlockCmd)
+	taskCmd.AddCommand(taskUnblockCmd)
+	taskCmd.AddCommand(taskBlockedCmd)
+	taskCmd.AddCommand(taskAnswerCmd)
+	taskCmd.AddCommand(taskDeleteCmd)
+	taskCmd.AddCommand(taskReopenCmd)
+	taskCmd.AddCommand(taskImportCmd)
+	taskDepsCmd.AddCommand(taskDepsAddCmd)
+	taskDepsCmd.AddCommand(taskDepsRemoveCmd)
+	taskDepsCmd.AddCommand(taskDepsListCmd)
+	taskCmd.AddCommand(taskDepsCmd)
 
 	// Add to root
 	rootCmd.AddCommand(taskCmd)
@@ -2,10 +2,9 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -14,20 +13,13 @@ import (
 	"isollm/internal/barerepo"
 	"isollm/internal/claude"
 	"isollm/internal/config"
+	"isollm/internal/events"
+	"isollm/internal/logs"
+	"isollm/internal/state"
 	"isollm/internal/worker"
 	"isollm/internal/zellij"
 )
 
-// SessionState represents the saved session state
-type SessionState struct {
-	Project     string    `json:"project"`
-	Workers     []string  `json:"workers"`
-	StartedAt   time.Time `json:"started_at"`
-	ZellijName  string    `json:"zellij_session,omitempty"`
-	AiryraPort  int       `json:"airyra_port"`
-	BaseBranch  string    `json:"base_branch"`
-}
-
 var upCmd = &cobra.Command{
 	Use:   "up",
 	Short: "Start the isollm orchestration environment",
@@ -42,15 +34,22 @@ This command:
 6. Launches a zellij session with worker panes
 
 Use --no-zellij to skip the zellij launch and just prepare workers.
+Use --dispatch to leave worker panes waiting for 'isollm dispatch'.
 Use --force to start even if the host repo has commits not in the bare repo.`,
 	RunE: runUp,
 }
 
+// paneReadyTimeout is how long worker panes get to come up before Claude
+// is started in them
+const paneReadyTimeout = time.Minute
+
 var (
 	upWorkers  int
 	upBase     string
 	upForce    bool
 	upNoZellij bool
+	upDispatch bool
+	upParallel int
 )
 
 func init() {
@@ -58,11 +57,13 @@ func init() {
 	upCmd.Flags().StringVar(&upBase, "base", "", "Override base branch")
 	upCmd.Flags().BoolVar(&upForce, "force", false, "Start even with stale repo")
 	upCmd.Flags().BoolVar(&upNoZellij, "no-zellij", false, "Skip zellij launch")
+	upCmd.Flags().BoolVar(&upDispatch, "dispatch", false, "Don't launch Claude in panes; leave them for 'isollm dispatch'")
+	upCmd.Flags().IntVar(&upParallel, "parallel", worker.DefaultParallel, "Workers to create or start at once")
 
 	rootCmd.AddCommand(upCmd)
 }
 
-func runUp(cmd *cobra.Command, args []string) error {
+func runUp(cmd *cobra.Command, args []string) (err error) {
 	ctx := cmd.Context()
 	if ctx == nil {
 		ctx = context.Background()
@@ -117,15 +118,58 @@ func runUp(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// 4. Start airyra server
+	// 4. Repair state left by a crashed isollm, then record the session so
+	// a second isollm up in the same project fails instead of racing this one
+	st := state.New(projectDir)
+	repairs, err := st.Recover()
+	if err != nil {
+		return fmt.Errorf("failed to recover state: %w", err)
+	}
+	for _, r := range repairs {
+		fmt.Printf("  Recovered: %s\n", r)
+	}
+
+	session := &state.Session{
+		Status:       state.SessionStatusInitializing,
+		StartedAt:    time.Now(),
+		PID:          os.Getpid(),
+		Project:      cfg.Project,
+		ProjectRoot:  projectDir,
+		BareRepoPath: bareRepoPath,
+		BaseBranch:   cfg.Git.BaseBranch,
+		AiryraPort:   cfg.Airyra.Port,
+	}
+	if !upNoZellij {
+		session.ZellijSession = fmt.Sprintf("isollm-%s", cfg.Project)
+	}
+	if err := st.StartSession(session); err != nil {
+		if errors.Is(err, state.ErrSessionExists) {
+			return fmt.Errorf("isollm up is already running for this project: %w", err)
+		}
+		return fmt.Errorf("failed to record session: %w", err)
+	}
+	defer func() {
+		// Don't leave behind a session that never got running
+		if err != nil && session.Status == state.SessionStatusInitializing {
+			st.ClearSession()
+		}
+	}()
+
+	// 5. Start airyra server
 	fmt.Print("Checking airyra server... ")
-	if err := ensureAiryraRunning(ctx, cfg); err != nil {
+	if err := ensureAiryraRunning(ctx, projectDir, cfg); err != nil {
 		fmt.Println("failed")
 		return fmt.Errorf("failed to start airyra server: %w", err)
 	}
 	fmt.Println("ok")
+	session.AiryraPort = cfg.Airyra.Port
+	if users, err := airyra.NewUsers(cfg.Airyra.Host, cfg.Airyra.Port); err == nil {
+		if err := users.Add(projectDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
 
-	// 5. Create bare repo if first run
+	// 6. Create bare repo if first run
 	if !barerepo.Exists(bareRepoPath) {
 		fmt.Print("Creating bare repo... ")
 		_, err := barerepo.Create(projectDir, bareRepoPath)
@@ -136,59 +180,55 @@ func runUp(cmd *cobra.Command, args []string) error {
 		fmt.Println("ok")
 	}
 
-	// 6. Create worker manager
+	// 7. Create worker manager
 	mgr, err := worker.NewManager(projectDir, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create worker manager: %w", err)
 	}
 
-	// 7. Create/start workers
-	fmt.Print("Starting workers... ")
-	workerNames, err := ensureWorkersRunning(mgr, cfg.Workers)
+	// 8. Create/start workers. Workers that fail are reported and left
+	// out; the session goes ahead with the rest.
+	fmt.Println("Starting workers...")
+	workerNames, err := ensureWorkersRunning(mgr, cfg.Workers, upParallel)
 	if err != nil {
-		fmt.Println("failed")
 		return err
 	}
-	fmt.Printf("%d workers ready\n", len(workerNames))
 
-	// 8. Prepare Claude environment in each worker
-	fmt.Print("Preparing Claude environment... ")
+	// 9. Prepare Claude environment in each worker
+	fmt.Println("Preparing Claude environment...")
 	launcher, err := claude.NewLauncher(cfg, mgr)
 	if err != nil {
-		fmt.Println("failed")
 		return fmt.Errorf("failed to create Claude launcher: %w", err)
 	}
 
-	for _, name := range workerNames {
+	results := worker.ForEach(workerNames, upParallel, func(name string) error {
 		branchName := fmt.Sprintf("%s%s", cfg.Git.BranchPrefix, name)
-		if err := launcher.PrepareWorker(name, branchName); err != nil {
-			fmt.Println("failed")
-			return fmt.Errorf("failed to prepare worker %s: %w", name, err)
-		}
-	}
-	fmt.Println("ok")
-
-	// 9. Save session state
-	sessionState := &SessionState{
-		Project:    cfg.Project,
-		Workers:    workerNames,
-		StartedAt:  time.Now(),
-		AiryraPort: cfg.Airyra.Port,
-		BaseBranch: cfg.Git.BaseBranch,
-	}
-
-	if !upNoZellij {
-		sessionState.ZellijName = fmt.Sprintf("isollm-%s", cfg.Project)
+		return launcher.PrepareWorker(name, branchName)
+	}, func(r worker.Result) {
+		printResult(r, "prepared")
+	})
+	workerNames = worker.Succeeded(results)
+	if len(workerNames) == 0 {
+		return fmt.Errorf("no workers could be prepared")
 	}
+	fmt.Printf("%d workers ready\n", len(workerNames))
 
-	if err := saveSessionState(projectDir, sessionState); err != nil {
+	// 10. Save session state
+	session.Status = state.SessionStatusRunning
+	session.Workers = workerNames
+	if err := st.SaveSession(session); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not save session state: %v\n", err)
 	}
+	events.New(projectDir).Emit(events.Event{
+		Type:    events.SessionStarted,
+		Message: fmt.Sprintf("%d workers on %s", len(workerNames), cfg.Git.BaseBranch),
+	})
 
-	// 10. Launch zellij
+	// 11. Launch zellij
 	if !upNoZellij {
+		logStore := openLogStore(projectDir, cfg)
 		fmt.Print("Launching zellij... ")
-		if err := launchZellij(cfg, workerNames, mgr); err != nil {
+		if err := launchZellij(cfg, workerNames, mgr, logStore); err != nil {
 			fmt.Println("failed")
 			return err
 		}
@@ -203,55 +243,97 @@ func runUp(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// ensureAiryraRunning ensures the airyra server is running
-func ensureAiryraRunning(ctx context.Context, cfg *config.Config) error {
-	return airyra.EnsureRunning(ctx, cfg.Airyra.Host, cfg.Airyra.Port)
+// ensureAiryraRunning ensures an airyra server for the project is ready,
+// starting the project's managed server if nothing listens on the
+// configured address. With airyra.auto_port a port taken by another
+// service is swapped for the port of the server the project already runs,
+// or else a free one; cfg and the session, if there is one, are updated to
+// use it.
+func ensureAiryraRunning(ctx context.Context, projectDir string, cfg *config.Config) error {
+	server := airyra.NewServer(projectDir, cfg.Airyra.Host, cfg.Airyra.Port)
+	server.Project = cfg.Airyra.Project
+
+	err := server.EnsureRunning(ctx)
+	if !errors.Is(err, airyra.ErrPortConflict) || !cfg.Airyra.AutoPort {
+		return withPortHint(err)
+	}
+
+	// A server the project started on another port earlier is reused
+	// rather than started again next to it
+	port := server.ManagedPort()
+	if port != 0 && port != cfg.Airyra.Port {
+		fmt.Printf("port %d is taken, using the project's server on %d... ", cfg.Airyra.Port, port)
+	} else {
+		if port, err = airyra.FreePort(cfg.Airyra.Host); err != nil {
+			return err
+		}
+		fmt.Printf("port %d is taken, using %d... ", cfg.Airyra.Port, port)
+	}
+	server.Port = port
+	if err := server.EnsureRunning(ctx); err != nil {
+		return err
+	}
+	cfg.Airyra.Port = port
+
+	st := state.New(projectDir)
+	if session, err := st.LoadSession(); err == nil {
+		session.AiryraPort = port
+		if err := st.SaveSession(session); err != nil {
+			return fmt.Errorf("failed to record airyra port: %w", err)
+		}
+	}
+	return nil
+}
+
+// withPortHint adds how to resolve a port conflict to err
+func withPortHint(err error) error {
+	if errors.Is(err, airyra.ErrPortConflict) {
+		return fmt.Errorf("%w\nSet airyra.port to a free port in isollm.yaml, or airyra.auto_port: true to pick one", err)
+	}
+	return err
 }
 
-// ensureWorkersRunning creates/starts workers up to the desired count
+// ensureWorkersRunning creates/starts workers up to the desired count,
+// at most parallel at a time. Workers that fail to start or be created are
+// reported and skipped; it only fails if no worker is running at the end.
 // Returns the list of running worker names
-func ensureWorkersRunning(mgr *worker.Manager, count int) ([]string, error) {
+func ensureWorkersRunning(mgr *worker.Manager, count, parallel int) ([]string, error) {
 	// List existing workers
 	workers, err := mgr.List()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list workers: %w", err)
 	}
 
-	var runningNames []string
-	var stoppedNames []string
-
-	// Categorize existing workers
-	for _, w := range workers {
-		if w.Status == "running" {
-			runningNames = append(runningNames, w.Name)
-		} else {
-			stoppedNames = append(stoppedNames, w.Name)
-		}
+	runningNames, stoppedNames, failedNames := worker.Partition(workers)
+	for _, name := range failedNames {
+		fmt.Printf("  %s: skipped (provisioning failed)\n", name)
 	}
 
 	// Start stopped workers if we need more
-	for _, name := range stoppedNames {
-		if len(runningNames) >= count {
-			break
-		}
-		if err := mgr.Start(name); err != nil {
-			return nil, fmt.Errorf("failed to start worker %s: %w", name, err)
+	if need := count - len(runningNames); need > 0 {
+		if need < len(stoppedNames) {
+			stoppedNames = stoppedNames[:need]
 		}
-		runningNames = append(runningNames, name)
+		results := worker.ForEach(stoppedNames, parallel, mgr.Start, func(r worker.Result) {
+			printResult(r, "started")
+		})
+		runningNames = append(runningNames, worker.Succeeded(results)...)
 	}
 
-	// Create new workers if still not enough
-	existingCount := len(workers)
-	for len(runningNames) < count {
-		// Create will auto-name as worker-N
-		workerNum := existingCount + 1
-		existingCount++
-		name := fmt.Sprintf("%s%d", worker.WorkerPrefix, workerNum)
+	// Create new workers if still not enough. Failed creations are not
+	// retried, so a broken setup does not loop.
+	if need := count - len(runningNames); need > 0 {
+		results := mgr.CreateWorkers(mgr.NextWorkerNames(need), parallel, func(r worker.Result) {
+			printResult(r, "created")
+		})
+		runningNames = append(runningNames, worker.Succeeded(results)...)
+	}
 
-		if err := mgr.CreateWorker(name); err != nil {
-			return nil, fmt.Errorf("failed to create worker: %w", err)
-		}
-		runningNames = append(runningNames, name)
+	if len(runningNames) == 0 {
+		return nil, fmt.Errorf("no workers are running")
+	}
+	if len(runningNames) < count {
+		fmt.Fprintf(os.Stderr, "Warning: only %d of %d workers are running\n", len(runningNames), count)
 	}
 
 	// If we have more running than needed, that's ok - we don't stop them
@@ -263,8 +345,17 @@ func ensureWorkersRunning(mgr *worker.Manager, count int) ([]string, error) {
 	return runningNames, nil
 }
 
+// printResult prints a per-worker progress line
+func printResult(r worker.Result, done string) {
+	if r.Err != nil {
+		fmt.Printf("  %s: failed: %v\n", r.Name, r.Err)
+		return
+	}
+	fmt.Printf("  %s: %s\n", r.Name, done)
+}
+
 // launchZellij creates and attaches to a zellij session
-func launchZellij(cfg *config.Config, workers []string, mgr *worker.Manager) error {
+func launchZellij(cfg *config.Config, workers []string, mgr *worker.Manager, logStore *logs.Store) error {
 	// Create zellij manager
 	zellijMgr, err := zellij.NewManager()
 	if err != nil {
@@ -285,8 +376,11 @@ func launchZellij(cfg *config.Config, workers []string, mgr *worker.Manager) err
 		return zellijMgr.AttachSession(sessionName)
 	}
 
-	// Build worker panes
+	// Build worker panes; each runs the commands isollm sends it
 	workerPanes := zellij.CreateWorkerPanes(workers)
+	for i := range workerPanes {
+		workerPanes[i].Command = claude.PaneCommand()
+	}
 
 	// Build dashboard config
 	dashboard := zellij.DashboardConfig{
@@ -318,6 +412,12 @@ func launchZellij(cfg *config.Config, workers []string, mgr *worker.Manager) err
 
 	fmt.Println("ok")
 
+	// In dispatch mode, Claude is started per task by 'isollm dispatch'
+	if upDispatch {
+		fmt.Println("Worker panes wait for tasks; run 'isollm dispatch' to assign them")
+		return zellijMgr.AttachSession(sessionName)
+	}
+
 	// Send commands to each worker pane to launch Claude
 	launcher, err := claude.NewLauncher(cfg, mgr)
 	if err != nil {
@@ -325,86 +425,42 @@ func launchZellij(cfg *config.Config, workers []string, mgr *worker.Manager) err
 	}
 
 	launchCmd := launcher.GetLaunchCommand()
-	cmdStr := formatCommand(launchCmd)
-
-	for _, name := range workers {
-		// Send the launch command to the worker pane
-		if err := zellijMgr.SendKeys(sessionName, name, cmdStr, "Enter"); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to send command to %s: %v\n", name, err)
-		}
-	}
-
-	// Attach to the session
-	return zellijMgr.AttachSession(sessionName)
-}
-
-// saveSessionState saves the session state to .isollm/session.json
-func saveSessionState(projectDir string, state *SessionState) error {
-	stateDir := filepath.Join(projectDir, config.StateDir)
-	if err := os.MkdirAll(stateDir, 0755); err != nil {
-		return fmt.Errorf("failed to create state directory: %w", err)
-	}
-
-	statePath := filepath.Join(stateDir, "session.json")
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal session state: %w", err)
-	}
-
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write session state: %w", err)
-	}
 
-	return nil
-}
-
-// loadSessionState loads the session state from .isollm/session.json
-func loadSessionState(projectDir string) (*SessionState, error) {
-	statePath := filepath.Join(projectDir, config.StateDir, "session.json")
-	data, err := os.ReadFile(statePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to read session state: %w", err)
-	}
+	// The panes come up with the session, so Claude is started in them
+	// while it is attached. Failures are reported once it is detached.
+	failures := make(chan []string, 1)
+	go func() {
+		failures <- launchInPanes(mgr, workers, launchCmd, logStore)
+	}()
 
-	var state SessionState
-	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal session state: %w", err)
+	err = zellijMgr.AttachSession(sessionName)
+	for _, failure := range <-failures {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", failure)
 	}
-
-	return &state, nil
+	return err
 }
 
-// formatCommand formats a command slice as a single shell command string
-func formatCommand(cmd []string) string {
-	if len(cmd) == 0 {
-		return ""
-	}
-	if len(cmd) == 1 {
-		return cmd[0]
-	}
+// launchInPanes starts Claude in each worker's pane, giving the panes up to
+// paneReadyTimeout to come up, and returns what could not be started
+func launchInPanes(mgr *worker.Manager, workers []string, launchCmd []string, logStore *logs.Store) []string {
+	deadline := time.Now().Add(paneReadyTimeout)
 
-	// Simple join for now - could add shell escaping if needed
-	result := cmd[0]
-	for _, arg := range cmd[1:] {
-		// Quote args with spaces
-		if containsSpace(arg) {
-			result += fmt.Sprintf(" %q", arg)
-		} else {
-			result += " " + arg
+	var failures []string
+	for _, name := range workers {
+		// Record the pane to the worker's session log when possible
+		cmd := launchCmd
+		if err := logStore.Prepare(name, logs.SessionLog); err == nil {
+			cmd = claude.WithLog(launchCmd, logs.SessionLog)
 		}
-	}
-	return result
-}
 
-// containsSpace checks if a string contains whitespace
-func containsSpace(s string) bool {
-	for _, c := range s {
-		if c == ' ' || c == '\t' || c == '\n' {
-			return true
+		err := mgr.RunInPane(name, cmd)
+		for errors.Is(err, claude.ErrNoPane) && time.Now().Before(deadline) {
+			time.Sleep(time.Second)
+			err = mgr.RunInPane(name, cmd)
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to start Claude in %s: %v", name, err))
 		}
 	}
-	return false
+	return failures
 }
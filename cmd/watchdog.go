@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"isollm/internal/airyra"
+	"isollm/internal/barerepo"
+	"isollm/internal/config"
+	"isollm/internal/events"
+	"isollm/internal/notes"
+	"isollm/internal/watchdog"
+	"isollm/internal/worker"
+)
+
+var (
+	watchdogInterval time.Duration
+	watchdogOnce     bool
+)
+
+var watchdogCmd = &cobra.Command{
+	Use:   "watchdog",
+	Short: "Reclaim tasks from dead or silent workers",
+	Long: `Watch in-progress tasks and put stale ones back in the queue.
+
+A task is reclaimed when:
+- its worker container is no longer running, or
+- nothing happened on its branch for longer than task_timeout
+  (time since the claim or the last commit pushed to the bare repo)
+
+Before releasing, uncommitted work in a running worker is committed and
+pushed to the task branch. A note explaining why the task was reclaimed is
+attached to it (see .isollm/notes).
+
+Set task_timeout in isollm.yaml (default 1h, 0 disables the silence check).
+'isollm dispatch' runs the same check on every pass.`,
+	RunE: runWatchdog,
+}
+
+func init() {
+	watchdogCmd.Flags().DurationVar(&watchdogInterval, "interval", watchdog.DefaultInterval, "Time between checks")
+	watchdogCmd.Flags().BoolVar(&watchdogOnce, "once", false, "Run a single check and exit")
+
+	rootCmd.AddCommand(watchdogCmd)
+}
+
+func runWatchdog(cmd *cobra.Command, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectDir, err := config.FindProjectRoot(dir)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+
+	mgr, err := worker.NewManager(projectDir, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create worker manager: %w", err)
+	}
+
+	client, err := airyra.NewClientFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	w, err := newWatchdog(projectDir, cfg, mgr, client)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if watchdogOnce {
+		reclaimed, err := w.Check(ctx)
+		if err != nil {
+			return err
+		}
+		if len(reclaimed) == 0 {
+			fmt.Println("No stale tasks")
+		}
+		return nil
+	}
+
+	fmt.Printf("Checking for stale tasks every %s (Ctrl-C to stop)\n", watchdogInterval)
+	return w.Run(ctx, watchdogInterval)
+}
+
+// newWatchdog creates a watchdog for the project using the bare repo for
+// branch activity, the project's note store and its event log
+func newWatchdog(projectDir string, cfg *config.Config, mgr *worker.Manager, client airyra.TaskClient) (*watchdog.Watchdog, error) {
+	barePath, err := barerepo.GetMountPath(cfg.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	var history watchdog.BranchHistory
+	if barerepo.Exists(barePath) {
+		history = barerepo.New(barePath)
+	}
+
+	w := watchdog.New(mgr, client, history, notes.New(projectDir), cfg.TaskTimeoutDuration(), os.Stdout)
+	w.SetEvents(events.New(projectDir))
+	return w, nil
+}
@@ -1,14 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"isollm/internal/config"
+	"isollm/internal/logs"
+	"isollm/internal/verify"
 	"isollm/internal/worker"
 )
 
@@ -20,6 +28,7 @@ var workerCmd = &cobra.Command{
 Workers are containers with:
 - The bare repo mounted at /repo.git
 - A cloned working copy at /home/dev/project
+- Their host log directory mounted at /home/dev/.isollm-logs
 - A "clean" snapshot for easy reset`,
 }
 
@@ -34,7 +43,10 @@ The worker is created with the bare repo mounted and a clean snapshot.`,
 	RunE: runWorkerAdd,
 }
 
-var addCount int
+var (
+	addCount    int
+	addParallel int
+)
 
 // workerListCmd lists all workers
 var workerListCmd = &cobra.Command{
@@ -113,11 +125,81 @@ var workerStatusCmd = &cobra.Command{
 	RunE:  runWorkerStatus,
 }
 
+// workerSetLimitsCmd changes a worker's resource limits
+var workerSetLimitsCmd = &cobra.Command{
+	Use:   "set-limits <name>",
+	Short: "Change a worker's CPU, memory, disk and process limits",
+	Long: `Change the resource limits of an existing worker.
+
+Only the limits given as flags are changed. Without flags, the resources
+block from isollm.yaml is applied (useful after editing it).
+
+Examples:
+  isollm worker set-limits worker-1 --cpu 4 --memory 8GiB
+  isollm worker set-limits worker-2 --disk 40GiB
+  isollm worker set-limits worker-3          # Re-apply isollm.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkerSetLimits,
+}
+
+var limitsFlags config.ResourcesConfig
+
+// workerLogsCmd shows what Claude did in a worker
+var workerLogsCmd = &cobra.Command{
+	Use:   "logs <name>",
+	Short: "View worker logs",
+	Long: `Show the recorded pane output of a worker.
+
+Every Claude session launched in a worker is recorded to
+.isollm/logs/<worker>/<task-id>.log on the host. By default the most
+recently written log is shown.
+
+Examples:
+  isollm worker logs worker-1                # Recent activity
+  isollm worker logs worker-1 -f             # Stream live
+  isollm worker logs worker-1 --task ar-a1b2 # A specific task
+  isollm worker logs worker-1 --since 2h     # Every task worked on in the last 2h`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkerLogs,
+}
+
+var (
+	logsTask   string
+	logsFollow bool
+	logsSince  time.Duration
+	logsLines  int
+)
+
+// workerDoneCmd verifies and completes a worker's task
+var workerDoneCmd = &cobra.Command{
+	Use:   "done <name>",
+	Short: "Verify and complete a worker's task",
+	Long: `Run the verify commands from isollm.yaml inside the worker against its
+task branch, then mark the task done.
+
+If a check fails the task is blocked instead and the output is attached to
+it as a note. With no verify commands configured the task is completed
+directly.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkerDone,
+}
+
 func init() {
 	workerAddCmd.Flags().IntVarP(&addCount, "count", "n", 1, "Number of workers to create")
+	workerAddCmd.Flags().IntVar(&addParallel, "parallel", worker.DefaultParallel, "Workers to create at once")
 
 	workerRemoveCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Force removal of running container")
 
+	workerSetLimitsCmd.Flags().IntVar(&limitsFlags.CPU, "cpu", 0, "CPU cores")
+	workerSetLimitsCmd.Flags().StringVar(&limitsFlags.Memory, "memory", "", "Memory limit, e.g. 4GiB or 50%")
+	workerSetLimitsCmd.Flags().StringVar(&limitsFlags.Disk, "disk", "", "Root disk quota, e.g. 20GiB")
+	workerSetLimitsCmd.Flags().IntVar(&limitsFlags.Processes, "processes", 0, "Maximum number of processes")
+
+	workerLogsCmd.Flags().StringVar(&logsTask, "task", "", "Show the log of a specific task")
+	workerLogsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Stream new output as it is written")
+	workerLogsCmd.Flags().DurationVar(&logsSince, "since", 0, "Show every log written within this duration")
+	workerLogsCmd.Flags().IntVarP(&logsLines, "lines", "n", 100, "Lines to show from the end of each log (0 for all)")
+
 	workerCmd.AddCommand(workerAddCmd)
 	workerCmd.AddCommand(workerListCmd)
 	workerCmd.AddCommand(workerStartCmd)
@@ -127,6 +209,9 @@ func init() {
 	workerCmd.AddCommand(workerShellCmd)
 	workerCmd.AddCommand(workerExecCmd)
 	workerCmd.AddCommand(workerStatusCmd)
+	workerCmd.AddCommand(workerSetLimitsCmd)
+	workerCmd.AddCommand(workerLogsCmd)
+	workerCmd.AddCommand(workerDoneCmd)
 
 	rootCmd.AddCommand(workerCmd)
 }
@@ -142,7 +227,7 @@ func getManager() (*worker.Manager, error) {
 		return nil, err
 	}
 
-	cfg, err := config.Load(projectDir)
+	cfg, err := loadProjectConfig(projectDir)
 	if err != nil {
 		return nil, err
 	}
@@ -168,18 +253,25 @@ func runWorkerAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create N workers
-	for i := 0; i < addCount; i++ {
+	if addCount == 1 {
 		fmt.Printf("Creating worker...\n")
 		if err := mgr.CreateWorker(""); err != nil {
 			return err
 		}
+		fmt.Println("Worker created")
+		return nil
 	}
 
-	if addCount == 1 {
-		fmt.Println("Worker created")
-	} else {
-		fmt.Printf("%d workers created\n", addCount)
+	fmt.Printf("Creating %d workers...\n", addCount)
+	results := mgr.CreateWorkers(mgr.NextWorkerNames(addCount), addParallel, func(r worker.Result) {
+		printResult(r, "created")
+	})
+
+	created := len(worker.Succeeded(results))
+	if created < addCount {
+		return fmt.Errorf("%d of %d workers failed", addCount-created, addCount)
 	}
+	fmt.Printf("%d workers created\n", addCount)
 	return nil
 }
 
@@ -215,10 +307,14 @@ func runWorkerList(cmd *cobra.Command, args []string) error {
 		if branch == "" {
 			branch = "-"
 		}
+		status := string(worker.Status)
+		if worker.Error != "" {
+			status = "ERROR"
+		}
 
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			worker.Name,
-			worker.Status,
+			status,
 			ip,
 			task,
 			branch,
@@ -335,10 +431,23 @@ func runWorkerStatus(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Worker: %s\n", name)
 	fmt.Printf("Status: %s\n", status)
+	if provisionErr := mgr.ProvisionError(name); provisionErr != "" {
+		fmt.Printf("Error: provisioning failed: %s\n", provisionErr)
+		fmt.Printf("  Log: isollm worker logs %s --task %s -n 0\n", name, logs.ProvisionLog)
+		fmt.Printf("  Fix the config, then 'isollm worker rm -f %s' and 'isollm worker add %s'\n", name, name)
+	}
 	if ip != "" {
 		fmt.Printf("IP: %s\n", ip)
 	}
 
+	if limits, err := mgr.Limits(name); err == nil {
+		fmt.Printf("\nLimits:\n")
+		fmt.Printf("  CPU: %s\n", limitValue(strconv.Itoa(limits.CPU), limits.CPU == 0))
+		fmt.Printf("  Memory: %s\n", limitValue(limits.Memory, limits.Memory == ""))
+		fmt.Printf("  Disk: %s\n", limitValue(limits.Disk, limits.Disk == ""))
+		fmt.Printf("  Processes: %s\n", limitValue(strconv.Itoa(limits.Processes), limits.Processes == 0))
+	}
+
 	if task != nil && task.TaskID != "" {
 		fmt.Printf("\nTask: %s\n", task.TaskID)
 		fmt.Printf("Branch: %s\n", task.Branch)
@@ -359,6 +468,158 @@ func runWorkerStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// limitValue formats a resource limit for display
+func limitValue(value string, unset bool) string {
+	if unset {
+		return "unlimited"
+	}
+	return value
+}
+
+func runWorkerSetLimits(cmd *cobra.Command, args []string) error {
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	limits := limitsFlags
+	if !cmd.Flags().Changed("cpu") && !cmd.Flags().Changed("memory") &&
+		!cmd.Flags().Changed("disk") && !cmd.Flags().Changed("processes") {
+		limits = mgr.ConfiguredLimits()
+		if limits.IsZero() {
+			return fmt.Errorf("no limits given and no resources block in %s", config.ConfigFileName)
+		}
+	}
+
+	name := args[0]
+	if err := mgr.SetLimits(name, limits); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated limits for %s\n", name)
+	return nil
+}
+
+func runWorkerDone(cmd *cobra.Command, args []string) error {
+	mgr, err := getManager()
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+
+	task, err := mgr.GetTask(name)
+	if err != nil {
+		return err
+	}
+	if task == nil || task.TaskID == "" {
+		return fmt.Errorf("worker %s has no assigned task", name)
+	}
+
+	// Verification runs builds and tests, so allow it plenty of time
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	fmt.Printf("Verifying %s on %s...\n", task.TaskID, name)
+	if err := mgr.CompleteWorkerTask(ctx, name); err != nil {
+		var failed *verify.FailedError
+		if errors.As(err, &failed) {
+			fmt.Print(failed.Report.Summary())
+			return fmt.Errorf("task %s blocked: verification failed", task.TaskID)
+		}
+		return err
+	}
+
+	fmt.Printf("Task %s done\n", task.TaskID)
+	return nil
+}
+
+func runWorkerLogs(cmd *cobra.Command, args []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectDir, err := config.FindProjectRoot(dir)
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	if !strings.HasPrefix(name, worker.WorkerPrefix) {
+		name = worker.WorkerPrefix + name
+	}
+
+	store := logs.New(projectDir)
+
+	var selected []logs.Log
+	switch {
+	case logsTask != "":
+		path := store.Path(name, logsTask)
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no log for task %s on %s", logsTask, name)
+			}
+			return fmt.Errorf("failed to read log: %w", err)
+		}
+		selected = append(selected, logs.Log{
+			Worker:  name,
+			TaskID:  logsTask,
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	case logsSince > 0:
+		all, err := store.List(name)
+		if err != nil {
+			return err
+		}
+		cutoff := time.Now().Add(-logsSince)
+		for _, l := range all {
+			if l.ModTime.After(cutoff) {
+				selected = append(selected, l)
+			}
+		}
+	default:
+		latest, err := store.Latest(name)
+		if err != nil {
+			return err
+		}
+		if latest != nil {
+			selected = append(selected, *latest)
+		}
+	}
+
+	if len(selected) == 0 {
+		fmt.Printf("No logs for %s\n", name)
+		return nil
+	}
+
+	for _, l := range selected {
+		if len(selected) > 1 {
+			fmt.Printf("==> %s (%s) <==\n", l.TaskID, l.ModTime.Format("2006-01-02 15:04"))
+		}
+		text, err := logs.Tail(l.Path, logsLines)
+		if err != nil {
+			return err
+		}
+		if text != "" {
+			fmt.Println(text)
+		}
+	}
+
+	if !logsFollow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	last := selected[len(selected)-1]
+	return logs.Follow(ctx, last.Path, last.Size, os.Stdout, 500*time.Millisecond)
+}
+
 // Helper function to check if a string slice contains a value
 func contains(slice []string, val string) bool {
 	for _, s := range slice {
@@ -76,6 +76,11 @@ func (c *Client) ListReadyTasks(ctx context.Context) (*TaskList, error) {
 	return c.sdk.ListReadyTasks(ctx)
 }
 
+// UpdateTask changes a task's title, description or priority
+func (c *Client) UpdateTask(ctx context.Context, id string, update TaskUpdate) (*Task, error) {
+	return c.sdk.UpdateTask(ctx, id, update)
+}
+
 // DeleteTask deletes a task
 func (c *Client) DeleteTask(ctx context.Context, id string) error {
 	return c.sdk.DeleteTask(ctx, id)
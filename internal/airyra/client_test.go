@@ -202,6 +202,29 @@ func TestMockClient_DeleteTask(t *testing.T) {
 	}
 }
 
+func TestMockClient_UpdateTask(t *testing.T) {
+	mock := NewMockClient()
+	ctx := context.Background()
+
+	task, _ := mock.AddTask(ctx, "Test task")
+
+	title, priority := "Renamed", PriorityHigh
+	updated, err := mock.UpdateTask(ctx, task.ID, TaskUpdate{Title: &title, Priority: &priority})
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if updated.Title != "Renamed" || updated.Priority != PriorityHigh {
+		t.Errorf("UpdateTask() = %q [%d], want Renamed [%d]", updated.Title, updated.Priority, PriorityHigh)
+	}
+	if updated.Description != "" {
+		t.Errorf("UpdateTask() changed the description to %q", updated.Description)
+	}
+
+	if _, err := mock.UpdateTask(ctx, "nonexistent", TaskUpdate{Title: &title}); !IsTaskNotFound(err) {
+		t.Errorf("UpdateTask() for nonexistent = %v, want TaskNotFound", err)
+	}
+}
+
 func TestMockClient_DeleteTaskNotFound(t *testing.T) {
 	mock := NewMockClient()
 	ctx := context.Background()
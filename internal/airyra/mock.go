@@ -31,6 +31,7 @@ type MockClient struct {
 	OnReleaseTask  func(ctx context.Context, id string, force bool) (*Task, error)
 	OnBlockTask    func(ctx context.Context, id string) (*Task, error)
 	OnDeleteTask   func(ctx context.Context, id string) error
+	OnUpdateTask   func(ctx context.Context, id string, update TaskUpdate) (*Task, error)
 }
 
 // NewMockClient creates a new mock client with default behavior.
@@ -169,6 +170,37 @@ func (m *MockClient) ListReadyTasks(ctx context.Context) (*TaskList, error) {
 	}, nil
 }
 
+// UpdateTask changes a task's title, description or priority.
+func (m *MockClient) UpdateTask(ctx context.Context, id string, update TaskUpdate) (*Task, error) {
+	if m.OnUpdateTask != nil {
+		return m.OnUpdateTask(ctx, id, update)
+	}
+	if !m.ServerRunning {
+		return nil, ErrServerNotRunning
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, ok := m.tasks[id]
+	if !ok {
+		return nil, &sdk.Error{Code: sdk.ErrCodeTaskNotFound, Message: "task not found"}
+	}
+
+	if update.Title != nil {
+		task.Title = *update.Title
+	}
+	if update.Description != nil {
+		task.Description = *update.Description
+	}
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	task.UpdatedAt = time.Now()
+
+	return task, nil
+}
+
 // DeleteTask deletes a task.
 func (m *MockClient) DeleteTask(ctx context.Context, id string) error {
 	if m.OnDeleteTask != nil {
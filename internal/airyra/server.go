@@ -2,10 +2,21 @@ package airyra
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
+
+	sdk "airyra/pkg/airyra"
+
+	"isollm/internal/config"
+	"isollm/internal/state"
 )
 
 const (
@@ -13,29 +24,143 @@ const (
 	DefaultPollInterval = 100 * time.Millisecond
 	// DefaultStartTimeout is the default timeout waiting for server to start
 	DefaultStartTimeout = 30 * time.Second
+	// DefaultStopTimeout is how long a stopping server gets to exit before
+	// it is killed
+	DefaultStopTimeout = 10 * time.Second
+
+	// PidFile holds the PID of the airyra server started by the project and
+	// the port it listens on
+	PidFile = "airyra.pid"
+	// LogFile collects the output of the airyra server started by the project
+	LogFile = "airyra.log"
 )
 
-// StartServer starts the airyra server in the background.
-// It shells out to `airyra server start` and runs it as a background process.
-func StartServer(host string, port int) error {
-	args := []string{"server", "start"}
-	if host != "" || port != 0 {
-		bindAddr := host
-		if bindAddr == "" {
-			bindAddr = "localhost"
-		}
-		if port != 0 {
-			bindAddr = fmt.Sprintf("%s:%d", bindAddr, port)
+var (
+	// ErrServerRunning is returned when starting a server that is already running
+	ErrServerRunning = errors.New("airyra server is already running")
+	// ErrNotManaged is returned when stopping a server the project did not start
+	ErrNotManaged = errors.New("airyra server was not started by this project")
+	// ErrPortConflict is returned when something other than an airyra server
+	// listens on the configured address
+	ErrPortConflict = errors.New("port is in use by another service")
+	// ErrIncompatible is returned when the airyra server answers health checks
+	// but not the task API for the project
+	ErrIncompatible = errors.New("airyra server is not compatible")
+)
+
+// Server is the airyra server process isollm runs for a project. It is
+// started in its own session so it outlives the isollm command that started
+// it, and is tracked by a pidfile and a log file in the project's .isollm dir.
+type Server struct {
+	Host string
+	Port int
+	// Project is checked to be served by a running server; empty skips the check
+	Project string
+
+	dir    string
+	client TaskClient // nil builds a client for Host, Port and Project
+}
+
+// NewServer returns the managed airyra server of the project at projectRoot
+func NewServer(projectRoot, host string, port int) *Server {
+	return &Server{
+		Host: host,
+		Port: port,
+		dir:  filepath.Join(projectRoot, config.StateDir),
+	}
+}
+
+// PidPath returns the path of the server's pidfile
+func (s *Server) PidPath() string {
+	return filepath.Join(s.dir, PidFile)
+}
+
+// LogPath returns the path of the server's log file
+func (s *Server) LogPath() string {
+	return filepath.Join(s.dir, LogFile)
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// PID returns the PID of the running server started by the project, or 0
+// if it did not start one or it has exited
+func (s *Server) PID() int {
+	pid, _ := s.readPidfile()
+	return pid
+}
+
+// ManagedPort returns the port the running server started by the project
+// listens on, or 0 if there is none or its pidfile predates the port
+// being recorded. It can differ from Port when airyra.auto_port moved it.
+func (s *Server) ManagedPort() int {
+	_, port := s.readPidfile()
+	return port
+}
+
+// readPidfile returns the PID and port recorded for the running server
+// started by the project, or zeros if there is none
+func (s *Server) readPidfile() (pid, port int) {
+	data, err := os.ReadFile(s.PidPath())
+	if err != nil {
+		return 0, 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, 0
+	}
+	pid, err = strconv.Atoi(fields[0])
+	if err != nil || !isProcessAlive(pid) {
+		return 0, 0
+	}
+	if len(fields) > 1 {
+		port, _ = strconv.Atoi(fields[1])
+	}
+	return pid, port
+}
+
+// Start launches `airyra server start` in the background, appending its
+// output to the log file and recording its PID
+func (s *Server) Start() error {
+	if pid, port := s.readPidfile(); pid != 0 {
+		if port != 0 && port != s.Port {
+			return fmt.Errorf("%w on port %d (pid %d)", ErrServerRunning, port, pid)
 		}
-		args = append(args, "--bind", bindAddr)
+		return fmt.Errorf("%w (pid %d)", ErrServerRunning, pid)
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
 	}
+	logFile, err := os.OpenFile(s.LogPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open airyra log: %w", err)
+	}
+	defer logFile.Close()
+
+	fmt.Fprintf(logFile, "--- %s starting airyra server on %s\n", time.Now().Format(time.RFC3339), s.Addr())
 
-	cmd := exec.Command("airyra", args...)
+	cmd := exec.Command("airyra", "server", "start", "--bind", s.Addr())
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// A session of its own keeps the server out of the terminal's process
+	// group, so Ctrl-C or closing the terminal does not take it down
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start airyra server: %w", err)
 	}
 
-	// Detach from the process so it continues running after we exit
+	pid := cmd.Process.Pid
+	if err := state.WriteFile(s.PidPath(), []byte(fmt.Sprintf("%d %d\n", pid, s.Port)), 0644); err != nil {
+		syscall.Kill(-pid, syscall.SIGKILL)
+		cmd.Wait()
+		return fmt.Errorf("failed to write airyra pidfile: %w", err)
+	}
+
+	// Reap the server if it exits while we are still running; once we exit
+	// it is adopted by init
 	go func() {
 		_ = cmd.Wait()
 	}()
@@ -43,52 +168,167 @@ func StartServer(host string, port int) error {
 	return nil
 }
 
-// WaitForServer polls until the airyra server is ready or context is cancelled.
-func WaitForServer(ctx context.Context, host string, port int) error {
-	addr := fmt.Sprintf("%s:%d", host, port)
+// Stop terminates the server started by the project, killing it if it has
+// not exited by the time ctx is done, and removes the pidfile
+func (s *Server) Stop(ctx context.Context) error {
+	pid := s.PID()
+	if pid == 0 {
+		os.Remove(s.PidPath())
+		return ErrNotManaged
+	}
+
+	// The server leads its own process group; signal all of it
+	if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("failed to stop airyra server (pid %d): %w", pid, err)
+	}
 
 	ticker := time.NewTicker(DefaultPollInterval)
 	defer ticker.Stop()
+	for isProcessAlive(pid) {
+		select {
+		case <-ctx.Done():
+			syscall.Kill(-pid, syscall.SIGKILL)
+			ctx = context.Background()
+		case <-ticker.C:
+		}
+	}
+
+	if err := os.Remove(s.PidPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove airyra pidfile: %w", err)
+	}
+	return nil
+}
 
+// Probe checks that an airyra server for the project answers on the
+// server's address. It returns ErrServerNotRunning when nothing listens,
+// ErrPortConflict when something else does and ErrIncompatible when the
+// server does not serve the project's task API.
+func (s *Server) Probe(ctx context.Context) error {
+	client := s.client
+	if client == nil {
+		c, err := newProbeClient(s.Host, s.Port, s.Project)
+		if err != nil {
+			return err
+		}
+		client = c
+	}
+	return Probe(ctx, client, s.Host, s.Port, s.Project != "")
+}
+
+// EnsureRunning starts the server if nothing listens on its address yet and
+// waits until it is ready. A port taken by another service or a server that
+// does not serve the project is reported rather than waited for.
+func (s *Server) EnsureRunning(ctx context.Context) error {
+	err := s.Probe(ctx)
+	if err == nil {
+		return nil
+	}
+	// A server we started that is still coming up only needs waiting for
+	starting := errors.Is(err, ErrPortConflict) && s.PID() != 0
+	if !errors.Is(err, ErrServerNotRunning) && !starting {
+		return err
+	}
+
+	// The project's server may already be starting, but only waiting for it
+	// helps if it is starting on this port
+	if err := s.Start(); err != nil {
+		if port := s.ManagedPort(); !errors.Is(err, ErrServerRunning) || (port != 0 && port != s.Port) {
+			return err
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, DefaultStartTimeout)
+	defer cancel()
+
+	if err := s.WaitForServer(waitCtx); err != nil {
+		return fmt.Errorf("%w (see %s)", err, s.LogPath())
+	}
+	return nil
+}
+
+// WaitForServer polls until the server is ready or ctx is done, returning
+// the last reason it was not ready
+func (s *Server) WaitForServer(ctx context.Context) error {
+	ticker := time.NewTicker(DefaultPollInterval)
+	defer ticker.Stop()
+
+	lastErr := ErrServerNotRunning
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for airyra server at %s: %w", addr, ctx.Err())
+			return fmt.Errorf("timeout waiting for airyra server at %s: %w", s.Addr(), lastErr)
 		case <-ticker.C:
-			if IsServerRunning(host, port) {
+			if lastErr = s.Probe(ctx); lastErr == nil {
 				return nil
 			}
 		}
 	}
 }
 
-// IsServerRunning checks if the airyra server is responding.
-// It does a simple TCP connection check to the server port.
-func IsServerRunning(host string, port int) bool {
-	addr := fmt.Sprintf("%s:%d", host, port)
-	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
-	if err != nil {
-		return false
+// Probe checks the airyra server behind client, which talks to host:port.
+// A failed health check is told apart from nothing listening by dialing the
+// port. With checkProject the project's task API must answer too; a project
+// airyra has not seen yet is fine.
+func Probe(ctx context.Context, client TaskClient, host string, port int, checkProject bool) error {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	if err := client.Health(ctx); err != nil {
+		if !portInUse(host, port) {
+			return ErrServerNotRunning
+		}
+		return fmt.Errorf("%w: %s answers but is not a healthy airyra server (%v)", ErrPortConflict, addr, err)
 	}
-	conn.Close()
-	return true
-}
 
-// EnsureRunning starts the server if not already running and waits for it.
-func EnsureRunning(ctx context.Context, host string, port int) error {
-	// Check if already running
-	if IsServerRunning(host, port) {
+	if !checkProject {
 		return nil
 	}
+	if _, err := client.ListTasks(ctx, sdk.WithPerPage(1)); err != nil && !IsProjectNotFound(err) {
+		return fmt.Errorf("%w: the server at %s does not answer task requests (%v); check that airyra is up to date", ErrIncompatible, addr, err)
+	}
+	return nil
+}
 
-	// Start the server
-	if err := StartServer(host, port); err != nil {
-		return err
+// FreePort returns a TCP port on host that nothing listens on
+func FreePort(host string) (int, error) {
+	l, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
 	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
 
-	// Wait for it to be ready
-	waitCtx, cancel := context.WithTimeout(ctx, DefaultStartTimeout)
-	defer cancel()
+// newProbeClient returns a client for the server on host:port with a short
+// timeout, so probes of a hung port return quickly
+func newProbeClient(host string, port int, project string) (*Client, error) {
+	sdkClient, err := sdk.NewClient(
+		sdk.WithHost(host),
+		sdk.WithPort(port),
+		sdk.WithProject(project),
+		sdk.WithAgentID(defaultAgentID()),
+		sdk.WithTimeout(2*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create airyra client: %w", err)
+	}
+	return &Client{sdk: sdkClient, project: project}, nil
+}
+
+// portInUse reports whether anything accepts TCP connections on host:port
+func portInUse(host string, port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), 2*time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
 
-	return WaitForServer(waitCtx, host, port)
+// isProcessAlive reports whether a process with pid exists
+func isProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// Signal 0 doesn't kill - just checks if process exists
+	return syscall.Kill(pid, syscall.Signal(0)) == nil
 }
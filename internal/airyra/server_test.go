@@ -0,0 +1,177 @@
+package airyra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "airyra/pkg/airyra"
+)
+
+// fakeAiryra puts an airyra on PATH that logs its arguments and sleeps
+func fakeAiryra(t *testing.T) {
+	t.Helper()
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"airyra $*\"\nexec sleep 60\n"
+	if err := os.WriteFile(filepath.Join(bin, "airyra"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestServer_StartStop(t *testing.T) {
+	fakeAiryra(t)
+	server := NewServer(t.TempDir(), "localhost", 7999)
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	pid := server.PID()
+	if pid == 0 {
+		t.Fatal("PID() = 0 after Start()")
+	}
+	if port := server.ManagedPort(); port != 7999 {
+		t.Errorf("ManagedPort() = %d, want 7999", port)
+	}
+	if err := server.Start(); !errors.Is(err, ErrServerRunning) {
+		t.Errorf("second Start() error = %v, want ErrServerRunning", err)
+	}
+
+	// The script writes once it is running
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(server.LogPath())
+		if strings.Contains(string(data), "airyra server start --bind localhost:7999") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("log = %q, want the server's output", data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if isProcessAlive(pid) {
+		t.Errorf("pid %d still alive after Stop()", pid)
+	}
+	if _, err := os.Stat(server.PidPath()); !os.IsNotExist(err) {
+		t.Errorf("pidfile left after Stop(): %v", err)
+	}
+}
+
+func TestServer_StalePidfile(t *testing.T) {
+	server := NewServer(t.TempDir(), "localhost", 7999)
+	os.MkdirAll(filepath.Dir(server.PidPath()), 0755)
+	os.WriteFile(server.PidPath(), []byte("999999999\n"), 0644)
+
+	if pid := server.PID(); pid != 0 {
+		t.Errorf("PID() = %d, want 0 for a dead process", pid)
+	}
+	if err := server.Stop(context.Background()); !errors.Is(err, ErrNotManaged) {
+		t.Errorf("Stop() error = %v, want ErrNotManaged", err)
+	}
+	if _, err := os.Stat(server.PidPath()); !os.IsNotExist(err) {
+		t.Errorf("stale pidfile not removed: %v", err)
+	}
+}
+
+// listen occupies a free port on localhost for the rest of the test
+func listen(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestProbe(t *testing.T) {
+	ctx := context.Background()
+	free, err := FreePort("localhost")
+	if err != nil {
+		t.Fatalf("FreePort() error = %v", err)
+	}
+	taken := listen(t)
+
+	down := NewMockClient()
+	down.ServerRunning = false
+
+	unhealthy := NewMockClient()
+	unhealthy.OnHealth = func(ctx context.Context) error { return ErrServerUnhealthy }
+
+	oldAPI := NewMockClient()
+	oldAPI.OnListTasks = func(ctx context.Context, opts ...sdk.ListTasksOption) (*TaskList, error) {
+		return nil, errors.New("404 page not found")
+	}
+
+	tests := []struct {
+		name         string
+		client       TaskClient
+		port         int
+		checkProject bool
+		want         error
+	}{
+		{"ready", NewMockClient(), taken, true, nil},
+		{"nothing listening", down, free, true, ErrServerNotRunning},
+		{"another service on the port", unhealthy, taken, true, ErrPortConflict},
+		{"task API not served", oldAPI, taken, true, ErrIncompatible},
+		{"task API not checked", oldAPI, taken, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Probe(ctx, tt.client, "localhost", tt.port, tt.checkProject)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Probe() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_EnsureRunningPortConflict(t *testing.T) {
+	server := NewServer(t.TempDir(), "localhost", listen(t))
+	unhealthy := NewMockClient()
+	unhealthy.OnHealth = func(ctx context.Context) error { return ErrServerUnhealthy }
+	server.client = unhealthy
+
+	if err := server.EnsureRunning(context.Background()); !errors.Is(err, ErrPortConflict) {
+		t.Fatalf("EnsureRunning() error = %v, want ErrPortConflict", err)
+	}
+	if _, err := os.Stat(server.PidPath()); !os.IsNotExist(err) {
+		t.Error("EnsureRunning() started a server on a taken port")
+	}
+}
+
+func TestServer_EnsureRunningOnOtherPort(t *testing.T) {
+	free, err := FreePort("localhost")
+	if err != nil {
+		t.Fatalf("FreePort() error = %v", err)
+	}
+	server := NewServer(t.TempDir(), "localhost", free)
+	down := NewMockClient()
+	down.ServerRunning = false
+	server.client = down
+
+	// The project's server, this process standing in for it, runs on
+	// another port
+	os.MkdirAll(filepath.Dir(server.PidPath()), 0755)
+	os.WriteFile(server.PidPath(), []byte(fmt.Sprintf("%d %d\n", os.Getpid(), free+1)), 0644)
+
+	if port := server.ManagedPort(); port != free+1 {
+		t.Errorf("ManagedPort() = %d, want %d", port, free+1)
+	}
+	err = server.EnsureRunning(context.Background())
+	if !errors.Is(err, ErrServerRunning) || !strings.Contains(err.Error(), fmt.Sprintf("port %d", free+1)) {
+		t.Errorf("EnsureRunning() error = %v, want ErrServerRunning on port %d", err, free+1)
+	}
+}
@@ -20,6 +20,7 @@ type TaskClient interface {
 	GetTask(ctx context.Context, id string) (*Task, error)
 	ListTasks(ctx context.Context, opts ...sdk.ListTasksOption) (*TaskList, error)
 	ListReadyTasks(ctx context.Context) (*TaskList, error)
+	UpdateTask(ctx context.Context, id string, update TaskUpdate) (*Task, error)
 	DeleteTask(ctx context.Context, id string) error
 	ClearDoneTasks(ctx context.Context) (int, error)
 	ClearAllTasks(ctx context.Context) (int, error)
@@ -43,6 +44,7 @@ type (
 	TaskStatus = sdk.TaskStatus
 	TaskList   = sdk.TaskList
 	Dependency = sdk.Dependency
+	TaskUpdate = sdk.TaskUpdate // Nil fields are left unchanged
 )
 
 // Re-export status constants
@@ -69,6 +71,21 @@ var (
 	WithPerPage = sdk.WithPerPage
 )
 
+// ListAllTasks returns every task matching opts, a page at a time
+func ListAllTasks(ctx context.Context, client TaskClient, opts ...sdk.ListTasksOption) ([]*Task, error) {
+	var tasks []*Task
+	for page := 1; ; page++ {
+		list, err := client.ListTasks(ctx, append(opts, WithPage(page), WithPerPage(100))...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+		tasks = append(tasks, list.Tasks...)
+		if page >= list.TotalPages || len(list.Tasks) == 0 {
+			return tasks, nil
+		}
+	}
+}
+
 // PriorityFromString converts CLI priority names to int values
 func PriorityFromString(s string) (int, error) {
 	switch strings.ToLower(s) {
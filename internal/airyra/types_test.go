@@ -1,7 +1,11 @@
 package airyra
 
 import (
+	"context"
+	"errors"
 	"testing"
+
+	sdk "airyra/pkg/airyra"
 )
 
 func TestPriorityFromString(t *testing.T) {
@@ -124,3 +128,31 @@ func TestStatusConstants(t *testing.T) {
 		})
 	}
 }
+
+func TestListAllTasks(t *testing.T) {
+	client := NewMockClient()
+	calls := 0
+	client.OnListTasks = func(ctx context.Context, opts ...sdk.ListTasksOption) (*TaskList, error) {
+		calls++
+		return &TaskList{Tasks: []*Task{{ID: "ar-" + string(rune('0'+calls))}}, Page: calls, TotalPages: 3}, nil
+	}
+
+	tasks, err := ListAllTasks(context.Background(), client, WithStatus(StatusInProgress))
+	if err != nil {
+		t.Fatalf("ListAllTasks() error = %v", err)
+	}
+	if calls != 3 || len(tasks) != 3 {
+		t.Errorf("ListAllTasks() fetched %d pages and %d tasks, want 3 and 3", calls, len(tasks))
+	}
+}
+
+func TestListAllTasks_Error(t *testing.T) {
+	client := NewMockClient()
+	client.OnListTasks = func(ctx context.Context, opts ...sdk.ListTasksOption) (*TaskList, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	if _, err := ListAllTasks(context.Background(), client); err == nil {
+		t.Error("ListAllTasks() error = nil, want the list failure")
+	}
+}
@@ -0,0 +1,81 @@
+package airyra
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"isollm/internal/state"
+)
+
+// Users records which projects use the airyra server on an address, so
+// one project's isollm down does not stop a server another still needs.
+// Each project is a file in ~/.isollm/airyra/<host>-<port> holding its root.
+type Users struct {
+	dir string
+}
+
+// NewUsers returns the users of the airyra server on host:port
+func NewUsers(host string, port int) (*Users, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return NewUsersWithDir(filepath.Join(home, ".isollm", "airyra", fmt.Sprintf("%s-%d", host, port))), nil
+}
+
+// NewUsersWithDir returns users recorded in dir
+func NewUsersWithDir(dir string) *Users {
+	return &Users{dir: dir}
+}
+
+// Add records that the project at projectRoot uses the server
+func (u *Users) Add(projectRoot string) error {
+	if err := state.WriteFile(u.path(projectRoot), []byte(projectRoot+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to record airyra user: %w", err)
+	}
+	return nil
+}
+
+// Remove forgets the project at projectRoot
+func (u *Users) Remove(projectRoot string) error {
+	if err := os.Remove(u.path(projectRoot)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove airyra user: %w", err)
+	}
+	return nil
+}
+
+// List returns the roots of the projects recorded as using the server
+func (u *Users) List() ([]string, error) {
+	entries, err := os.ReadDir(u.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list airyra users: %w", err)
+	}
+
+	var roots []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(u.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if root := strings.TrimSpace(string(data)); root != "" {
+			roots = append(roots, root)
+		}
+	}
+	return roots, nil
+}
+
+// path returns the file recording projectRoot, named by a hash of the root
+func (u *Users) path(projectRoot string) string {
+	sum := sha256.Sum256([]byte(projectRoot))
+	return filepath.Join(u.dir, hex.EncodeToString(sum[:8]))
+}
@@ -0,0 +1,42 @@
+package airyra
+
+import (
+	"testing"
+)
+
+func TestUsers(t *testing.T) {
+	users := NewUsersWithDir(t.TempDir())
+
+	if roots, err := users.List(); err != nil || len(roots) != 0 {
+		t.Fatalf("List() = %v, %v, want none", roots, err)
+	}
+
+	users.Add("/src/a")
+	users.Add("/src/b")
+	users.Add("/src/a")
+	roots, err := users.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(roots) != 2 {
+		t.Errorf("List() = %v, want /src/a and /src/b", roots)
+	}
+
+	if err := users.Remove("/src/a"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := users.Remove("/src/a"); err != nil {
+		t.Errorf("second Remove() error = %v", err)
+	}
+	roots, _ = users.List()
+	if len(roots) != 1 || roots[0] != "/src/b" {
+		t.Errorf("List() = %v, want [/src/b]", roots)
+	}
+}
+
+func TestUsers_MissingDir(t *testing.T) {
+	users := NewUsersWithDir(t.TempDir() + "/missing")
+	if roots, err := users.List(); err != nil || roots != nil {
+		t.Errorf("List() = %v, %v, want nil, nil", roots, err)
+	}
+}
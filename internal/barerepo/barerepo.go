@@ -1,11 +1,15 @@
 package barerepo
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"isollm/internal/git"
 )
@@ -31,6 +35,21 @@ type BranchInfo struct {
 	Subject    string // Latest commit subject
 }
 
+// DiffStat summarises the changes on a branch
+type DiffStat struct {
+	Files      int `json:"files"`
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+}
+
+// BranchOverlap describes two task branches that changed the same files
+type BranchOverlap struct {
+	BranchA  string
+	BranchB  string
+	Paths    []string // Files changed on both branches since base
+	Conflict bool     // A trial merge of the two branches conflicts
+}
+
 // New creates a BareRepo instance for an existing bare repo
 func New(barePath string) *BareRepo {
 	return &BareRepo{
@@ -234,3 +253,122 @@ func (b *BareRepo) HasUnpushedCommits(branchName, baseBranch string) (bool, erro
 	}
 	return count > 0, nil
 }
+
+// LastCommitTime returns the committer time of the latest commit on a branch
+func (b *BareRepo) LastCommitTime(branchName string) (time.Time, error) {
+	output, err := b.executor.Run(b.path, "log", "-1", "--format=%ct", branchName)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last commit time: %w", err)
+	}
+
+	secs, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse commit time: %w", err)
+	}
+
+	return time.Unix(secs, 0), nil
+}
+
+// ChangedPaths returns the files a branch changed since it forked from base
+func (b *BareRepo) ChangedPaths(branchName, baseBranch string) ([]string, error) {
+	output, err := b.executor.Run(b.path, "diff", "--name-only", baseBranch+"..."+branchName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes on %s: %w", branchName, err)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// DiffStat counts the files and lines a branch changed since it forked
+// from base. Binary files count as changed files with no lines.
+func (b *BareRepo) DiffStat(branchName, baseBranch string) (DiffStat, error) {
+	output, err := b.executor.Run(b.path, "diff", "--numstat", baseBranch+"..."+branchName)
+	if err != nil {
+		return DiffStat{}, fmt.Errorf("failed to diff %s: %w", branchName, err)
+	}
+
+	var stat DiffStat
+	if output == "" {
+		return stat, nil
+	}
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		stat.Files++
+		if n, err := strconv.Atoi(parts[0]); err == nil {
+			stat.Insertions += n
+		}
+		if n, err := strconv.Atoi(parts[1]); err == nil {
+			stat.Deletions += n
+		}
+	}
+	return stat, nil
+}
+
+// TrialMerge merges two branches in memory (nothing is written to any
+// branch) and reports whether the merge conflicts
+func (b *BareRepo) TrialMerge(branchA, branchB string) (bool, error) {
+	_, err := b.executor.Run(b.path, "merge-tree", "--write-tree", "--name-only", "--no-messages", branchA, branchB)
+	if err == nil {
+		return false, nil
+	}
+
+	// merge-tree exits with 1 when the merge has conflicts
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return true, nil
+	}
+	return false, fmt.Errorf("failed to merge %s with %s: %w", branchA, branchB, err)
+}
+
+// FindOverlaps compares every pair of branches and returns the pairs that
+// changed the same files, with a trial merge to tell real conflicts from
+// edits that merge cleanly
+func (b *BareRepo) FindOverlaps(branches []string, baseBranch string) ([]BranchOverlap, error) {
+	changed := make(map[string]map[string]bool, len(branches))
+	for _, branch := range branches {
+		paths, err := b.ChangedPaths(branch, baseBranch)
+		if err != nil {
+			return nil, err
+		}
+		set := make(map[string]bool, len(paths))
+		for _, p := range paths {
+			set[p] = true
+		}
+		changed[branch] = set
+	}
+
+	var overlaps []BranchOverlap
+	for i, a := range branches {
+		for _, other := range branches[i+1:] {
+			var shared []string
+			for p := range changed[a] {
+				if changed[other][p] {
+					shared = append(shared, p)
+				}
+			}
+			if len(shared) == 0 {
+				continue // Disjoint changes cannot conflict
+			}
+			sort.Strings(shared)
+
+			conflict, err := b.TrialMerge(a, other)
+			if err != nil {
+				return nil, err
+			}
+
+			overlaps = append(overlaps, BranchOverlap{
+				BranchA:  a,
+				BranchB:  other,
+				Paths:    shared,
+				Conflict: conflict,
+			})
+		}
+	}
+
+	return overlaps, nil
+}
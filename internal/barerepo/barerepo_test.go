@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGetMountPath(t *testing.T) {
@@ -255,6 +256,144 @@ func TestDeleteBranch(t *testing.T) {
 	}
 }
 
+func TestLastCommitTime(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	bareDir := filepath.Join(tmpDir, "project.git")
+
+	setupTestRepo(t, projectDir)
+
+	repo, err := Create(projectDir, bareDir)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Create a task branch with a commit at a known time
+	when := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", "task work")
+	cmd.Dir = projectDir
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+when.Format(time.RFC3339))
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	cmd = exec.Command("git", "push", bareDir, "HEAD:refs/heads/isollm/ar-time")
+	cmd.Dir = projectDir
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to push branch: %v", err)
+	}
+
+	got, err := repo.LastCommitTime("isollm/ar-time")
+	if err != nil {
+		t.Fatalf("LastCommitTime failed: %v", err)
+	}
+	if !got.Equal(when) {
+		t.Errorf("expected %v, got %v", when, got)
+	}
+
+	// Missing branch is an error
+	if _, err := repo.LastCommitTime("isollm/ar-missing"); err == nil {
+		t.Error("expected error for missing branch")
+	}
+}
+
+func TestFindOverlaps(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	bareDir := filepath.Join(tmpDir, "project.git")
+
+	setupTestRepo(t, projectDir)
+
+	repo, err := Create(projectDir, bareDir)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Two branches rewrite README.md differently; the third only adds a file
+	pushBranch := func(branch string, files map[string]string) {
+		t.Helper()
+		git := func(args ...string) {
+			t.Helper()
+			cmd := exec.Command("git", args...)
+			cmd.Dir = projectDir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v\n%s", args, err, out)
+			}
+		}
+		git("checkout", "-q", "-B", branch, "master")
+		for name, content := range files {
+			os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644)
+		}
+		git("add", ".")
+		git("commit", "-q", "-m", "work on "+branch)
+		git("push", "-q", bareDir, branch+":"+branch)
+	}
+
+	pushBranch("isollm/ar-0001", map[string]string{"README.md": "# One", "shared.txt": "a\nb\nc\n"})
+	pushBranch("isollm/ar-0002", map[string]string{"README.md": "# Two"})
+	pushBranch("isollm/ar-0003", map[string]string{"other.txt": "x"})
+
+	paths, err := repo.ChangedPaths("isollm/ar-0001", "master")
+	if err != nil {
+		t.Fatalf("ChangedPaths failed: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Errorf("expected 2 changed paths, got %v", paths)
+	}
+
+	stat, err := repo.DiffStat("isollm/ar-0001", "master")
+	if err != nil {
+		t.Fatalf("DiffStat failed: %v", err)
+	}
+	if want := (DiffStat{Files: 2, Insertions: 4, Deletions: 1}); stat != want {
+		t.Errorf("expected diffstat %+v, got %+v", want, stat)
+	}
+
+	overlaps, err := repo.FindOverlaps([]string{"isollm/ar-0001", "isollm/ar-0002", "isollm/ar-0003"}, "master")
+	if err != nil {
+		t.Fatalf("FindOverlaps failed: %v", err)
+	}
+	if len(overlaps) != 1 {
+		t.Fatalf("expected 1 overlap, got %+v", overlaps)
+	}
+
+	o := overlaps[0]
+	if o.BranchA != "isollm/ar-0001" || o.BranchB != "isollm/ar-0002" {
+		t.Errorf("unexpected pair %s / %s", o.BranchA, o.BranchB)
+	}
+	if len(o.Paths) != 1 || o.Paths[0] != "README.md" {
+		t.Errorf("expected README.md overlap, got %v", o.Paths)
+	}
+	if !o.Conflict {
+		t.Error("expected trial merge to conflict")
+	}
+}
+
+func TestTrialMerge_Clean(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	bareDir := filepath.Join(tmpDir, "project.git")
+
+	setupTestRepo(t, projectDir)
+
+	repo, err := Create(projectDir, bareDir)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	cmd := exec.Command("git", "branch", "isollm/ar-a", "master")
+	cmd.Dir = bareDir
+	cmd.Run()
+
+	conflict, err := repo.TrialMerge("isollm/ar-a", "master")
+	if err != nil {
+		t.Fatalf("TrialMerge failed: %v", err)
+	}
+	if conflict {
+		t.Error("expected identical branches to merge cleanly")
+	}
+}
+
 func setupTestRepo(t *testing.T, projectDir string) {
 	t.Helper()
 
@@ -25,26 +25,51 @@ func GenerateCLAUDEMD(ctx *Context) string {
 	}
 	b.WriteString("\n")
 
+	// Assigned task
+	if ctx.TaskID != "" {
+		b.WriteString("## Current Task\n\n")
+		b.WriteString(fmt.Sprintf("- **ID**: %s\n", ctx.TaskID))
+		b.WriteString(fmt.Sprintf("- **Title**: %s\n", ctx.TaskTitle))
+		b.WriteString("\n")
+		if ctx.TaskDescription != "" {
+			b.WriteString(ctx.TaskDescription)
+			b.WriteString("\n\n")
+		}
+		b.WriteString("This task has already been claimed for you and its branch is checked out.\n")
+		b.WriteString("When it is done (or released), exit the session so isollm can assign your next task.\n\n")
+	}
+
+	if ctx.Plan {
+		writePlanSection(&b)
+	}
+
 	// Task workflow
 	b.WriteString("## Task Workflow\n\n")
-	b.WriteString("### 1. Claiming a Task\n\n")
-	b.WriteString("Before starting work, claim a task from airyra:\n\n")
-	b.WriteString("```bash\n")
-	b.WriteString("# List available tasks\n")
-	b.WriteString(fmt.Sprintf("airyra task list --host %s --port %d\n\n", ctx.AiryraHost, ctx.AiryraPort))
-	b.WriteString("# Claim the next ready task\n")
-	b.WriteString(fmt.Sprintf("airyra task claim --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
-	b.WriteString("```\n\n")
+	if ctx.TaskID != "" {
+		b.WriteString("### 1. Claiming a Task\n\n")
+		b.WriteString(fmt.Sprintf("Task %s was claimed on your behalf by isollm. Do not claim another task.\n\n", ctx.TaskID))
+		b.WriteString("### 2. Creating a Task Branch\n\n")
+		b.WriteString(fmt.Sprintf("Branch `%s` is already checked out. Commit all work for this task to it.\n\n", ctx.TaskBranch))
+	} else {
+		b.WriteString("### 1. Claiming a Task\n\n")
+		b.WriteString("Before starting work, claim a task from airyra:\n\n")
+		b.WriteString("```bash\n")
+		b.WriteString("# List available tasks\n")
+		b.WriteString(fmt.Sprintf("airyra task list --host %s --port %d\n\n", ctx.AiryraHost, ctx.AiryraPort))
+		b.WriteString("# Claim the next ready task\n")
+		b.WriteString(fmt.Sprintf("airyra task claim --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
+		b.WriteString("```\n\n")
 
-	b.WriteString("### 2. Creating a Task Branch\n\n")
-	b.WriteString("After claiming a task, create a branch for your work:\n\n")
-	b.WriteString("```bash\n")
-	b.WriteString("# Ensure you're on the base branch\n")
-	b.WriteString(fmt.Sprintf("git checkout %s\n", ctx.BaseBranch))
-	b.WriteString("git pull origin %s\n\n")
-	b.WriteString("# Create task branch (use the task ID from airyra)\n")
-	b.WriteString("git checkout -b isollm/<task-id>\n")
-	b.WriteString("```\n\n")
+		b.WriteString("### 2. Creating a Task Branch\n\n")
+		b.WriteString("After claiming a task, create a branch for your work:\n\n")
+		b.WriteString("```bash\n")
+		b.WriteString("# Ensure you're on the base branch\n")
+		b.WriteString(fmt.Sprintf("git checkout %s\n", ctx.BaseBranch))
+		b.WriteString("git pull origin %s\n\n")
+		b.WriteString("# Create task branch (use the task ID from airyra)\n")
+		b.WriteString("git checkout -b isollm/<task-id>\n")
+		b.WriteString("```\n\n")
+	}
 
 	// Git workflow
 	b.WriteString("### 3. Git Commit Workflow\n\n")
@@ -68,22 +93,58 @@ func GenerateCLAUDEMD(ctx *Context) string {
 
 	// Completing task
 	b.WriteString("### 4. Completing a Task\n\n")
-	b.WriteString("When the task is done:\n\n")
-	b.WriteString("```bash\n")
-	b.WriteString("# Ensure all changes are pushed\n")
-	b.WriteString("git push origin HEAD\n\n")
-	b.WriteString("# Mark task as complete\n")
-	b.WriteString(fmt.Sprintf("airyra task done --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
-	b.WriteString("```\n\n")
+	// Only a task assigned by the dispatcher is completed through the
+	// marker; nothing reads it for tasks Claude claims itself
+	if ctx.TaskID != "" {
+		b.WriteString("When the task is done, push your work and ask isollm to complete it:\n\n")
+		b.WriteString("```bash\n")
+		b.WriteString("# Ensure all changes are pushed\n")
+		b.WriteString("git push origin HEAD\n\n")
+		b.WriteString("# Request verification and completion\n")
+		b.WriteString(fmt.Sprintf("touch %s\n", DoneMarkerPath))
+		b.WriteString("```\n\n")
+		if len(ctx.VerifyCommands) > 0 {
+			b.WriteString("isollm runs these checks on your branch before marking the task done:\n\n")
+			writeVerifyCommands(&b, ctx.VerifyCommands)
+			b.WriteString("\nRun them yourself first. If one fails, the task is blocked with the output attached.\n")
+		}
+		b.WriteString("Do not run `airyra task done` yourself.\n\n")
+	} else {
+		if len(ctx.VerifyCommands) > 0 {
+			b.WriteString("Before marking a task done, run these checks and fix any failure:\n\n")
+			writeVerifyCommands(&b, ctx.VerifyCommands)
+			b.WriteString("\n")
+		}
+		b.WriteString("When the task is done:\n\n")
+		b.WriteString("```bash\n")
+		b.WriteString("# Ensure all changes are pushed\n")
+		b.WriteString("git push origin HEAD\n\n")
+		b.WriteString("# Mark task as complete\n")
+		b.WriteString(fmt.Sprintf("airyra task done --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
+		b.WriteString("```\n\n")
+	}
 
 	// Handling blocks
 	b.WriteString("### 5. Handling Blockers\n\n")
 	b.WriteString("If you encounter a blocker:\n\n")
-	b.WriteString("```bash\n")
-	b.WriteString("# Mark task as blocked\n")
-	b.WriteString(fmt.Sprintf("airyra task block --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
-	b.WriteString("```\n\n")
-	b.WriteString("Describe the blocker clearly so it can be addressed.\n\n")
+	if ctx.TaskID != "" {
+		b.WriteString("```bash\n")
+		b.WriteString("# Push your work, then describe the blocker or ask your question\n")
+		b.WriteString("git push origin HEAD\n")
+		b.WriteString(fmt.Sprintf("echo \"Which API version should the client target?\" > %s\n", BlockedMarkerPath))
+		b.WriteString("```\n\n")
+		b.WriteString("isollm blocks the task and shows your question to a human. Describe the blocker\n")
+		b.WriteString("clearly, with what you tried and the options you see, then stop working. isollm\n")
+		b.WriteString("ends this session once the task is blocked. When a human answers, the answer is\n")
+		b.WriteString("appended to this file under \"Answer to blocker\", the task is unblocked and this\n")
+		b.WriteString("conversation is resumed with the answer.\n\n")
+	} else {
+		b.WriteString("```bash\n")
+		b.WriteString("# Mark task as blocked\n")
+		b.WriteString(fmt.Sprintf("airyra task block --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
+		b.WriteString("```\n\n")
+		b.WriteString("Describe the blocker clearly so it can be addressed.\n\n")
+	}
 
 	// Releasing task
 	b.WriteString("### 6. Releasing a Task\n\n")
@@ -113,7 +174,11 @@ func GenerateCLAUDEMD(ctx *Context) string {
 	b.WriteString("## Important Notes\n\n")
 	b.WriteString("1. **Always push your work** - The bare repo is the bridge between this container and the host.\n")
 	b.WriteString("2. **Commit frequently** - Small, focused commits are easier to review and merge.\n")
-	b.WriteString("3. **Communicate blockers** - Use `airyra task block` immediately when stuck.\n")
+	if ctx.TaskID != "" {
+		b.WriteString(fmt.Sprintf("3. **Communicate blockers** - Write your question to `%s` immediately when stuck.\n", BlockedMarkerPath))
+	} else {
+		b.WriteString("3. **Communicate blockers** - Use `airyra task block` immediately when stuck.\n")
+	}
 	b.WriteString("4. **One task at a time** - Complete or release a task before claiming another.\n")
 	b.WriteString("5. **Stay in your branch** - Don't modify the base branch directly.\n\n")
 
@@ -127,6 +192,38 @@ func GenerateCLAUDEMD(ctx *Context) string {
 	return b.String()
 }
 
+// writeVerifyCommands lists the checks a task must pass
+func writeVerifyCommands(b *strings.Builder, commands []string) {
+	for _, cmd := range commands {
+		b.WriteString(fmt.Sprintf("- `%s`\n", cmd))
+	}
+}
+
+// writePlanSection tells a planner how to hand back its subtasks
+func writePlanSection(b *strings.Builder) {
+	b.WriteString("## Planning This Task\n\n")
+	b.WriteString("This is a planning task. Do not implement it. Read the code, work out how to split\n")
+	b.WriteString("the task into subtasks that can each be done on their own branch, and write them to\n")
+	b.WriteString(fmt.Sprintf("`%s`:\n\n", PlanMarkerPath))
+	b.WriteString("```yaml\n")
+	b.WriteString("tasks:\n")
+	b.WriteString("  - id: schema\n")
+	b.WriteString("    title: Add the sessions table\n")
+	b.WriteString("    description: Migration and model for sessions, keyed by user.\n")
+	b.WriteString("    priority: high\n")
+	b.WriteString("  - id: login\n")
+	b.WriteString("    title: Add the login endpoint\n")
+	b.WriteString("    description: POST /login creates a session and returns its token.\n")
+	b.WriteString("    depends_on: [schema]\n")
+	b.WriteString("```\n\n")
+	b.WriteString("Each subtask needs a title and should say enough for another worker to do it\n")
+	b.WriteString("without this conversation. `id` names a subtask for `depends_on`; priority is one of\n")
+	b.WriteString("critical, high, normal, low or lowest. Write the whole file in one go.\n\n")
+	b.WriteString("isollm adds the subtasks to the queue, this task waits for them and is marked done\n")
+	b.WriteString("when they all are. Once the file is written, exit the session. Do not commit code or\n")
+	b.WriteString("mark this task done yourself. An invalid plan blocks the task with the reason.\n\n")
+}
+
 // GenerateMinimalCLAUDEMD generates a minimal CLAUDE.md for quick reference.
 func GenerateMinimalCLAUDEMD(ctx *Context) string {
 	var b strings.Builder
@@ -138,8 +235,16 @@ func GenerateMinimalCLAUDEMD(ctx *Context) string {
 	b.WriteString("```bash\n")
 	b.WriteString(fmt.Sprintf("# List tasks:    airyra task list --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
 	b.WriteString(fmt.Sprintf("# Claim task:    airyra task claim --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
-	b.WriteString(fmt.Sprintf("# Complete:      airyra task done --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
-	b.WriteString(fmt.Sprintf("# Block:         airyra task block --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
+	if ctx.TaskID != "" {
+		b.WriteString(fmt.Sprintf("# Complete:      touch %s  (isollm verifies first)\n", DoneMarkerPath))
+	} else {
+		b.WriteString(fmt.Sprintf("# Complete:      airyra task done --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
+	}
+	if ctx.TaskID != "" {
+		b.WriteString(fmt.Sprintf("# Block:         echo \"<question>\" > %s\n", BlockedMarkerPath))
+	} else {
+		b.WriteString(fmt.Sprintf("# Block:         airyra task block --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
+	}
 	b.WriteString(fmt.Sprintf("# Release:       airyra task release --host %s --port %d\n", ctx.AiryraHost, ctx.AiryraPort))
 	b.WriteString("```\n")
 
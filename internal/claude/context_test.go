@@ -272,6 +272,150 @@ func TestGenerateCLAUDEMD_WithoutTaskBranch(t *testing.T) {
 	}
 }
 
+func TestGenerateCLAUDEMD_WithAssignedTask(t *testing.T) {
+	ctx := &Context{
+		ProjectName:     "myproject",
+		WorkerName:      "worker-1",
+		TaskBranch:      "isollm/ar-0001",
+		BaseBranch:      "main",
+		AiryraHost:      "localhost",
+		AiryraPort:      7432,
+		TaskID:          "ar-0001",
+		TaskTitle:       "Add login endpoint",
+		TaskDescription: "POST /login returning a JWT.",
+	}
+
+	result := GenerateCLAUDEMD(ctx)
+
+	for _, want := range []string{
+		"## Current Task",
+		"**ID**: ar-0001",
+		"**Title**: Add login endpoint",
+		"POST /login returning a JWT.",
+		"Branch `isollm/ar-0001` is already checked out",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("GenerateCLAUDEMD() missing %q", want)
+		}
+	}
+
+	// The worker must not be told to claim another task
+	if strings.Contains(result, "airyra task claim") {
+		t.Error("GenerateCLAUDEMD() with assigned task should not include claim command")
+	}
+}
+
+func TestGenerateCLAUDEMD_BlockerQuestion(t *testing.T) {
+	ctx := &Context{
+		ProjectName: "myproject",
+		WorkerName:  "worker-1",
+		TaskBranch:  "isollm/ar-0001",
+		BaseBranch:  "main",
+		TaskID:      "ar-0001",
+	}
+
+	result := GenerateCLAUDEMD(ctx)
+
+	if !strings.Contains(result, "> "+BlockedMarkerPath) {
+		t.Errorf("GenerateCLAUDEMD() missing the blocker marker %s", BlockedMarkerPath)
+	}
+	if strings.Contains(result, "airyra task block") {
+		t.Error("GenerateCLAUDEMD() with assigned task should ask questions through isollm, not airyra task block")
+	}
+	if !strings.Contains(GenerateMinimalCLAUDEMD(ctx), BlockedMarkerPath) {
+		t.Errorf("GenerateMinimalCLAUDEMD() missing the blocker marker %s", BlockedMarkerPath)
+	}
+}
+
+func TestGenerateCLAUDEMD_PlanTask(t *testing.T) {
+	ctx := &Context{
+		ProjectName: "myproject",
+		WorkerName:  "worker-1",
+		TaskBranch:  "isollm/ar-0001",
+		BaseBranch:  "main",
+		TaskID:      "ar-0001",
+		TaskTitle:   "Add user accounts",
+	}
+
+	if strings.Contains(GenerateCLAUDEMD(ctx), "## Planning This Task") {
+		t.Error("GenerateCLAUDEMD() has planning instructions for a normal task")
+	}
+
+	ctx.Plan = true
+	result := GenerateCLAUDEMD(ctx)
+	for _, want := range []string{"## Planning This Task", PlanMarkerPath, "depends_on:", "Do not implement it"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("GenerateCLAUDEMD() for a planning task missing %q", want)
+		}
+	}
+}
+
+func TestGenerateCLAUDEMD_WithoutAssignedTask(t *testing.T) {
+	ctx := &Context{
+		ProjectName: "myproject",
+		WorkerName:  "worker-1",
+		BaseBranch:  "main",
+		AiryraHost:  "localhost",
+		AiryraPort:  7432,
+	}
+
+	result := GenerateCLAUDEMD(ctx)
+
+	if strings.Contains(result, "## Current Task") {
+		t.Error("GenerateCLAUDEMD() without task should not include Current Task section")
+	}
+	if !strings.Contains(result, "airyra task claim") {
+		t.Error("GenerateCLAUDEMD() without task should include claim command")
+	}
+}
+
+func TestGenerateCLAUDEMD_WithVerifyCommands(t *testing.T) {
+	ctx := &Context{
+		ProjectName:    "myproject",
+		WorkerName:     "worker-1",
+		BaseBranch:     "main",
+		AiryraHost:     "localhost",
+		AiryraPort:     7432,
+		TaskID:         "ar-0001",
+		TaskTitle:      "Add login",
+		VerifyCommands: []string{"go build ./...", "go test ./..."},
+	}
+
+	result := GenerateCLAUDEMD(ctx)
+
+	for _, want := range []string{"touch " + DoneMarkerPath, "`go build ./...`", "`go test ./...`"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("GenerateCLAUDEMD() with verify commands should contain %q", want)
+		}
+	}
+	if strings.Contains(result, "airyra task done --host") {
+		t.Error("GenerateCLAUDEMD() with verify commands should not tell Claude to run airyra task done")
+	}
+}
+
+func TestGenerateCLAUDEMD_VerifyCommandsWithoutDispatch(t *testing.T) {
+	ctx := &Context{
+		ProjectName:    "myproject",
+		WorkerName:     "worker-1",
+		BaseBranch:     "main",
+		AiryraHost:     "localhost",
+		AiryraPort:     7432,
+		VerifyCommands: []string{"go test ./..."},
+	}
+
+	result := GenerateCLAUDEMD(ctx)
+
+	// Nothing reads the done marker for tasks Claude claims itself
+	if strings.Contains(result, DoneMarkerPath) {
+		t.Error("GenerateCLAUDEMD() without an assigned task should not use the done marker")
+	}
+	for _, want := range []string{"airyra task done --host", "`go test ./...`"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("GenerateCLAUDEMD() without an assigned task should contain %q", want)
+		}
+	}
+}
+
 func TestGenerateCLAUDEMD_TableFormat(t *testing.T) {
 	ctx := &Context{
 		ProjectName: "myproject",
@@ -3,6 +3,7 @@ package claude
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"isollm/internal/config"
@@ -15,6 +16,15 @@ const (
 	DefaultProjectPath = "/home/dev/project"
 	// DefaultBareRepoPath is the default bare repo mount path
 	DefaultBareRepoPath = "/repo.git"
+	// DoneMarkerPath is created to ask isollm to verify and complete a task
+	DoneMarkerPath = "/home/dev/.isollm-done"
+	// BlockedMarkerPath is written with a question to block a task until a
+	// human answers it
+	BlockedMarkerPath = "/home/dev/.isollm-blocked"
+	// PlanMarkerPath is written with the subtasks of a planning task
+	PlanMarkerPath = "/home/dev/.isollm-plan.yaml"
+	// LogMountPath is where the worker's host log directory is mounted
+	LogMountPath = "/home/dev/.isollm-logs"
 )
 
 // ContainerExecer interface for executing commands in containers
@@ -64,27 +74,112 @@ func (l *Launcher) PrepareWorker(workerName string, taskBranch string) error {
 
 	// Build context for CLAUDE.md
 	ctx := &Context{
-		ProjectName: l.cfg.Project,
-		WorkerName:  workerName,
-		TaskBranch:  taskBranch,
-		BaseBranch:  l.cfg.Git.BaseBranch,
-		AiryraHost:  l.hostIP,
-		AiryraPort:  l.cfg.Airyra.Port,
+		ProjectName:    l.cfg.Project,
+		WorkerName:     workerName,
+		TaskBranch:     taskBranch,
+		BaseBranch:     l.cfg.Git.BaseBranch,
+		AiryraHost:     l.hostIP,
+		AiryraPort:     l.cfg.Airyra.Port,
+		VerifyCommands: l.cfg.Verify.Commands,
 	}
 
 	// Generate and write CLAUDE.md
 	if err := l.writeCLAUDEMD(workerName, ctx); err != nil {
 		return fmt.Errorf("failed to write CLAUDE.md: %w", err)
 	}
+	if err := l.excludeCLAUDEMD(workerName); err != nil {
+		return fmt.Errorf("failed to exclude CLAUDE.md from git: %w", err)
+	}
 
 	// Add source of env file to .bashrc
 	if err := l.setupBashrc(workerName); err != nil {
 		return fmt.Errorf("failed to setup .bashrc: %w", err)
 	}
 
+	if err := l.ensureLogDir(workerName); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	return nil
+}
+
+// PrepareTask rewrites CLAUDE.md in a worker with the details of a task that
+// has been claimed on its behalf. PrepareWorker must have been called first.
+func (l *Launcher) PrepareTask(workerName string, task *TaskAssignment) error {
+	ctx := &Context{
+		ProjectName:     l.cfg.Project,
+		WorkerName:      workerName,
+		TaskBranch:      task.Branch,
+		BaseBranch:      l.cfg.Git.BaseBranch,
+		AiryraHost:      l.hostIP,
+		AiryraPort:      l.cfg.Airyra.Port,
+		TaskID:          task.ID,
+		TaskTitle:       task.Title,
+		TaskDescription: task.Description,
+		Plan:            task.Plan,
+		VerifyCommands:  append(slices.Clone(l.cfg.Verify.Commands), task.Verify...),
+	}
+
+	if err := l.ensureLogDir(workerName); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	if err := l.writeCLAUDEMD(workerName, ctx); err != nil {
+		return fmt.Errorf("failed to write CLAUDE.md: %w", err)
+	}
+
 	return nil
 }
 
+// GetTaskLaunchCommand returns the command to launch Claude in a worker
+// with the given task as its initial prompt.
+func (l *Launcher) GetTaskLaunchCommand(task *TaskAssignment) []string {
+	cmd := l.GetLaunchCommand()
+	return append(cmd, TaskPrompt(task))
+}
+
+// GetResumeCommand returns the command to resume the last Claude
+// conversation in a worker with prompt as the next message.
+func (l *Launcher) GetResumeCommand(prompt string) []string {
+	cmd := l.GetLaunchCommand()
+	return append(cmd, "--continue", prompt)
+}
+
+// TaskPrompt returns the initial prompt given to Claude for a task.
+func TaskPrompt(task *TaskAssignment) string {
+	if task.Plan {
+		return fmt.Sprintf("Plan task %s: %s. Split it into subtasks as CLAUDE.md describes; do not implement it yourself.",
+			task.ID, task.Title)
+	}
+	return fmt.Sprintf("Work on task %s: %s. CLAUDE.md has the full description and workflow.",
+		task.ID, task.Title)
+}
+
+// WithLog wraps a launch command in script(1) so everything it prints to
+// the pane is also appended to <name>.log in the worker's log directory.
+func WithLog(cmd []string, name string) []string {
+	return []string{
+		"script", "-q", "-f", "-a",
+		"-c", ShellCommand(cmd),
+		filepath.Join(LogMountPath, name+".log"),
+	}
+}
+
+// ShellCommand joins cmd into a line for sh, quoting every argument so
+// none of it is expanded or run by the shell
+func ShellCommand(cmd []string) string {
+	quoted := make([]string, len(cmd))
+	for i, arg := range cmd {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes s for use as a single word in sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // GetLaunchCommand returns the command to launch Claude in a worker.
 func (l *Launcher) GetLaunchCommand() []string {
 	cmd := []string{l.cfg.Claude.Command}
@@ -104,12 +199,13 @@ func (l *Launcher) GetLaunchConfig(workerName, taskBranch string) *LaunchConfig
 	)
 
 	ctx := &Context{
-		ProjectName: l.cfg.Project,
-		WorkerName:  workerName,
-		TaskBranch:  taskBranch,
-		BaseBranch:  l.cfg.Git.BaseBranch,
-		AiryraHost:  l.hostIP,
-		AiryraPort:  l.cfg.Airyra.Port,
+		ProjectName:    l.cfg.Project,
+		WorkerName:     workerName,
+		TaskBranch:     taskBranch,
+		BaseBranch:     l.cfg.Git.BaseBranch,
+		AiryraHost:     l.hostIP,
+		AiryraPort:     l.cfg.Airyra.Port,
+		VerifyCommands: l.cfg.Verify.Commands,
 	}
 
 	return &LaunchConfig{
@@ -123,27 +219,50 @@ func (l *Launcher) GetLaunchConfig(workerName, taskBranch string) *LaunchConfig
 
 // writeEnvFile writes the environment file to the container.
 func (l *Launcher) writeEnvFile(workerName string, env *Environment) error {
-	content := env.ToEnvFile()
+	_, err := l.execer.Exec(workerName, writeFileCommand(EnvFilePath, env.ToEnvFile()))
+	return err
+}
+
+// writeCLAUDEMD writes the CLAUDE.md file to the project directory.
+func (l *Launcher) writeCLAUDEMD(workerName string, ctx *Context) error {
+	claudeMDPath := filepath.Join(DefaultProjectPath, "CLAUDE.md")
 
-	// Use printf to write the file to avoid issues with special characters
+	_, err := l.execer.Exec(workerName, writeFileCommand(claudeMDPath, GenerateCLAUDEMD(ctx)))
+	return err
+}
+
+// writeFileCommand returns a command writing content to path in a worker.
+// The content is single-quoted so backticks, $ and newlines in it, e.g.
+// from task descriptions or verify commands, are written as they are
+// instead of being run by the shell.
+func writeFileCommand(path, content string) []string {
+	return []string{
+		"bash", "-c",
+		fmt.Sprintf("printf '%%s' %s > %s", shellQuote(content), shellQuote(path)),
+	}
+}
+
+// excludeCLAUDEMD adds the generated CLAUDE.md to the project clone's
+// .git/info/exclude so `git add -A` in the worker does not commit it. A
+// CLAUDE.md the project tracks itself is not affected by the exclude.
+func (l *Launcher) excludeCLAUDEMD(workerName string) error {
 	cmd := []string{
 		"bash", "-c",
-		fmt.Sprintf("printf '%%s' %q > %s", content, EnvFilePath),
+		fmt.Sprintf("exclude=%s; grep -qxF /CLAUDE.md \"$exclude\" 2>/dev/null || echo /CLAUDE.md >> \"$exclude\"",
+			shellQuote(DefaultProjectPath+"/.git/info/exclude")),
 	}
 
 	_, err := l.execer.Exec(workerName, cmd)
 	return err
 }
 
-// writeCLAUDEMD writes the CLAUDE.md file to the project directory.
-func (l *Launcher) writeCLAUDEMD(workerName string, ctx *Context) error {
-	content := GenerateCLAUDEMD(ctx)
-	claudeMDPath := filepath.Join(DefaultProjectPath, "CLAUDE.md")
-
-	// Use printf to write the file
+// ensureLogDir makes sure script(1) has somewhere to write in workers
+// created before the host log directory was mounted. Output written there
+// stays inside the container.
+func (l *Launcher) ensureLogDir(workerName string) error {
 	cmd := []string{
 		"bash", "-c",
-		fmt.Sprintf("printf '%%s' %q > %s", content, claudeMDPath),
+		fmt.Sprintf("[ -d %s ] || install -d -o dev -g dev %s", LogMountPath, LogMountPath),
 	}
 
 	_, err := l.execer.Exec(workerName, cmd)
@@ -2,6 +2,9 @@ package claude
 
 import (
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -208,6 +211,23 @@ func TestPrepareWorker_WritesCLAUDEMD(t *testing.T) {
 	}
 }
 
+func TestPrepareWorker_ExcludesCLAUDEMD(t *testing.T) {
+	mock := NewMockContainerExecer()
+
+	launcher, _ := NewLauncher(testConfig(), mock)
+	if err := launcher.PrepareWorker("worker-01", "isollm/task-123"); err != nil {
+		t.Fatalf("PrepareWorker() error = %v", err)
+	}
+
+	for _, call := range mock.ExecCalls {
+		cmdStr := strings.Join(call.Cmd, " ")
+		if strings.Contains(cmdStr, DefaultProjectPath+"/.git/info/exclude") && strings.Contains(cmdStr, "echo /CLAUDE.md") {
+			return
+		}
+	}
+	t.Error("PrepareWorker() did not add CLAUDE.md to .git/info/exclude")
+}
+
 func TestPrepareWorker_SetupsBashrc(t *testing.T) {
 	mock := NewMockContainerExecer()
 	mock.ExecFunc = func(name string, cmd []string) ([]byte, error) {
@@ -409,6 +429,111 @@ func TestGetLaunchCommand(t *testing.T) {
 	}
 }
 
+func TestPrepareTask_WritesTaskIntoCLAUDEMD(t *testing.T) {
+	mock := NewMockContainerExecer()
+	cfg := testConfig()
+	launcher, _ := NewLauncher(cfg, mock)
+
+	task := &TaskAssignment{
+		ID:          "ar-0001",
+		Title:       "Add login endpoint",
+		Description: "POST /login",
+		Branch:      "isollm/ar-0001",
+		Verify:      []string{"go test ./internal/auth/..."},
+	}
+
+	if err := launcher.PrepareTask("worker-01", task); err != nil {
+		t.Fatalf("PrepareTask() error = %v", err)
+	}
+
+	call := mock.LastCall()
+	if call == nil {
+		t.Fatal("PrepareTask() made no exec calls")
+	}
+	cmdStr := strings.Join(call.Cmd, " ")
+	if !strings.Contains(cmdStr, "CLAUDE.md") {
+		t.Errorf("PrepareTask() did not write CLAUDE.md: %s", cmdStr)
+	}
+	if !strings.Contains(cmdStr, "Add login endpoint") {
+		t.Errorf("PrepareTask() CLAUDE.md missing task title: %s", cmdStr)
+	}
+	if !strings.Contains(cmdStr, "go test ./internal/auth/...") {
+		t.Errorf("PrepareTask() CLAUDE.md missing the task's verify command: %s", cmdStr)
+	}
+}
+
+func TestPrepareTask_WriteError(t *testing.T) {
+	mock := NewMockContainerExecer()
+	mock.ExecError = errors.New("exec failed")
+	launcher, _ := NewLauncher(testConfig(), mock)
+
+	err := launcher.PrepareTask("worker-01", &TaskAssignment{ID: "ar-0001"})
+	if err == nil {
+		t.Fatal("PrepareTask() error = nil, want error")
+	}
+}
+
+func TestGetTaskLaunchCommand(t *testing.T) {
+	cfg := testConfig()
+	launcher, _ := NewLauncher(cfg, NewMockContainerExecer())
+
+	task := &TaskAssignment{ID: "ar-0001", Title: "Add login endpoint"}
+	cmd := launcher.GetTaskLaunchCommand(task)
+
+	want := len(cfg.Claude.Args) + 2
+	if len(cmd) != want {
+		t.Fatalf("GetTaskLaunchCommand() len = %d, want %d", len(cmd), want)
+	}
+	if cmd[0] != cfg.Claude.Command {
+		t.Errorf("GetTaskLaunchCommand()[0] = %q, want %q", cmd[0], cfg.Claude.Command)
+	}
+	prompt := cmd[len(cmd)-1]
+	if !strings.Contains(prompt, "ar-0001") || !strings.Contains(prompt, "Add login endpoint") {
+		t.Errorf("GetTaskLaunchCommand() prompt = %q, missing task details", prompt)
+	}
+
+	// Base launch command must be unaffected
+	if len(launcher.GetLaunchCommand()) != want-1 {
+		t.Error("GetTaskLaunchCommand() modified the base launch command")
+	}
+}
+
+func TestGetResumeCommand(t *testing.T) {
+	cfg := testConfig()
+	launcher, _ := NewLauncher(cfg, NewMockContainerExecer())
+
+	cmd := launcher.GetResumeCommand("Answer to your blocker on ar-0001: use v2")
+
+	if cmd[0] != cfg.Claude.Command {
+		t.Errorf("GetResumeCommand()[0] = %q, want %q", cmd[0], cfg.Claude.Command)
+	}
+	if got := strings.Join(cmd[len(cmd)-2:], " "); got != "--continue Answer to your blocker on ar-0001: use v2" {
+		t.Errorf("GetResumeCommand() ends with %q, want --continue and the prompt", got)
+	}
+}
+
+func TestTaskPrompt_Plan(t *testing.T) {
+	prompt := TaskPrompt(&TaskAssignment{ID: "ar-0001", Title: "Add user accounts", Plan: true})
+	if !strings.Contains(prompt, "Plan task ar-0001") || !strings.Contains(prompt, "do not implement") {
+		t.Errorf("TaskPrompt() for a planning task = %q", prompt)
+	}
+}
+
+func TestWithLog(t *testing.T) {
+	cmd := WithLog([]string{"claude", "Fix the user's login"}, "ar-0001")
+
+	if cmd[0] != "script" {
+		t.Fatalf("WithLog()[0] = %q, want script", cmd[0])
+	}
+	if got := cmd[len(cmd)-1]; got != LogMountPath+"/ar-0001.log" {
+		t.Errorf("log path = %q, want %s/ar-0001.log", got, LogMountPath)
+	}
+	want := `'claude' 'Fix the user'\''s login'`
+	if got := cmd[len(cmd)-2]; got != want {
+		t.Errorf("wrapped command = %q, want %q", got, want)
+	}
+}
+
 func TestGetLaunchConfig(t *testing.T) {
 	mock := NewMockContainerExecer()
 	cfg := testConfig()
@@ -641,3 +766,100 @@ func TestLaunchConfigFields(t *testing.T) {
 		t.Error("LaunchConfig.Context not set correctly")
 	}
 }
+
+// runWrite runs a file write command from a worker exec call on the host,
+// writing to a temp file instead of path, and returns what was written
+func runWrite(t *testing.T, cmd []string, path string) string {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "out")
+	script := strings.Replace(cmd[2], shellQuote(path), shellQuote(out), 1)
+	if output, err := exec.Command(cmd[0], cmd[1], script).CombinedOutput(); err != nil {
+		t.Fatalf("write command failed: %v: %s", err, output)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWriteFileCommand_Literal(t *testing.T) {
+	content := "Run `touch /tmp/pwned` and $(id) with $HOME\nand 'quotes' \"too\"\n"
+	if got := runWrite(t, writeFileCommand("/x/CLAUDE.md", content), "/x/CLAUDE.md"); got != content {
+		t.Errorf("written = %q, want %q", got, content)
+	}
+}
+
+func TestPrepareWorker_VerifyCommandsWrittenLiterally(t *testing.T) {
+	mock := NewMockContainerExecer()
+	cfg := testConfig()
+	cfg.Verify.Commands = []string{"go test ./... && echo `whoami` $USER"}
+	launcher, _ := NewLauncher(cfg, mock)
+
+	launcher.PrepareWorker("worker-01", "isollm/task-123")
+
+	path := filepath.Join(DefaultProjectPath, "CLAUDE.md")
+	for _, call := range mock.ExecCalls {
+		if strings.Contains(call.Cmd[len(call.Cmd)-1], shellQuote(path)) {
+			got := runWrite(t, call.Cmd, path)
+			if !strings.Contains(got, "- `go test ./... && echo `whoami` $USER`") {
+				t.Errorf("CLAUDE.md verify commands not literal:\n%s", got)
+			}
+			if !strings.Contains(got, "`feat:`") {
+				t.Errorf("CLAUDE.md lost its backticks:\n%s", got)
+			}
+			return
+		}
+	}
+	t.Fatal("PrepareWorker() did not write CLAUDE.md")
+}
+
+func TestPrepareTask_TaskTextWrittenLiterally(t *testing.T) {
+	mock := NewMockContainerExecer()
+	launcher, _ := NewLauncher(testConfig(), mock)
+
+	task := &TaskAssignment{
+		ID:          "ar-0001",
+		Title:       "Fix $(rm -rf ~) parsing",
+		Description: "Line one with `id`\nLine two with $HOME",
+		Branch:      "isollm/ar-0001",
+	}
+	if err := launcher.PrepareTask("worker-01", task); err != nil {
+		t.Fatalf("PrepareTask() error = %v", err)
+	}
+
+	got := runWrite(t, mock.LastCall().Cmd, filepath.Join(DefaultProjectPath, "CLAUDE.md"))
+	for _, want := range []string{task.Title, "Line one with `id`\nLine two with $HOME"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CLAUDE.md missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestPrepareTask_PlanSectionWrittenLiterally(t *testing.T) {
+	mock := NewMockContainerExecer()
+	launcher, _ := NewLauncher(testConfig(), mock)
+
+	task := &TaskAssignment{ID: "ar-0001", Title: "Add user accounts", Branch: "isollm/ar-0001", Plan: true}
+	if err := launcher.PrepareTask("worker-01", task); err != nil {
+		t.Fatalf("PrepareTask() error = %v", err)
+	}
+
+	got := runWrite(t, mock.LastCall().Cmd, filepath.Join(DefaultProjectPath, "CLAUDE.md"))
+	for _, want := range []string{"`" + PlanMarkerPath + "`", "`id` names a subtask for `depends_on`"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CLAUDE.md missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	cmd := []string{"printf", "%s|", "Work on task ar-1: `id` $(whoami) $HOME 'x'"}
+	out, err := exec.Command("sh", "-c", ShellCommand(cmd)).Output()
+	if err != nil {
+		t.Fatalf("sh error = %v", err)
+	}
+	if want := cmd[2] + "|"; string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
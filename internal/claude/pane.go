@@ -0,0 +1,120 @@
+package claude
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// PaneFIFOPath is read by a worker's pane for the next command to run
+	PaneFIFOPath = "/home/dev/.isollm-pane"
+	// PanePIDPath holds the PID of the command a worker's pane is running.
+	// It is removed when the command exits.
+	PanePIDPath = "/home/dev/.isollm-pane.pid"
+
+	// paneWriteTimeout is how long, in seconds, a command waits for the
+	// pane to read it
+	paneWriteTimeout = 5
+	// paneStopTimeout is how long, in seconds, a stopped command gets to
+	// exit before it is killed
+	paneStopTimeout = 5
+)
+
+var (
+	// ErrPaneBusy is returned when a worker's pane is still running a command
+	ErrPaneBusy = errors.New("worker pane is still running a command")
+	// ErrNoPane is returned when no pane is waiting for commands in a worker
+	ErrNoPane = errors.New("no worker pane is waiting for commands")
+)
+
+// PaneCommand returns the command a worker's zellij pane runs in its
+// container. It waits for command lines on PaneFIFOPath and runs each in
+// the pane's terminal, so isollm can start and stop Claude in the right
+// pane without typing into it or depending on which pane has focus.
+func PaneCommand() []string {
+	return []string{"su", "-l", "dev", "-c", paneScript(PaneFIFOPath, PanePIDPath, EnvFilePath, DefaultProjectPath)}
+}
+
+// RunInPane starts cmd in a worker's pane. It fails with ErrPaneBusy if the
+// pane is still running a command and ErrNoPane if no pane reads it.
+func RunInPane(execer ContainerExecer, workerName string, cmd []string) error {
+	if PaneBusy(execer, workerName) {
+		return fmt.Errorf("%w in %s", ErrPaneBusy, workerName)
+	}
+	if _, err := execer.Exec(workerName, []string{"sh", "-c", paneWriteScript(PaneFIFOPath), "sh", ShellCommand(cmd)}); err != nil {
+		return fmt.Errorf("%w in %s: %v", ErrNoPane, workerName, err)
+	}
+	return nil
+}
+
+// PaneBusy reports whether a worker's pane is running a command
+func PaneBusy(execer ContainerExecer, workerName string) bool {
+	_, err := execer.Exec(workerName, []string{"sh", "-c", paneBusyScript(PanePIDPath)})
+	return err == nil
+}
+
+// StopPane stops the command a worker's pane is running, if any, and
+// waits for the pane to be ready for the next one
+func StopPane(execer ContainerExecer, workerName string) error {
+	if _, err := execer.Exec(workerName, []string{"sh", "-c", paneStopScript(PanePIDPath)}); err != nil {
+		return fmt.Errorf("failed to stop the pane command in %s: %w", workerName, err)
+	}
+	return nil
+}
+
+// paneScript is run by a worker's pane. Each command runs in the
+// background with the pane's terminal as its input, so its PID can be
+// recorded, and a trapped Ctrl-C reaches it without ending the loop.
+func paneScript(fifo, pidFile, envFile, workDir string) string {
+	return fmt.Sprintf(`fifo=%s
+pidfile=%s
+trap 'rm -f "$fifo" "$pidfile"' EXIT
+trap 'exit 1' HUP TERM
+trap : INT
+rm -f "$fifo" "$pidfile"
+mkfifo -m 600 "$fifo" || exit 1
+[ -f %[3]s ] && . %[3]s
+cd %s 2>/dev/null
+exec 3<&0
+while :; do
+	echo "isollm: waiting for a task"
+	line=$(cat "$fifo") || continue
+	[ -n "$line" ] || continue
+	sh -c "exec $line" <&3 3<&- &
+	pid=$!
+	echo "$pid" > "$pidfile"
+	while kill -0 "$pid" 2>/dev/null; do wait "$pid"; done
+	rm -f "$pidfile"
+done
+`, shellQuote(fifo), shellQuote(pidFile), shellQuote(envFile), shellQuote(workDir))
+}
+
+// paneWriteScript writes its first argument to the pane's FIFO, giving up
+// if nothing reads it in time
+func paneWriteScript(fifo string) string {
+	return fmt.Sprintf(`[ -p %[1]s ] || exit 1
+exec timeout %[2]d sh -c 'printf "%%s" "$1" > "$2"' sh "$1" %[1]s
+`, shellQuote(fifo), paneWriteTimeout)
+}
+
+// paneBusyScript succeeds if the pane's command is still running
+func paneBusyScript(pidFile string) string {
+	return fmt.Sprintf(`pid=$(cat %s 2>/dev/null) && kill -0 "$pid" 2>/dev/null`, shellQuote(pidFile))
+}
+
+// paneStopScript terminates the pane's command and its children, killing
+// them if they outlive paneStopTimeout
+func paneStopScript(pidFile string) string {
+	return fmt.Sprintf(`pid=$(cat %s 2>/dev/null) || exit 0
+kill -0 "$pid" 2>/dev/null || exit 0
+pkill -TERM -P "$pid"; kill -TERM "$pid"
+i=0
+while [ $i -lt %d ]; do
+	kill -0 "$pid" 2>/dev/null || exit 0
+	sleep 0.5
+	i=$((i + 1))
+done
+pkill -KILL -P "$pid"; kill -KILL "$pid"
+exit 0
+`, shellQuote(pidFile), paneStopTimeout*2)
+}
@@ -0,0 +1,129 @@
+package claude
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startPane runs the pane script on the host with its files in a temp dir
+func startPane(t *testing.T) (dir, fifo, pidFile string) {
+	t.Helper()
+	dir = t.TempDir()
+	fifo = filepath.Join(dir, "pane")
+	pidFile = filepath.Join(dir, "pane.pid")
+
+	runner := exec.Command("sh", "-c", paneScript(fifo, pidFile, filepath.Join(dir, "env"), dir))
+	if err := runner.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		runner.Process.Kill()
+		runner.Wait()
+	})
+
+	waitFor(t, "the pane FIFO", func() bool {
+		info, err := os.Stat(fifo)
+		return err == nil && info.Mode()&os.ModeNamedPipe != 0
+	})
+	return dir, fifo, pidFile
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func busy(t *testing.T, pidFile string) bool {
+	t.Helper()
+	_, err := exec.Command("sh", "-c", paneBusyScript(pidFile)).CombinedOutput()
+	return err == nil
+}
+
+func TestPaneScript_RunsAndStopsCommands(t *testing.T) {
+	dir, fifo, pidFile := startPane(t)
+	out := filepath.Join(dir, "out")
+
+	line := ShellCommand([]string{"sh", "-c", `printf '%s' "$1" > out; sleep 30`, "sh", "Task `id` $HOME 'x'"})
+	if output, err := exec.Command("sh", "-c", paneWriteScript(fifo), "sh", line).CombinedOutput(); err != nil {
+		t.Fatalf("write failed: %v: %s", err, output)
+	}
+
+	waitFor(t, "the command to run", func() bool { return busy(t, pidFile) })
+	waitFor(t, "the command output", func() bool {
+		data, _ := os.ReadFile(out)
+		return string(data) == "Task `id` $HOME 'x'"
+	})
+
+	if output, err := exec.Command("sh", "-c", paneStopScript(pidFile)).CombinedOutput(); err != nil {
+		t.Fatalf("stop failed: %v: %s", err, output)
+	}
+	waitFor(t, "the pane to be idle", func() bool { return !busy(t, pidFile) })
+
+	// The pane takes the next command once the first has stopped
+	os.Remove(out)
+	line = ShellCommand([]string{"sh", "-c", "echo again > out"})
+	if output, err := exec.Command("sh", "-c", paneWriteScript(fifo), "sh", line).CombinedOutput(); err != nil {
+		t.Fatalf("second write failed: %v: %s", err, output)
+	}
+	waitFor(t, "the second command", func() bool {
+		data, _ := os.ReadFile(out)
+		return strings.TrimSpace(string(data)) == "again"
+	})
+}
+
+func TestPaneStopScript_Idle(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pane.pid")
+	if output, err := exec.Command("sh", "-c", paneStopScript(pidFile)).CombinedOutput(); err != nil {
+		t.Errorf("stop without a command failed: %v: %s", err, output)
+	}
+}
+
+func TestPaneWriteScript_NoPane(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "pane")
+	if err := exec.Command("sh", "-c", paneWriteScript(fifo), "sh", "true").Run(); err == nil {
+		t.Error("write without a pane succeeded")
+	}
+}
+
+func TestRunInPane_Busy(t *testing.T) {
+	mock := NewMockContainerExecer()
+
+	err := RunInPane(mock, "worker-1", []string{"claude"})
+	if !errors.Is(err, ErrPaneBusy) {
+		t.Fatalf("RunInPane() error = %v, want ErrPaneBusy", err)
+	}
+	if mock.CallCount() != 1 {
+		t.Errorf("ran %d commands, want only the busy check", mock.CallCount())
+	}
+}
+
+func TestRunInPane_NoPane(t *testing.T) {
+	mock := NewMockContainerExecer()
+	mock.ExecError = errors.New("exit status 1")
+
+	if err := RunInPane(mock, "worker-1", []string{"claude"}); !errors.Is(err, ErrNoPane) {
+		t.Fatalf("RunInPane() error = %v, want ErrNoPane", err)
+	}
+}
+
+func TestPaneCommand(t *testing.T) {
+	cmd := PaneCommand()
+	if strings.Join(cmd[:4], " ") != "su -l dev -c" {
+		t.Fatalf("PaneCommand() = %v, want su -l dev -c <script>", cmd[:4])
+	}
+	for _, want := range []string{PaneFIFOPath, PanePIDPath, EnvFilePath, DefaultProjectPath} {
+		if !strings.Contains(cmd[4], want) {
+			t.Errorf("pane script missing %s", want)
+		}
+	}
+}
@@ -32,6 +32,16 @@ type Context struct {
 	AiryraHost string
 	// AiryraPort is the port for airyra commands
 	AiryraPort int
+	// TaskID is the airyra task already claimed for this worker (empty if none)
+	TaskID string
+	// TaskTitle is the title of the claimed task
+	TaskTitle string
+	// TaskDescription is the full description of the claimed task
+	TaskDescription string
+	// Plan is set when the task is to be split into subtasks, not implemented
+	Plan bool
+	// VerifyCommands are the checks isollm runs before a task is marked done
+	VerifyCommands []string
 	// CustomContext is additional context to include in CLAUDE.md
 	CustomContext string
 }
@@ -49,3 +59,19 @@ type LaunchConfig struct {
 	// Context holds context for CLAUDE.md generation
 	Context *Context
 }
+
+// TaskAssignment describes a task that isollm has claimed on behalf of a worker.
+type TaskAssignment struct {
+	// ID is the airyra task ID
+	ID string
+	// Title is the task title
+	Title string
+	// Description is the task description
+	Description string
+	// Branch is the task branch the worker should commit to
+	Branch string
+	// Plan is set when the worker should split the task into subtasks
+	Plan bool
+	// Verify lists checks particular to this task, run after the project's
+	Verify []string
+}
@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,19 +16,37 @@ const (
 	ConfigFileName = "isollm.yaml"
 	// StateDir is the name of the local state directory
 	StateDir = ".isollm"
+	// DefaultTaskTimeout is how long a task may go without activity before
+	// the watchdog reclaims it
+	DefaultTaskTimeout = "1h"
+	// DefaultLogRetention is how long worker logs are kept
+	DefaultLogRetention = "168h"
+	// DefaultLogMaxSizeMB is the size at which a task log is rotated
+	DefaultLogMaxSizeMB = 50
+	// DefaultHookTimeout is how long a hook may run
+	DefaultHookTimeout = 10 * time.Second
 )
 
 // Config represents the isollm.yaml configuration
 type Config struct {
-	Project string       `yaml:"project"`
-	Workers int          `yaml:"workers"`
-	Image   string       `yaml:"image"`
-	Setup   string       `yaml:"setup_script,omitempty"`
-	Git     GitConfig    `yaml:"git"`
-	Claude  ClaudeConfig `yaml:"claude"`
-	Airyra  AiryraConfig `yaml:"airyra"`
-	Ports   []string     `yaml:"ports,omitempty"`
-	Zellij  ZellijConfig `yaml:"zellij"`
+	Project     string                    `yaml:"project"`
+	Workers     int                       `yaml:"workers"`
+	Image       string                    `yaml:"image"`
+	Setup       string                    `yaml:"setup_script,omitempty"` // Run as dev in the project after clone
+	Provision   ProvisionConfig           `yaml:"provision,omitempty"`
+	TaskTimeout string                    `yaml:"task_timeout,omitempty"` // Inactivity limit for claimed tasks ("0" disables)
+	Git         GitConfig                 `yaml:"git"`
+	Claude      ClaudeConfig              `yaml:"claude"`
+	Airyra      AiryraConfig              `yaml:"airyra"`
+	Ports       []string                  `yaml:"ports,omitempty"`
+	Zellij      ZellijConfig              `yaml:"zellij"`
+	Merge       MergeConfig               `yaml:"merge,omitempty"`
+	Verify      VerifyConfig              `yaml:"verify,omitempty"`
+	Logs        LogsConfig                `yaml:"logs,omitempty"`
+	Resources   ResourcesConfig           `yaml:"resources,omitempty"`
+	Network     NetworkConfig             `yaml:"network,omitempty"`
+	Hooks       []HookConfig              `yaml:"hooks,omitempty"`
+	Templates   map[string]TemplateConfig `yaml:"templates,omitempty"`
 }
 
 // GitConfig contains git-related settings
@@ -46,6 +67,9 @@ type AiryraConfig struct {
 	Project string `yaml:"project,omitempty"`
 	Host    string `yaml:"host,omitempty"`
 	Port    int    `yaml:"port,omitempty"`
+	// AutoPort lets isollm up start airyra on a free port when Port is taken
+	// by another service; the port used is kept in the session state
+	AutoPort bool `yaml:"auto_port,omitempty"`
 }
 
 // ZellijConfig contains zellij-related settings
@@ -54,12 +78,106 @@ type ZellijConfig struct {
 	Dashboard bool   `yaml:"dashboard"`
 }
 
+// MergeConfig contains settings for landing task branches with 'isollm merge'
+type MergeConfig struct {
+	Strategy string `yaml:"strategy,omitempty"` // merge, rebase or squash
+	Verify   string `yaml:"verify,omitempty"`   // Shell command that must pass before the base branch moves
+}
+
+// VerifyConfig contains the checks a task branch must pass inside its
+// worker before the task can be marked done. They are enforced for tasks
+// 'isollm dispatch' assigns; tasks Claude claims itself are only asked to
+// run them. The gate is advisory either way: a worker holds airyra
+// credentials and could still call 'airyra task done' directly.
+type VerifyConfig struct {
+	Commands []string `yaml:"commands,omitempty"` // Run in order in the project directory
+}
+
+// LogsConfig contains settings for the worker logs in .isollm/logs
+type LogsConfig struct {
+	Retention string `yaml:"retention,omitempty"`   // Delete logs not written for this long ("0" keeps them)
+	MaxSizeMB int    `yaml:"max_size_mb,omitempty"` // Rotate a task log past this size when its task restarts (0 means the default)
+}
+
+// ResourcesConfig limits what each worker container may use.
+// Zero values leave the limit unset.
+type ResourcesConfig struct {
+	CPU       int    `yaml:"cpu,omitempty"`       // CPU cores
+	Memory    string `yaml:"memory,omitempty"`    // e.g. 4GiB or 50%
+	Disk      string `yaml:"disk,omitempty"`      // Root disk quota, e.g. 20GiB
+	Processes int    `yaml:"processes,omitempty"` // Max processes
+}
+
+// IsZero returns true if no limit is set
+func (r ResourcesConfig) IsZero() bool {
+	return r == ResourcesConfig{}
+}
+
+// ProvisionConfig describes how a new worker is set up before its clean
+// snapshot is taken, so that reset returns to a fully provisioned state
+type ProvisionConfig struct {
+	Packages []string          `yaml:"packages,omitempty"` // apt packages to install
+	Files    []FileCopy        `yaml:"files,omitempty"`    // Host files copied into the worker
+	Env      map[string]string `yaml:"env,omitempty"`      // Exported in the dev user's shell
+}
+
+// FileCopy copies a host file into workers
+type FileCopy struct {
+	Src  string `yaml:"src"`            // Relative to the project root, or ~/...
+	Dst  string `yaml:"dst"`            // Relative to /home/dev, or absolute
+	Mode string `yaml:"mode,omitempty"` // Octal permissions, e.g. "0600"
+}
+
+// NetworkConfig controls what workers can reach on the network.
+// The airyra host is always reachable.
+type NetworkConfig struct {
+	Policy string   `yaml:"policy,omitempty"` // open, airyra-only or allowlist
+	Allow  []string `yaml:"allow,omitempty"`  // Hosts, IPs or CIDRs reachable under allowlist
+}
+
+// HookConfig runs a command or POSTs to a URL when a task or session
+// event happens. The event is sent as JSON on stdin or as the request body.
+type HookConfig struct {
+	On      []string `yaml:"on"`                // task.claimed, task.done, task.blocked, worker.error, session.down
+	Command string   `yaml:"command,omitempty"` // Run with sh -c on the host
+	URL     string   `yaml:"url,omitempty"`     // http(s) URL to POST to
+	Timeout string   `yaml:"timeout,omitempty"` // Default 10s
+}
+
+// TemplateConfig describes a kind of task that is added often, with
+// 'isollm task add --template'. Title, description and verify commands are
+// Go templates over the parameters, e.g. "Fix lint in {{.pkg}}".
+type TemplateConfig struct {
+	Title       string            `yaml:"title"`
+	Description string            `yaml:"description,omitempty"`
+	Priority    string            `yaml:"priority,omitempty"`
+	Verify      []string          `yaml:"verify,omitempty"` // Run after verify.commands before the task is done
+	Params      map[string]string `yaml:"params,omitempty"` // Default parameter values
+	Each        map[string]string `yaml:"each,omitempty"`   // Parameter to glob or comma list, one task per value
+}
+
+// TemplateFuncs are the functions available in task templates
+var TemplateFuncs = template.FuncMap{
+	"base": path.Base, // internal/cache -> cache
+	"dir":  path.Dir,
+}
+
+// TimeoutDuration returns how long the hook may run
+func (h HookConfig) TimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil || d <= 0 {
+		return DefaultHookTimeout
+	}
+	return d
+}
+
 // DefaultConfig returns a config with sensible defaults
 func DefaultConfig(projectName string) *Config {
 	return &Config{
-		Project: projectName,
-		Workers: 3,
-		Image:   "ubuntu:24.04",
+		Project:     projectName,
+		Workers:     3,
+		Image:       "ubuntu:24.04",
+		TaskTimeout: DefaultTaskTimeout,
 		Git: GitConfig{
 			BaseBranch:   "main",
 			BranchPrefix: "isollm/",
@@ -75,6 +193,16 @@ func DefaultConfig(projectName string) *Config {
 			Layout:    "auto",
 			Dashboard: true,
 		},
+		Merge: MergeConfig{
+			Strategy: "merge",
+		},
+		Network: NetworkConfig{
+			Policy: "open",
+		},
+		Logs: LogsConfig{
+			Retention: DefaultLogRetention,
+			MaxSizeMB: DefaultLogMaxSizeMB,
+		},
 	}
 }
 
@@ -141,6 +269,9 @@ func applyDefaults(cfg *Config) {
 	if cfg.Image == "" {
 		cfg.Image = "ubuntu:24.04"
 	}
+	if cfg.TaskTimeout == "" {
+		cfg.TaskTimeout = DefaultTaskTimeout
+	}
 	if cfg.Git.BaseBranch == "" {
 		cfg.Git.BaseBranch = "main"
 	}
@@ -162,6 +293,43 @@ func applyDefaults(cfg *Config) {
 	if cfg.Zellij.Layout == "" {
 		cfg.Zellij.Layout = "auto"
 	}
+	if cfg.Merge.Strategy == "" {
+		cfg.Merge.Strategy = "merge"
+	}
+	if cfg.Network.Policy == "" {
+		cfg.Network.Policy = "open"
+	}
+	if cfg.Logs.Retention == "" {
+		cfg.Logs.Retention = DefaultLogRetention
+	}
+	if cfg.Logs.MaxSizeMB == 0 {
+		cfg.Logs.MaxSizeMB = DefaultLogMaxSizeMB
+	}
+}
+
+// TaskTimeoutDuration returns the parsed task timeout.
+// Zero means the watchdog is disabled.
+func (c *Config) TaskTimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(c.TaskTimeout)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// LogRetentionDuration returns the parsed log retention.
+// Zero means logs are kept forever.
+func (c *Config) LogRetentionDuration() time.Duration {
+	d, err := time.ParseDuration(c.Logs.Retention)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// LogMaxBytes returns the size at which task logs are rotated
+func (c *Config) LogMaxBytes() int64 {
+	return int64(c.Logs.MaxSizeMB) * 1024 * 1024
 }
 
 // FindProjectRoot walks up from the current directory to find isollm.yaml
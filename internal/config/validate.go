@@ -2,9 +2,13 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 )
 
 const (
@@ -27,6 +31,25 @@ var (
 	validLayouts     = map[string]struct{}{
 		"auto": {}, "horizontal": {}, "vertical": {}, "grid": {},
 	}
+	validMergeStrategies = map[string]struct{}{
+		"merge": {}, "rebase": {}, "squash": {},
+	}
+	validNetworkPolicies = map[string]struct{}{
+		"open": {}, "airyra-only": {}, "allowlist": {},
+	}
+	validHookEvents = map[string]struct{}{
+		"task.claimed": {}, "task.done": {}, "task.blocked": {}, "worker.error": {}, "session.down": {},
+	}
+	validHostname = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+	validPackage  = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.+:=~_-]*$`)
+	validEnvName  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	validSize     = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(B|kB|MB|GB|TB|KiB|MiB|GiB|TiB)$`)
+	validPercent  = regexp.MustCompile(`^[0-9]{1,3}%$`)
+
+	validPriorities = map[string]struct{}{
+		"critical": {}, "high": {}, "normal": {}, "low": {}, "lowest": {},
+	}
+	validParamName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 )
 
 // ValidationError collects multiple validation failures
@@ -122,12 +145,184 @@ func (c *Config) Validate() error {
 		errs.Add("zellij.layout must be one of: auto, horizontal, vertical, grid")
 	}
 
+	// Merge strategy
+	if _, ok := validMergeStrategies[c.Merge.Strategy]; !ok && c.Merge.Strategy != "" {
+		errs.Add("merge.strategy must be one of: merge, rebase, squash")
+	}
+
+	// Verify commands
+	for i, cmd := range c.Verify.Commands {
+		if strings.TrimSpace(cmd) == "" {
+			errs.Add(fmt.Sprintf("verify.commands[%d] is empty", i))
+		}
+	}
+
+	// Task timeout
+	if c.TaskTimeout != "" {
+		if d, err := time.ParseDuration(c.TaskTimeout); err != nil {
+			errs.Add("task_timeout must be a duration such as 30m or 2h")
+		} else if d < 0 {
+			errs.Add("task_timeout cannot be negative")
+		}
+	}
+
+	// Logs
+	if c.Logs.Retention != "" {
+		if d, err := time.ParseDuration(c.Logs.Retention); err != nil {
+			errs.Add("logs.retention must be a duration such as 72h")
+		} else if d < 0 {
+			errs.Add("logs.retention cannot be negative")
+		}
+	}
+	if c.Logs.MaxSizeMB < 0 {
+		errs.Add("logs.max_size_mb cannot be negative")
+	}
+
+	// Resource limits
+	c.Resources.validate(errs)
+
+	// Provisioning
+	for _, pkg := range c.Provision.Packages {
+		if !validPackage.MatchString(pkg) {
+			errs.Add(fmt.Sprintf("provision.packages entry %q is not a valid package name", pkg))
+		}
+	}
+	for i, f := range c.Provision.Files {
+		if f.Src == "" || f.Dst == "" {
+			errs.Add(fmt.Sprintf("provision.files[%d] needs both src and dst", i))
+		}
+		if f.Mode != "" {
+			if _, err := strconv.ParseUint(f.Mode, 8, 32); err != nil {
+				errs.Add(fmt.Sprintf("provision.files[%d].mode must be octal, e.g. 0644", i))
+			}
+		}
+	}
+	for name := range c.Provision.Env {
+		if !validEnvName.MatchString(name) {
+			errs.Add(fmt.Sprintf("provision.env name %q is not a valid variable name", name))
+		}
+	}
+
+	// Network policy
+	if _, ok := validNetworkPolicies[c.Network.Policy]; !ok && c.Network.Policy != "" {
+		errs.Add("network.policy must be one of: open, airyra-only, allowlist")
+	}
+	for _, entry := range c.Network.Allow {
+		if !validAllowEntry(entry) {
+			errs.Add(fmt.Sprintf("network.allow entry %q must be a hostname, IP address or CIDR", entry))
+		}
+	}
+
+	// Hooks
+	for i, h := range c.Hooks {
+		if len(h.On) == 0 {
+			errs.Add(fmt.Sprintf("hooks[%d].on must list at least one event", i))
+		}
+		for _, event := range h.On {
+			if _, ok := validHookEvents[event]; !ok {
+				errs.Add(fmt.Sprintf("hooks[%d].on event %q must be one of: task.claimed, task.done, task.blocked, worker.error, session.down", i, event))
+			}
+		}
+		if (h.Command == "") == (h.URL == "") {
+			errs.Add(fmt.Sprintf("hooks[%d] needs either command or url", i))
+		}
+		if h.URL != "" {
+			if u, err := url.Parse(h.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs.Add(fmt.Sprintf("hooks[%d].url must be an http or https URL", i))
+			}
+		}
+		if h.Timeout != "" {
+			if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+				errs.Add(fmt.Sprintf("hooks[%d].timeout must be a positive duration like 10s", i))
+			}
+		}
+	}
+
+	// Templates
+	for name, t := range c.Templates {
+		t.validate(name, errs)
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+func (t TemplateConfig) validate(name string, errs *ValidationError) {
+	prefix := "templates." + name
+	if strings.TrimSpace(t.Title) == "" {
+		errs.Add(prefix + ".title is required")
+	}
+	if _, ok := validPriorities[t.Priority]; !ok && t.Priority != "" {
+		errs.Add(prefix + ".priority must be one of: critical, high, normal, low, lowest")
+	}
+
+	texts := map[string]string{"title": t.Title, "description": t.Description}
+	for i, cmd := range t.Verify {
+		if strings.TrimSpace(cmd) == "" {
+			errs.Add(fmt.Sprintf("%s.verify[%d] is empty", prefix, i))
+		}
+		texts[fmt.Sprintf("verify[%d]", i)] = cmd
+	}
+	for field, text := range texts {
+		if _, err := template.New(field).Funcs(TemplateFuncs).Parse(text); err != nil {
+			errs.Add(fmt.Sprintf("%s.%s is not a valid template: %v", prefix, field, err))
+		}
+	}
+
+	for param := range t.Params {
+		if !validParamName.MatchString(param) {
+			errs.Add(fmt.Sprintf("%s.params name %q is not a valid parameter name", prefix, param))
+		}
+	}
+	for param, values := range t.Each {
+		if !validParamName.MatchString(param) {
+			errs.Add(fmt.Sprintf("%s.each name %q is not a valid parameter name", prefix, param))
+		}
+		if strings.TrimSpace(values) == "" {
+			errs.Add(fmt.Sprintf("%s.each.%s is empty", prefix, param))
+		}
+	}
+}
+
+// Validate checks worker resource limits on their own (for limits given on
+// the command line)
+func (r ResourcesConfig) Validate() error {
+	errs := &ValidationError{}
+	r.validate(errs)
 	if errs.HasErrors() {
 		return errs
 	}
 	return nil
 }
 
+func (r ResourcesConfig) validate(errs *ValidationError) {
+	if r.CPU < 0 {
+		errs.Add("resources.cpu cannot be negative")
+	}
+	if r.Memory != "" && !validSize.MatchString(r.Memory) && !validPercent.MatchString(r.Memory) {
+		errs.Add("resources.memory must be a size such as 4GiB or a percentage such as 50%")
+	}
+	if r.Disk != "" && !validSize.MatchString(r.Disk) {
+		errs.Add("resources.disk must be a size such as 20GiB")
+	}
+	if r.Processes < 0 {
+		errs.Add("resources.processes cannot be negative")
+	}
+}
+
+// validAllowEntry checks a network allowlist entry
+func validAllowEntry(entry string) bool {
+	if _, _, err := net.ParseCIDR(entry); err == nil {
+		return true
+	}
+	if net.ParseIP(entry) != nil {
+		return true
+	}
+	return validHostname.MatchString(entry)
+}
+
 // validatePort checks if a port string is valid (port or host:container format)
 func validatePort(p string) error {
 	parts := strings.Split(p, ":")
@@ -152,6 +347,9 @@ func (c *Config) Warnings() []string {
 	if c.Image != "" && !strings.Contains(c.Image, ":") {
 		warnings = append(warnings, "image has no tag, will use :latest")
 	}
+	if len(c.Network.Allow) > 0 && c.Network.Policy != "allowlist" {
+		warnings = append(warnings, "network.allow is ignored unless network.policy is allowlist")
+	}
 	return warnings
 }
 
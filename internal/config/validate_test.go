@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // validConfig returns a minimal valid configuration for testing
@@ -457,6 +458,324 @@ func TestValidate_ValidLayouts(t *testing.T) {
 	}
 }
 
+func TestValidate_MergeStrategy(t *testing.T) {
+	for _, strategy := range []string{"", "merge", "rebase", "squash"} {
+		cfg := validConfig()
+		cfg.Merge.Strategy = strategy
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("unexpected error for merge.strategy %q: %v", strategy, err)
+		}
+	}
+
+	cfg := validConfig()
+	cfg.Merge.Strategy = "octopus"
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "merge.strategy") {
+		t.Errorf("expected merge.strategy error, got: %v", err)
+	}
+}
+
+func TestValidate_EmptyVerifyCommand(t *testing.T) {
+	cfg := validConfig()
+	cfg.Verify.Commands = []string{"go build ./...", "  "}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "verify.commands[1] is empty") {
+		t.Errorf("expected verify.commands error, got: %v", err)
+	}
+}
+
+func TestValidate_InvalidTaskTimeout(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"soon", "task_timeout must be a duration"},
+		{"30", "task_timeout must be a duration"},
+		{"-5m", "task_timeout cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.TaskTimeout = tt.value
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatalf("expected error for task_timeout %q", tt.value)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected %q error, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_ValidTaskTimeouts(t *testing.T) {
+	for _, value := range []string{"", "0", "30m", "2h", "1h30m"} {
+		cfg := validConfig()
+		cfg.TaskTimeout = value
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("unexpected error for task_timeout %q: %v", value, err)
+		}
+	}
+}
+
+func TestTaskTimeoutDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"1h", time.Hour},
+		{"45m", 45 * time.Minute},
+		{"0", 0},
+		{"", 0},
+		{"bogus", 0},
+		{"-1h", 0},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.TaskTimeout = tt.value
+		if got := cfg.TaskTimeoutDuration(); got != tt.want {
+			t.Errorf("TaskTimeoutDuration(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_DefaultTaskTimeout(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ConfigFileName), []byte("project: myproject\n"), 0644)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.TaskTimeout != DefaultTaskTimeout {
+		t.Errorf("TaskTimeout = %q, want %q", cfg.TaskTimeout, DefaultTaskTimeout)
+	}
+}
+
+func TestValidate_Logs(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention string
+		maxSize   int
+		want      string
+	}{
+		{"bad retention", "a week", 0, "logs.retention must be a duration"},
+		{"negative retention", "-1h", 0, "logs.retention cannot be negative"},
+		{"negative size", "72h", -1, "logs.max_size_mb cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Logs = LogsConfig{Retention: tt.retention, MaxSizeMB: tt.maxSize}
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected %q error, got: %v", tt.want, err)
+			}
+		})
+	}
+
+	cfg := validConfig()
+	cfg.Logs = LogsConfig{Retention: "0", MaxSizeMB: 10}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := cfg.LogRetentionDuration(); got != 0 {
+		t.Errorf("LogRetentionDuration() = %v, want 0", got)
+	}
+	if got := cfg.LogMaxBytes(); got != 10*1024*1024 {
+		t.Errorf("LogMaxBytes() = %d, want 10MB", got)
+	}
+}
+
+func TestValidate_Resources(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources ResourcesConfig
+		want      string
+	}{
+		{"negative cpu", ResourcesConfig{CPU: -1}, "resources.cpu cannot be negative"},
+		{"bad memory", ResourcesConfig{Memory: "4G"}, "resources.memory must be a size"},
+		{"bad disk", ResourcesConfig{Disk: "50%"}, "resources.disk must be a size"},
+		{"negative processes", ResourcesConfig{Processes: -10}, "resources.processes cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Resources = tt.resources
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected %q error, got: %v", tt.want, err)
+			}
+		})
+	}
+
+	for _, r := range []ResourcesConfig{
+		{},
+		{CPU: 2, Memory: "4GiB", Disk: "20GiB", Processes: 1000},
+		{Memory: "50%", Disk: "1.5TB"},
+	} {
+		if err := r.Validate(); err != nil {
+			t.Errorf("unexpected error for %+v: %v", r, err)
+		}
+	}
+}
+
+func TestValidate_Network(t *testing.T) {
+	cfg := validConfig()
+	cfg.Network = NetworkConfig{Policy: "closed"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "network.policy must be one of") {
+		t.Errorf("expected network.policy error, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Network = NetworkConfig{Policy: "allowlist", Allow: []string{"proxy.golang.org", "10.0.0.0/8", "1.1.1.1", "not a host"}}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `"not a host"`) {
+		t.Fatalf("expected error for invalid allow entry, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "proxy.golang.org") || strings.Contains(err.Error(), "10.0.0.0/8") {
+		t.Errorf("valid allow entries rejected: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Network = NetworkConfig{Policy: "airyra-only", Allow: []string{"proxy.golang.org"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if w := cfg.Warnings(); len(w) == 0 || !strings.Contains(w[len(w)-1], "network.allow is ignored") {
+		t.Errorf("Warnings() = %v, want network.allow warning", w)
+	}
+}
+
+func TestValidate_Hooks(t *testing.T) {
+	cfg := validConfig()
+	cfg.Hooks = []HookConfig{
+		{On: []string{"task.done", "session.down"}, Command: "notify-send isollm"},
+		{On: []string{"task.blocked"}, URL: "https://hooks.example.com/isollm", Timeout: "5s"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Hooks = []HookConfig{
+		{On: []string{"task.finished"}, Command: "true"},
+		{Command: "true"},
+		{On: []string{"task.done"}},
+		{On: []string{"task.done"}, Command: "true", URL: "https://example.com"},
+		{On: []string{"task.done"}, URL: "ftp://example.com"},
+		{On: []string{"task.done"}, Command: "true", Timeout: "soon"},
+	}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		`hooks[0].on event "task.finished" must be one of`,
+		"hooks[1].on must list at least one event",
+		"hooks[2] needs either command or url",
+		"hooks[3] needs either command or url",
+		"hooks[4].url must be an http or https URL",
+		"hooks[5].timeout must be a positive duration",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q: %v", want, err)
+		}
+	}
+}
+
+func TestValidate_Templates(t *testing.T) {
+	cfg := validConfig()
+	cfg.Templates = map[string]TemplateConfig{
+		"lint": {
+			Title:    "Fix lint in {{base .pkg}}",
+			Priority: "low",
+			Verify:   []string{"golangci-lint run ./{{.pkg}}/..."},
+			Each:     map[string]string{"pkg": "internal/*/"},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Templates = map[string]TemplateConfig{
+		"empty": {Priority: "urgent"},
+		"broken": {
+			Title:  "Fix {{.pkg",
+			Verify: []string{" "},
+			Params: map[string]string{"my-pkg": ""},
+			Each:   map[string]string{"pkg": ""},
+		},
+	}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"templates.empty.title is required",
+		"templates.empty.priority must be one of",
+		"templates.broken.title is not a valid template",
+		"templates.broken.verify[0] is empty",
+		`templates.broken.params name "my-pkg" is not a valid parameter name`,
+		"templates.broken.each.pkg is empty",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q: %v", want, err)
+		}
+	}
+}
+
+func TestHookConfig_TimeoutDuration(t *testing.T) {
+	if d := (HookConfig{}).TimeoutDuration(); d != DefaultHookTimeout {
+		t.Errorf("default timeout = %v, want %v", d, DefaultHookTimeout)
+	}
+	if d := (HookConfig{Timeout: "3s"}).TimeoutDuration(); d != 3*time.Second {
+		t.Errorf("timeout = %v, want 3s", d)
+	}
+}
+
+func TestValidate_Provision(t *testing.T) {
+	cfg := validConfig()
+	cfg.Provision = ProvisionConfig{
+		Packages: []string{"build-essential", "python3=3.12.3-0ubuntu1", "rm -rf /"},
+		Files: []FileCopy{
+			{Src: "~/.gitconfig", Dst: ".gitconfig"},
+			{Src: ".env.worker", Dst: ""},
+			{Src: "id_ed25519", Dst: ".ssh/id_ed25519", Mode: "rw"},
+		},
+		Env: map[string]string{"GOFLAGS": "-mod=mod", "BAD-NAME": "x"},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		`"rm -rf /" is not a valid package name`,
+		"provision.files[1] needs both src and dst",
+		"provision.files[2].mode must be octal",
+		`"BAD-NAME" is not a valid variable name`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in: %v", want, err)
+		}
+	}
+	for _, unwanted := range []string{"build-essential", "python3=", "GOFLAGS", "provision.files[0]"} {
+		if strings.Contains(err.Error(), unwanted) {
+			t.Errorf("valid entry %q rejected: %v", unwanted, err)
+		}
+	}
+}
+
 func TestValidate_MultipleErrors(t *testing.T) {
 	cfg := &Config{
 		Project: "",  // error 1
@@ -0,0 +1,341 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"isollm/internal/status"
+)
+
+const (
+	// DefaultInterval is the default time between refreshes
+	DefaultInterval = 2 * time.Second
+
+	// collectTimeout bounds a single refresh
+	collectTimeout = 30 * time.Second
+)
+
+// Collector gathers the status shown on each refresh (status.Collector)
+type Collector interface {
+	Collect(ctx context.Context) (*status.Status, error)
+}
+
+// Actions are the worker operations bound to keys
+type Actions interface {
+	// Release gives the worker's task back to the queue
+	Release(ctx context.Context, worker string) error
+	// Reset releases the worker's task and resets it to its clean snapshot
+	Reset(ctx context.Context, worker string) error
+	// Shell opens a shell in the worker. It runs with the terminal
+	// restored to normal mode.
+	Shell(worker string) error
+}
+
+// Terminal switches the input terminal between key-at-a-time and normal
+// line mode
+type Terminal interface {
+	Raw() error
+	Restore() error
+	Width() int
+}
+
+// action is a key-bound action awaiting confirmation
+type action struct {
+	prompt string
+	run    func(ctx context.Context) (string, error)
+}
+
+// collected is the outcome of a background Collect
+type collected struct {
+	status *status.Status
+	err    error
+}
+
+// Dashboard is a live, auto-refreshing status view with keyboard actions
+type Dashboard struct {
+	collector Collector
+	actions   Actions
+	term      Terminal // nil when the input is not a terminal
+	in        io.Reader
+	out       io.Writer
+	interval  time.Duration
+
+	status   *status.Status
+	err      error
+	selected int
+	message  string
+	pending  *action
+
+	// results receives the status collected in the background, so a slow
+	// collection does not hold up keys. At most one runs at a time; again
+	// asks for another once it is done because its result is out of date.
+	results    chan collected
+	collecting bool
+	again      bool
+
+	// readMu is held while waiting for a key, so the input can be handed
+	// to a shell
+	readMu sync.Mutex
+}
+
+// New creates a Dashboard. With a nil term the view refreshes but keys
+// are not read.
+func New(collector Collector, actions Actions, term Terminal, in io.Reader, out io.Writer) *Dashboard {
+	return &Dashboard{
+		collector: collector,
+		actions:   actions,
+		term:      term,
+		in:        in,
+		out:       out,
+		interval:  DefaultInterval,
+		results:   make(chan collected, 1),
+	}
+}
+
+// SetInterval sets the time between refreshes
+func (d *Dashboard) SetInterval(interval time.Duration) {
+	if interval > 0 {
+		d.interval = interval
+	}
+}
+
+// Run shows the dashboard until the context is cancelled or q is pressed
+func (d *Dashboard) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keys := make(chan string)
+	if d.term != nil {
+		if err := d.term.Raw(); err != nil {
+			return fmt.Errorf("failed to set up terminal: %w", err)
+		}
+		defer d.term.Restore()
+		go d.readKeys(ctx, keys)
+	}
+
+	fmt.Fprint(d.out, enterScreen)
+	defer fmt.Fprint(d.out, leaveScreen)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	d.refresh(ctx)
+	for {
+		d.draw()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.refresh(ctx)
+		case r := <-d.results:
+			d.apply(ctx, r)
+		case key := <-keys:
+			if d.HandleKey(ctx, key) {
+				return nil
+			}
+		}
+	}
+}
+
+// refresh starts collecting a new status in the background. The last
+// status is shown until it is done.
+func (d *Dashboard) refresh(ctx context.Context) {
+	if d.collecting {
+		d.again = true
+		return
+	}
+	d.collecting = true
+
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, collectTimeout)
+		defer cancel()
+
+		s, err := d.collector.Collect(ctx)
+		d.results <- collected{status: s, err: err}
+	}()
+}
+
+// apply shows a collected status, starting the next collection if one was
+// asked for meanwhile
+func (d *Dashboard) apply(ctx context.Context, r collected) {
+	d.collecting = false
+	d.err = r.err
+	if r.err == nil {
+		d.status = r.status
+		d.clampSelection()
+	}
+
+	if d.again {
+		d.again = false
+		d.refresh(ctx)
+	}
+}
+
+// HandleKey applies a key press and reports whether the dashboard should
+// quit
+func (d *Dashboard) HandleKey(ctx context.Context, key string) bool {
+	if d.pending != nil {
+		a := d.pending
+		d.pending = nil
+		if key != "y" && key != "Y" {
+			d.message = "Cancelled"
+			return false
+		}
+		msg, err := a.run(ctx)
+		if err != nil {
+			d.message = "Error: " + err.Error()
+		} else {
+			d.message = msg
+		}
+		d.refresh(ctx)
+		return false
+	}
+
+	d.message = ""
+	switch key {
+	case "q", KeyCtrlC:
+		return true
+	case "k", KeyUp:
+		if d.selected > 0 {
+			d.selected--
+		}
+	case "j", KeyDown:
+		d.selected++
+		d.clampSelection()
+	case " ", "g":
+		d.refresh(ctx)
+	case "r":
+		d.confirmRelease()
+	case "x":
+		d.confirmReset()
+	case "s":
+		d.shell()
+	}
+	return false
+}
+
+// selectedWorker returns the highlighted worker, or nil
+func (d *Dashboard) selectedWorker() *status.WorkerStatus {
+	if d.status == nil || d.selected >= len(d.status.Workers) {
+		return nil
+	}
+	return &d.status.Workers[d.selected]
+}
+
+// clampSelection keeps the selection on an existing worker
+func (d *Dashboard) clampSelection() {
+	if d.status == nil || len(d.status.Workers) == 0 {
+		d.selected = 0
+		return
+	}
+	if d.selected >= len(d.status.Workers) {
+		d.selected = len(d.status.Workers) - 1
+	}
+}
+
+func (d *Dashboard) confirmRelease() {
+	w := d.selectedWorker()
+	if w == nil {
+		return
+	}
+	if w.TaskID == "" {
+		d.message = w.Name + " has no task"
+		return
+	}
+	name, taskID := w.Name, w.TaskID
+	d.pending = &action{
+		prompt: fmt.Sprintf("Release %s from %s? [y/N]", taskID, name),
+		run: func(ctx context.Context) (string, error) {
+			if err := d.actions.Release(ctx, name); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Released %s from %s", taskID, name), nil
+		},
+	}
+}
+
+func (d *Dashboard) confirmReset() {
+	w := d.selectedWorker()
+	if w == nil {
+		return
+	}
+	name := w.Name
+	prompt := fmt.Sprintf("Reset %s to its clean snapshot? [y/N]", name)
+	if w.TaskID != "" {
+		prompt = fmt.Sprintf("Reset %s to its clean snapshot, releasing %s? Uncommitted work is lost [y/N]", name, w.TaskID)
+	}
+	d.pending = &action{
+		prompt: prompt,
+		run: func(ctx context.Context) (string, error) {
+			if err := d.actions.Reset(ctx, name); err != nil {
+				return "", err
+			}
+			return "Reset " + name, nil
+		},
+	}
+}
+
+// shell hands the terminal to a shell in the selected worker
+func (d *Dashboard) shell() {
+	w := d.selectedWorker()
+	if w == nil {
+		return
+	}
+
+	if d.term != nil {
+		d.readMu.Lock()
+		defer d.readMu.Unlock()
+		fmt.Fprint(d.out, leaveScreen)
+		d.term.Restore()
+		defer func() {
+			d.term.Raw()
+			fmt.Fprint(d.out, enterScreen)
+		}()
+	}
+
+	if err := d.actions.Shell(w.Name); err != nil {
+		d.message = "Error: " + err.Error()
+	}
+}
+
+// draw repaints the screen in place
+func (d *Dashboard) draw() {
+	width := 0
+	if d.term != nil {
+		width = d.term.Width()
+	}
+
+	var lines []string
+	if d.status != nil {
+		lines = Render(d.status, d.selected, width)
+	} else {
+		lines = []string{"Collecting status..."}
+	}
+	lines = append(lines, "")
+	if d.err != nil {
+		lines = append(lines, fit("Refresh failed: "+d.err.Error(), width))
+	}
+
+	switch {
+	case d.pending != nil:
+		lines = append(lines, fit(d.pending.prompt, width))
+	case d.message != "":
+		lines = append(lines, fit(d.message, width))
+	case d.term != nil:
+		lines = append(lines, fit(helpLine, width))
+	}
+
+	var b strings.Builder
+	b.WriteString(cursorHome)
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString(clearLine + "\r\n")
+	}
+	b.WriteString(clearBelow)
+	fmt.Fprint(d.out, b.String())
+}
@@ -0,0 +1,297 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"isollm/internal/status"
+)
+
+type fakeCollector struct {
+	status *status.Status
+	wait   chan struct{} // Blocks Collect until closed, when set
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (f *fakeCollector) Collect(ctx context.Context) (*status.Status, error) {
+	f.mu.Lock()
+	f.calls++
+	f.mu.Unlock()
+	if f.wait != nil {
+		<-f.wait
+	}
+	return f.status, nil
+}
+
+func (f *fakeCollector) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+type fakeActions struct {
+	calls []string
+	err   error
+}
+
+func (f *fakeActions) Release(ctx context.Context, worker string) error {
+	f.calls = append(f.calls, "release "+worker)
+	return f.err
+}
+
+func (f *fakeActions) Reset(ctx context.Context, worker string) error {
+	f.calls = append(f.calls, "reset "+worker)
+	return f.err
+}
+
+func (f *fakeActions) Shell(worker string) error {
+	f.calls = append(f.calls, "shell "+worker)
+	return f.err
+}
+
+func testStatus() *status.Status {
+	return &status.Status{
+		Project:   "demo",
+		Timestamp: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+		Workers: []status.WorkerStatus{
+			{Name: "worker-1", Status: status.WorkerStatusRunning, TaskID: "ar-1", TaskTitle: "Add login", Duration: 12 * time.Minute},
+			{Name: "worker-2", Status: status.WorkerStatusRunning},
+			{Name: "worker-3", Status: status.WorkerStatusError, Error: "apt-get failed"},
+		},
+		Tasks: status.TaskSummary{Ready: 3, InProgress: 1, Blocked: 2, Completed: 4},
+		Sync: status.SyncStatus{
+			TotalBranches: 2,
+			TaskBranches: []status.BranchInfo{
+				{Name: "isollm/ar-1", TaskID: "ar-1", Subject: "Add login form"},
+				{Name: "isollm/ar-2", TaskID: "ar-2", Subject: "Fix tests"},
+			},
+			Overlaps: []status.BranchOverlap{
+				{BranchA: "isollm/ar-1", BranchB: "isollm/ar-2", Paths: []string{"auth.go"}, Conflict: true},
+			},
+		},
+		Services: status.ServiceStatus{
+			Airyra: status.ServiceInfo{Running: true},
+			Zellij: status.ServiceInfo{Error: "session not found"},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	out := strings.Join(Render(testStatus(), 1, 0), "\n")
+
+	for _, want := range []string{
+		"isollm: demo  15:04:05",
+		"airyra ●  zellij ○ (session not found)",
+		"3 ready, 1 in-progress, 2 blocked, 4 completed",
+		"ar-1: Add login",
+		"12m",
+		"provisioning failed",
+		"isollm/ar-2",
+		"Fix tests",
+		"⚠ isollm/ar-1 ↔ isollm/ar-2 will conflict: auth.go",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() missing %q:\n%s", want, out)
+		}
+	}
+
+	for _, line := range Render(testStatus(), 1, 0) {
+		if strings.Contains(line, "worker-2") && !strings.HasPrefix(line, reverse+"›") {
+			t.Errorf("selected row not highlighted: %q", line)
+		}
+		if strings.Contains(line, "worker-1") && strings.Contains(line, reverse) {
+			t.Errorf("unselected row highlighted: %q", line)
+		}
+	}
+}
+
+func TestRender_Width(t *testing.T) {
+	for _, line := range Render(testStatus(), -1, 20) {
+		if n := len([]rune(line)); n > 20 {
+			t.Errorf("line %q is %d wide, want at most 20", line, n)
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := ParseKeys([]byte("j\x1b[A\x1b[Bq\x1b[C\x03"))
+	want := []string{"j", KeyUp, KeyDown, "q", KeyCtrlC}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ParseKeys() = %v, want %v", got, want)
+	}
+}
+
+func testDashboard() (*Dashboard, *fakeCollector, *fakeActions) {
+	collector := &fakeCollector{status: testStatus()}
+	actions := &fakeActions{}
+	d := New(collector, actions, nil, strings.NewReader(""), &bytes.Buffer{})
+	d.refresh(context.Background())
+	collect(d)
+	return d, collector, actions
+}
+
+// collect applies the status being collected in the background
+func collect(d *Dashboard) {
+	d.apply(context.Background(), <-d.results)
+}
+
+func TestHandleKey_Selection(t *testing.T) {
+	d, _, _ := testDashboard()
+	ctx := context.Background()
+
+	for _, key := range []string{"j", KeyDown, "j", "j"} {
+		d.HandleKey(ctx, key)
+	}
+	if d.selected != 2 {
+		t.Errorf("selected = %d after moving past the end, want 2", d.selected)
+	}
+	d.HandleKey(ctx, KeyUp)
+	d.HandleKey(ctx, "k")
+	d.HandleKey(ctx, "k")
+	if d.selected != 0 {
+		t.Errorf("selected = %d after moving past the top, want 0", d.selected)
+	}
+}
+
+func TestHandleKey_ReleaseConfirmed(t *testing.T) {
+	d, collector, actions := testDashboard()
+	ctx := context.Background()
+
+	d.HandleKey(ctx, "r")
+	if d.pending == nil || !strings.Contains(d.pending.prompt, "Release ar-1 from worker-1") {
+		t.Fatalf("pending = %+v, want release prompt", d.pending)
+	}
+	if len(actions.calls) != 0 {
+		t.Fatal("released before confirmation")
+	}
+
+	before := collector.Calls()
+	d.HandleKey(ctx, "y")
+	if strings.Join(actions.calls, ",") != "release worker-1" {
+		t.Errorf("actions = %v, want release worker-1", actions.calls)
+	}
+	collect(d)
+	if collector.Calls() != before+1 {
+		t.Error("status not refreshed after the action")
+	}
+	if d.message != "Released ar-1 from worker-1" {
+		t.Errorf("message = %q", d.message)
+	}
+}
+
+func TestHandleKey_Cancel(t *testing.T) {
+	d, _, actions := testDashboard()
+	ctx := context.Background()
+
+	d.HandleKey(ctx, "x")
+	d.HandleKey(ctx, "n")
+	if len(actions.calls) != 0 {
+		t.Errorf("actions = %v after cancelling", actions.calls)
+	}
+	if d.message != "Cancelled" {
+		t.Errorf("message = %q, want Cancelled", d.message)
+	}
+}
+
+func TestHandleKey_ReleaseIdleWorker(t *testing.T) {
+	d, _, _ := testDashboard()
+	ctx := context.Background()
+
+	d.HandleKey(ctx, "j")
+	d.HandleKey(ctx, "r")
+	if d.pending != nil {
+		t.Error("asked to release a worker with no task")
+	}
+	if d.message != "worker-2 has no task" {
+		t.Errorf("message = %q", d.message)
+	}
+}
+
+func TestHandleKey_ActionError(t *testing.T) {
+	d, _, actions := testDashboard()
+	actions.err = errors.New("airyra client not initialized")
+	ctx := context.Background()
+
+	d.HandleKey(ctx, "j")
+	d.HandleKey(ctx, "x")
+	d.HandleKey(ctx, "y")
+	if actions.calls[0] != "reset worker-2" {
+		t.Errorf("actions = %v, want reset worker-2", actions.calls)
+	}
+	if !strings.Contains(d.message, "airyra client not initialized") {
+		t.Errorf("message = %q, want the error", d.message)
+	}
+}
+
+func TestHandleKey_ShellAndQuit(t *testing.T) {
+	d, _, actions := testDashboard()
+	ctx := context.Background()
+
+	if d.HandleKey(ctx, "s") {
+		t.Error("s quit the dashboard")
+	}
+	if strings.Join(actions.calls, ",") != "shell worker-1" {
+		t.Errorf("actions = %v, want shell worker-1", actions.calls)
+	}
+	if !d.HandleKey(ctx, "q") {
+		t.Error("q did not quit")
+	}
+}
+
+func TestRefresh_SlowCollectDoesNotBlock(t *testing.T) {
+	d, collector, _ := testDashboard()
+	ctx := context.Background()
+	collector.wait = make(chan struct{})
+
+	d.HandleKey(ctx, " ")
+	d.HandleKey(ctx, "g")
+	d.HandleKey(ctx, "j")
+	if d.selected != 1 {
+		t.Errorf("selected = %d while collecting, want keys handled", d.selected)
+	}
+	if d.status == nil {
+		t.Error("last status dropped while collecting")
+	}
+
+	close(collector.wait)
+	collect(d)
+	collect(d) // The refresh asked for while the first was running
+	if calls := collector.Calls(); calls != 3 {
+		t.Errorf("collected %d times, want one at a time and one more after", calls)
+	}
+	select {
+	case <-d.results:
+		t.Error("collected again without being asked")
+	default:
+	}
+}
+
+func TestRun_StopsWithContext(t *testing.T) {
+	collector := &fakeCollector{status: testStatus()}
+	var out bytes.Buffer
+	d := New(collector, &fakeActions{}, nil, strings.NewReader(""), &out)
+	d.SetInterval(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := d.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if calls := collector.Calls(); calls < 2 {
+		t.Errorf("collected %d times, want periodic refreshes", calls)
+	}
+	if !strings.HasPrefix(out.String(), enterScreen) || !strings.HasSuffix(out.String(), leaveScreen) {
+		t.Error("screen not entered and restored")
+	}
+	if !strings.Contains(out.String(), "worker-3") {
+		t.Error("status not drawn")
+	}
+}
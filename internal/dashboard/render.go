@@ -0,0 +1,140 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"isollm/internal/status"
+)
+
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l" // Alternate screen, hide cursor
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+	cursorHome  = "\x1b[H"
+	clearLine   = "\x1b[K"
+	clearBelow  = "\x1b[J"
+	reverse     = "\x1b[7m"
+	reset       = "\x1b[0m"
+
+	helpLine = "↑/↓ select  r release  x reset  s shell  space refresh  q quit"
+
+	// maxBranches is how many task branches are listed
+	maxBranches = 5
+)
+
+// Render lays out a status as screen lines, highlighting the selected
+// worker. Lines are cut to width; width 0 means no limit.
+func Render(s *status.Status, selected, width int) []string {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fit(fmt.Sprintf(format, args...), width))
+	}
+
+	add("isollm: %s  %s", s.Project, s.Timestamp.Format("15:04:05"))
+	add("%s", strings.Repeat("─", 49))
+	add("Services: airyra %s  zellij %s", service(s.Services.Airyra), service(s.Services.Zellij))
+	add("Tasks: %d ready, %d in-progress, %d blocked, %d completed",
+		s.Tasks.Ready, s.Tasks.InProgress, s.Tasks.Blocked, s.Tasks.Completed)
+	add("")
+
+	add("Workers:")
+	if len(s.Workers) == 0 {
+		add("  No workers")
+	}
+	for i, w := range s.Workers {
+		task := "-"
+		switch {
+		case w.Error != "":
+			task = "provisioning failed"
+		case w.TaskTitle != "":
+			task = w.TaskID + ": " + w.TaskTitle
+		case w.TaskID != "":
+			task = w.TaskID
+		}
+		duration := "-"
+		if w.Duration > 0 {
+			duration = shortDuration(w.Duration)
+		}
+
+		marker := " "
+		if i == selected {
+			marker = "›"
+		}
+		line := fit(fmt.Sprintf("%s %-10s %s %-8s %6s  %s",
+			marker, w.Name, symbol(w.Status), w.Status, duration, task), width)
+		if i == selected {
+			line = reverse + line + reset
+		}
+		lines = append(lines, line)
+	}
+	add("")
+
+	sync := fmt.Sprintf("Branches: %d task branches", s.Sync.TotalBranches)
+	if s.Sync.HostAhead > 0 {
+		sync += fmt.Sprintf(", host %d commits ahead of bare repo", s.Sync.HostAhead)
+	}
+	add("%s", sync)
+	for i, b := range s.Sync.TaskBranches {
+		if i == maxBranches {
+			add("  ... %d more", len(s.Sync.TaskBranches)-maxBranches)
+			break
+		}
+		add("  %-24s %s", b.Name, b.Subject)
+	}
+	for _, o := range s.Sync.Conflicts() {
+		add("  ⚠ %s ↔ %s will conflict: %s", o.BranchA, o.BranchB, strings.Join(o.Paths, ", "))
+	}
+
+	return lines
+}
+
+// service shows a service's health, with the reason when it is down
+func service(info status.ServiceInfo) string {
+	if info.Running {
+		return "●"
+	}
+	if info.Error != "" {
+		return "○ (" + info.Error + ")"
+	}
+	return "○"
+}
+
+// symbol returns the marker for a worker status
+func symbol(s string) string {
+	switch s {
+	case status.WorkerStatusRunning:
+		return "●"
+	case status.WorkerStatusStopped:
+		return "○"
+	case status.WorkerStatusError:
+		return "✗"
+	default:
+		return "?"
+	}
+}
+
+// shortDuration formats a duration as 45s, 12m or 3h5m
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// fit cuts a line to width characters
+func fit(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	if width == 1 {
+		return string(r[:1])
+	}
+	return string(r[:width-1]) + "…"
+}
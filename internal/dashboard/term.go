@@ -0,0 +1,118 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Keys that are not a single printable character
+const (
+	KeyUp    = "up"
+	KeyDown  = "down"
+	KeyCtrlC = "ctrl-c"
+)
+
+// ParseKeys splits raw terminal input into key names. Arrow keys become
+// KeyUp and KeyDown; other escape sequences are dropped.
+func ParseKeys(input []byte) []string {
+	var keys []string
+	for i := 0; i < len(input); i++ {
+		switch {
+		case input[i] == 0x03:
+			keys = append(keys, KeyCtrlC)
+		case input[i] == 0x1b && i+2 < len(input) && input[i+1] == '[':
+			switch input[i+2] {
+			case 'A':
+				keys = append(keys, KeyUp)
+			case 'B':
+				keys = append(keys, KeyDown)
+			}
+			i += 2
+		case input[i] == 0x1b:
+			// Lone escape or an unknown sequence
+		default:
+			keys = append(keys, string(input[i]))
+		}
+	}
+	return keys
+}
+
+// readKeys sends key presses until the context is cancelled. The terminal
+// is in "min 0 time 1" mode, so each read gives up after 100ms and the
+// input can be handed to a shell by holding readMu.
+func (d *Dashboard) readKeys(ctx context.Context, keys chan<- string) {
+	buf := make([]byte, 64)
+	for ctx.Err() == nil {
+		d.readMu.Lock()
+		n, err := d.in.Read(buf)
+		d.readMu.Unlock()
+
+		if n == 0 {
+			if err != nil && err != io.EOF {
+				return
+			}
+			continue
+		}
+		for _, key := range ParseKeys(buf[:n]) {
+			select {
+			case keys <- key:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// sttyTerminal controls the terminal on stdin with stty(1)
+type sttyTerminal struct {
+	saved string
+}
+
+// OpenTerminal returns a Terminal for stdin, or an error if stdin is not
+// a terminal
+func OpenTerminal() (Terminal, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal: %w", err)
+	}
+	return &sttyTerminal{saved: saved}, nil
+}
+
+// Raw reads keys as they are pressed, without echo
+func (t *sttyTerminal) Raw() error {
+	_, err := stty("-icanon", "-echo", "min", "0", "time", "1")
+	return err
+}
+
+// Restore puts the terminal back the way it was found
+func (t *sttyTerminal) Restore() error {
+	_, err := stty(t.saved)
+	return err
+}
+
+// Width returns the terminal's width in columns, or 0 if it is unknown
+func (t *sttyTerminal) Width() int {
+	size, err := stty("size")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(size)
+	if len(fields) != 2 {
+		return 0
+	}
+	cols, _ := strconv.Atoi(fields[1])
+	return cols
+}
+
+// stty runs stty against stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
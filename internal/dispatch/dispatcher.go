@@ -0,0 +1,389 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"isollm/internal/airyra"
+	"isollm/internal/claude"
+	"isollm/internal/plans"
+	"isollm/internal/verify"
+	"isollm/internal/watchdog"
+	"isollm/internal/worker"
+)
+
+const (
+	// DefaultInterval is the default time between dispatch passes
+	DefaultInterval = 10 * time.Second
+)
+
+// WorkerManager is the subset of worker.Manager used by the dispatcher
+type WorkerManager interface {
+	List() ([]worker.WorkerInfo, error)
+	ClaimTask(ctx context.Context, workerName, taskID string) (*airyra.Task, error)
+	ReleaseWorkerTask(ctx context.Context, workerName string) error
+	CompleteWorkerTask(ctx context.Context, workerName string) error
+	DoneRequested(workerName string) bool
+	ClearDoneRequest(workerName string) error
+	BlockWorkerTask(ctx context.Context, workerName, reason string) error
+	BlockRequest(workerName string) (string, bool)
+	ClearBlockRequest(workerName string) error
+	Plan(workerName string) ([]byte, bool)
+	ClearPlan(workerName string) error
+	SplitWorkerTask(ctx context.Context, workerName string, plan []byte) ([]string, error)
+	CompletePlans(ctx context.Context) ([]string, error)
+	PlanState(taskID string) plans.State
+	TaskVerifyCommands(taskID string) []string
+	CreateTaskBranch(workerName, branch string) error
+	TaskBranch(taskID string) string
+	ClearTask(name string) error
+	PaneBusy(workerName string) bool
+	StopPane(workerName string) error
+}
+
+// TaskPreparer writes task context into a worker (claude.Launcher)
+type TaskPreparer interface {
+	PrepareTask(workerName string, task *claude.TaskAssignment) error
+	GetTaskLaunchCommand(task *claude.TaskAssignment) []string
+}
+
+// Reclaimer takes tasks back from dead or silent workers (watchdog.Watchdog)
+type Reclaimer interface {
+	Check(ctx context.Context) ([]watchdog.Reclaim, error)
+}
+
+// LogRecorder readies the host log a task's pane output is recorded to
+// (logs.Store)
+type LogRecorder interface {
+	Prepare(workerName, taskID string) error
+}
+
+// LaunchFunc starts a command for a worker (typically in its zellij pane)
+type LaunchFunc func(workerName string, cmd []string) error
+
+// Assignment records a task handed to a worker during a dispatch pass
+type Assignment struct {
+	Worker string
+	TaskID string
+	Title  string
+	Branch string
+}
+
+// Dispatcher feeds ready airyra tasks to idle workers
+type Dispatcher struct {
+	mgr       WorkerManager
+	airyra    airyra.TaskClient
+	preparer  TaskPreparer
+	launch    LaunchFunc
+	reclaimer Reclaimer
+	logs      LogRecorder
+	interval  time.Duration
+	out       io.Writer
+}
+
+// New creates a Dispatcher. launch may be nil, in which case tasks are
+// claimed and prepared but Claude is not started.
+func New(mgr WorkerManager, client airyra.TaskClient, preparer TaskPreparer, launch LaunchFunc, out io.Writer) *Dispatcher {
+	return &Dispatcher{
+		mgr:      mgr,
+		airyra:   client,
+		preparer: preparer,
+		launch:   launch,
+		interval: DefaultInterval,
+		out:      out,
+	}
+}
+
+// SetInterval sets the time between dispatch passes
+func (d *Dispatcher) SetInterval(interval time.Duration) {
+	if interval > 0 {
+		d.interval = interval
+	}
+}
+
+// SetReclaimer makes every pass reclaim stale tasks before assigning new ones
+func (d *Dispatcher) SetReclaimer(r Reclaimer) {
+	d.reclaimer = r
+}
+
+// SetLogs records each launched Claude session to a per-task log
+func (d *Dispatcher) SetLogs(l LogRecorder) {
+	d.logs = l
+}
+
+// Run dispatches tasks until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Tick(ctx); err != nil {
+			fmt.Fprintf(d.out, "dispatch: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick performs a single dispatch pass: it reclaims stale tasks, blocks,
+// splits or completes tasks as workers asked, frees workers whose task has
+// finished, completes planned tasks whose subtasks are done, then hands ready
+// tasks to idle running workers.
+func (d *Dispatcher) Tick(ctx context.Context) ([]Assignment, error) {
+	if d.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+
+	if d.reclaimer != nil {
+		if _, err := d.reclaimer.Check(ctx); err != nil {
+			fmt.Fprintf(d.out, "dispatch: %v\n", err)
+		}
+	}
+
+	workers, err := d.mgr.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	idle := d.reconcile(ctx, workers)
+
+	completed, err := d.mgr.CompletePlans(ctx)
+	if err != nil {
+		fmt.Fprintf(d.out, "dispatch: %v\n", err)
+	}
+	for _, id := range completed {
+		fmt.Fprintf(d.out, "Completed %s: all subtasks done\n", id)
+	}
+
+	if len(idle) == 0 {
+		return nil, nil
+	}
+
+	ready, err := d.airyra.ListReadyTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ready tasks: %w", err)
+	}
+
+	var assignments []Assignment
+	tasks := ready.Tasks
+	for _, name := range idle {
+		for len(tasks) > 0 {
+			task := tasks[0]
+			tasks = tasks[1:]
+			if d.mgr.PlanState(task.ID) == plans.StateSplit {
+				continue // Waiting on its subtasks, not for a worker
+			}
+
+			a, err := d.assign(ctx, name, task)
+			if err != nil {
+				if airyra.IsAlreadyClaimed(err) {
+					continue // Claimed elsewhere, try the next task
+				}
+				fmt.Fprintf(d.out, "dispatch: %s: %v\n", name, err)
+				break
+			}
+
+			fmt.Fprintf(d.out, "Assigned %s to %s (%s)\n", a.TaskID, a.Worker, a.Branch)
+			assignments = append(assignments, *a)
+			break
+		}
+	}
+
+	return assignments, nil
+}
+
+// reconcile stops Claude and clears local task state for workers whose task
+// is no longer in progress and returns the names of running workers without
+// a task whose pane is free
+func (d *Dispatcher) reconcile(ctx context.Context, workers []worker.WorkerInfo) []string {
+	var idle []string
+
+	for _, w := range workers {
+		if w.Status != "RUNNING" || w.Error != "" {
+			continue
+		}
+
+		if w.TaskID != "" {
+			task, err := d.airyra.GetTask(ctx, w.TaskID)
+			switch {
+			case err != nil && !airyra.IsTaskNotFound(err):
+				continue // Can't tell, leave the worker alone
+			case err == nil && task.Status == airyra.StatusInProgress && d.askedToBlock(ctx, w.Name, task.ID):
+				continue // Waiting for an answer
+			case err == nil && task.Status == airyra.StatusInProgress && d.split(ctx, w.Name, task.ID):
+				// Handed back to wait for its subtasks, the worker is free
+			case err == nil && task.Status == airyra.StatusInProgress && d.mgr.DoneRequested(w.Name):
+				if !d.complete(ctx, w.Name, task.ID) {
+					continue // Blocked by verification or still in progress
+				}
+			case err == nil && (task.Status == airyra.StatusInProgress || task.Status == airyra.StatusBlocked):
+				continue // Still working
+			}
+
+			// Claude may still be in the pane; it has to go before the
+			// next task is typed there
+			if err := d.mgr.StopPane(w.Name); err != nil {
+				fmt.Fprintf(d.out, "dispatch: %v\n", err)
+				continue
+			}
+			if err := d.mgr.ClearTask(w.Name); err != nil {
+				fmt.Fprintf(d.out, "dispatch: failed to clear task state for %s: %v\n", w.Name, err)
+				continue
+			}
+		} else if d.launch != nil && d.mgr.PaneBusy(w.Name) {
+			continue // Running something the dispatcher did not start
+		}
+
+		idle = append(idle, w.Name)
+	}
+
+	return idle
+}
+
+// askedToBlock blocks a task whose worker reported a blocker, recording
+// its reason. It returns true if the task was blocked.
+func (d *Dispatcher) askedToBlock(ctx context.Context, workerName, taskID string) bool {
+	reason, ok := d.mgr.BlockRequest(workerName)
+	if !ok {
+		return false
+	}
+	// The request is kept until the task is blocked, so the question is
+	// tried again on the next pass instead of being lost
+	if err := d.mgr.BlockWorkerTask(ctx, workerName, reason); err != nil {
+		fmt.Fprintf(d.out, "dispatch: failed to block %s on %s: %v\n", taskID, workerName, err)
+		return false
+	}
+	if err := d.mgr.ClearBlockRequest(workerName); err != nil {
+		fmt.Fprintf(d.out, "dispatch: %v\n", err)
+	}
+	// The conversation is resumed with the answer, so the pane is free
+	// for it meanwhile
+	if err := d.mgr.StopPane(workerName); err != nil {
+		fmt.Fprintf(d.out, "dispatch: %v\n", err)
+	}
+	fmt.Fprintf(d.out, "Blocked %s on %s: %s\n", taskID, workerName, reason)
+	return true
+}
+
+// split imports the subtasks a planner worker wrote for its task. It
+// returns true if the task was split. The plan is kept when splitting
+// fails before the task was split or blocked, so it is tried again on the
+// next pass.
+func (d *Dispatcher) split(ctx context.Context, workerName, taskID string) bool {
+	plan, ok := d.mgr.Plan(workerName)
+	if !ok {
+		return false
+	}
+	children, err := d.mgr.SplitWorkerTask(ctx, workerName, plan)
+	switch {
+	case err == nil:
+		fmt.Fprintf(d.out, "Split %s on %s into %d subtasks\n", taskID, workerName, len(children))
+	case errors.Is(err, worker.ErrPlanRejected):
+		fmt.Fprintf(d.out, "Blocked %s on %s: %v\n", taskID, workerName, err)
+	case len(children) > 0:
+		// Split and handed back, only recording the plan failed
+		fmt.Fprintf(d.out, "dispatch: split %s on %s: %v\n", taskID, workerName, err)
+	default:
+		fmt.Fprintf(d.out, "dispatch: failed to split %s on %s: %v\n", taskID, workerName, err)
+		return false
+	}
+
+	if err := d.mgr.ClearPlan(workerName); err != nil {
+		fmt.Fprintf(d.out, "dispatch: %v\n", err)
+	}
+	return len(children) > 0
+}
+
+// complete verifies and completes a task its worker reported as done.
+// It returns true if the worker is free for a new task. The request is
+// kept when completing fails for another reason than verification, so it
+// is tried again on the next pass.
+func (d *Dispatcher) complete(ctx context.Context, workerName, taskID string) bool {
+	err := d.mgr.CompleteWorkerTask(ctx, workerName)
+	var failed *verify.FailedError
+	switch {
+	case err == nil:
+		fmt.Fprintf(d.out, "Completed %s on %s\n", taskID, workerName)
+	case errors.As(err, &failed):
+		fmt.Fprintf(d.out, "Blocked %s on %s: %v\n", taskID, workerName, err)
+	default:
+		fmt.Fprintf(d.out, "dispatch: failed to complete %s on %s: %v\n", taskID, workerName, err)
+		return false
+	}
+
+	if err := d.mgr.ClearDoneRequest(workerName); err != nil {
+		fmt.Fprintf(d.out, "dispatch: %v\n", err)
+	}
+	return err == nil
+}
+
+// assign claims a task for a worker, checks out its branch, writes the
+// task context and launches Claude
+func (d *Dispatcher) assign(ctx context.Context, workerName string, task *airyra.Task) (*Assignment, error) {
+	claimed, err := d.mgr.ClaimTask(ctx, workerName, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &claude.TaskAssignment{
+		ID:          claimed.ID,
+		Title:       claimed.Title,
+		Description: claimed.Description,
+		Branch:      d.mgr.TaskBranch(claimed.ID),
+		Plan:        d.mgr.PlanState(claimed.ID) == plans.StatePending,
+		Verify:      d.mgr.TaskVerifyCommands(claimed.ID),
+	}
+
+	if err := d.prepare(workerName, a); err != nil {
+		d.release(ctx, workerName, a.ID)
+		return nil, err
+	}
+
+	if d.launch != nil {
+		cmd := d.preparer.GetTaskLaunchCommand(a)
+		if d.logs != nil {
+			if err := d.logs.Prepare(workerName, a.ID); err != nil {
+				fmt.Fprintf(d.out, "dispatch: %v (launching without a log)\n", err)
+			} else {
+				cmd = claude.WithLog(cmd, a.ID)
+			}
+		}
+		if err := d.launch(workerName, cmd); err != nil {
+			d.release(ctx, workerName, a.ID)
+			return nil, fmt.Errorf("failed to launch Claude for %s: %w", a.ID, err)
+		}
+	}
+
+	return &Assignment{
+		Worker: workerName,
+		TaskID: a.ID,
+		Title:  a.Title,
+		Branch: a.Branch,
+	}, nil
+}
+
+// release hands a task that could not be started back to the queue and
+// clears the worker's task state, so another worker can pick it up
+func (d *Dispatcher) release(ctx context.Context, workerName, taskID string) {
+	if err := d.mgr.ReleaseWorkerTask(ctx, workerName); err != nil {
+		fmt.Fprintf(d.out, "dispatch: failed to release %s: %v\n", taskID, err)
+	}
+}
+
+// prepare creates the task branch and writes CLAUDE.md
+func (d *Dispatcher) prepare(workerName string, a *claude.TaskAssignment) error {
+	if err := d.mgr.CreateTaskBranch(workerName, a.Branch); err != nil {
+		return err
+	}
+	if err := d.preparer.PrepareTask(workerName, a); err != nil {
+		return fmt.Errorf("failed to prepare task %s: %w", a.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,808 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"isollm/internal/airyra"
+	"isollm/internal/claude"
+	"isollm/internal/plans"
+	"isollm/internal/verify"
+	"isollm/internal/watchdog"
+	"isollm/internal/worker"
+)
+
+// mockManager implements WorkerManager on top of an airyra mock
+type mockManager struct {
+	airyra      *airyra.MockClient
+	workers     []worker.WorkerInfo
+	branches    map[string]string // worker -> checked out branch
+	branchErr   error
+	cleared     []string
+	released    []string
+	claimCounts map[string]int
+	doneReqs    map[string]bool   // worker -> Claude asked to complete
+	blockReqs   map[string]string // worker -> reason Claude gave for a blocker
+	planReqs    map[string][]byte // worker -> subtasks a planner wrote
+	planStates  map[string]plans.State
+	planDone    []string // Split tasks CompletePlans reports done
+	splitErr    error
+	checks      map[string][]string
+	completeErr error
+	blockErr    error
+	busy        map[string]bool // worker -> pane running a command
+	stopped     []string
+}
+
+func newMockManager(client *airyra.MockClient, workers ...worker.WorkerInfo) *mockManager {
+	return &mockManager{
+		airyra:      client,
+		workers:     workers,
+		branches:    make(map[string]string),
+		busy:        make(map[string]bool),
+		claimCounts: make(map[string]int),
+		doneReqs:    make(map[string]bool),
+		blockReqs:   make(map[string]string),
+		planReqs:    make(map[string][]byte),
+		planStates:  make(map[string]plans.State),
+	}
+}
+
+func (m *mockManager) List() ([]worker.WorkerInfo, error) {
+	return m.workers, nil
+}
+
+func (m *mockManager) ClaimTask(ctx context.Context, workerName, taskID string) (*airyra.Task, error) {
+	m.claimCounts[workerName]++
+	task, err := m.airyra.ClaimTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range m.workers {
+		if m.workers[i].Name == workerName {
+			m.workers[i].TaskID = task.ID
+			m.workers[i].Branch = m.TaskBranch(task.ID)
+		}
+	}
+	return task, nil
+}
+
+func (m *mockManager) ReleaseWorkerTask(ctx context.Context, workerName string) error {
+	m.released = append(m.released, workerName)
+	for i := range m.workers {
+		if m.workers[i].Name == workerName && m.workers[i].TaskID != "" {
+			if _, err := m.airyra.ReleaseTask(ctx, m.workers[i].TaskID, false); err != nil {
+				return err
+			}
+			m.workers[i].TaskID = ""
+		}
+	}
+	return nil
+}
+
+func (m *mockManager) CompleteWorkerTask(ctx context.Context, workerName string) error {
+	for i := range m.workers {
+		if m.workers[i].Name != workerName {
+			continue
+		}
+		if m.completeErr != nil {
+			return m.completeErr
+		}
+		if _, err := m.airyra.CompleteTask(ctx, m.workers[i].TaskID); err != nil {
+			return err
+		}
+		m.workers[i].TaskID = ""
+	}
+	return nil
+}
+
+func (m *mockManager) DoneRequested(workerName string) bool {
+	return m.doneReqs[workerName]
+}
+
+func (m *mockManager) ClearDoneRequest(workerName string) error {
+	delete(m.doneReqs, workerName)
+	return nil
+}
+
+func (m *mockManager) BlockRequest(workerName string) (string, bool) {
+	reason, ok := m.blockReqs[workerName]
+	return reason, ok
+}
+
+func (m *mockManager) ClearBlockRequest(workerName string) error {
+	delete(m.blockReqs, workerName)
+	return nil
+}
+
+func (m *mockManager) BlockWorkerTask(ctx context.Context, workerName, reason string) error {
+	if m.blockErr != nil {
+		return m.blockErr
+	}
+	for _, w := range m.workers {
+		if w.Name == workerName {
+			_, err := m.airyra.BlockTask(ctx, w.TaskID)
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockManager) Plan(workerName string) ([]byte, bool) {
+	plan, ok := m.planReqs[workerName]
+	return plan, ok
+}
+
+func (m *mockManager) ClearPlan(workerName string) error {
+	delete(m.planReqs, workerName)
+	return nil
+}
+
+func (m *mockManager) SplitWorkerTask(ctx context.Context, workerName string, plan []byte) ([]string, error) {
+	if m.splitErr != nil {
+		return nil, m.splitErr
+	}
+	for _, w := range m.workers {
+		if w.Name != workerName {
+			continue
+		}
+		child, err := m.airyra.AddTask(ctx, string(plan))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := m.airyra.ReleaseTask(ctx, w.TaskID, false); err != nil {
+			return nil, err
+		}
+		m.planStates[w.TaskID] = plans.StateSplit
+		return []string{child.ID}, nil
+	}
+	return nil, errors.New("unknown worker")
+}
+
+func (m *mockManager) CompletePlans(ctx context.Context) ([]string, error) {
+	done := m.planDone
+	m.planDone = nil
+	return done, nil
+}
+
+func (m *mockManager) PlanState(taskID string) plans.State {
+	return m.planStates[taskID]
+}
+
+func (m *mockManager) TaskVerifyCommands(taskID string) []string {
+	return m.checks[taskID]
+}
+
+func (m *mockManager) CreateTaskBranch(workerName, branch string) error {
+	if m.branchErr != nil {
+		return m.branchErr
+	}
+	m.branches[workerName] = branch
+	return nil
+}
+
+func (m *mockManager) TaskBranch(taskID string) string {
+	return "isollm/" + taskID
+}
+
+func (m *mockManager) ClearTask(name string) error {
+	m.cleared = append(m.cleared, name)
+	for i := range m.workers {
+		if m.workers[i].Name == name {
+			m.workers[i].TaskID = ""
+			m.workers[i].Branch = ""
+		}
+	}
+	return nil
+}
+
+func (m *mockManager) PaneBusy(workerName string) bool {
+	return m.busy[workerName]
+}
+
+func (m *mockManager) StopPane(workerName string) error {
+	m.stopped = append(m.stopped, workerName)
+	delete(m.busy, workerName)
+	return nil
+}
+
+// mockPreparer implements TaskPreparer
+type mockPreparer struct {
+	prepared map[string]*claude.TaskAssignment
+}
+
+func newMockPreparer() *mockPreparer {
+	return &mockPreparer{prepared: make(map[string]*claude.TaskAssignment)}
+}
+
+func (p *mockPreparer) PrepareTask(workerName string, task *claude.TaskAssignment) error {
+	p.prepared[workerName] = task
+	return nil
+}
+
+func (p *mockPreparer) GetTaskLaunchCommand(task *claude.TaskAssignment) []string {
+	return []string{"claude", claude.TaskPrompt(task)}
+}
+
+func running(name string) worker.WorkerInfo {
+	return worker.WorkerInfo{Name: name, Status: "RUNNING"}
+}
+
+func TestTick_AssignsReadyTasksToIdleWorkers(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	client.AddTask(ctx, "Task 1")
+	client.AddTask(ctx, "Task 2")
+	client.AddTask(ctx, "Task 3")
+
+	mgr := newMockManager(client, running("worker-1"), running("worker-2"))
+	prep := newMockPreparer()
+
+	launched := make(map[string][]string)
+	launch := func(name string, cmd []string) error {
+		launched[name] = cmd
+		return nil
+	}
+
+	d := New(mgr, client, prep, launch, &bytes.Buffer{})
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	if len(assignments) != 2 {
+		t.Fatalf("Tick() assigned %d tasks, want 2", len(assignments))
+	}
+
+	for _, a := range assignments {
+		if mgr.branches[a.Worker] != a.Branch {
+			t.Errorf("branch for %s = %q, want %q", a.Worker, mgr.branches[a.Worker], a.Branch)
+		}
+		if prep.prepared[a.Worker] == nil || prep.prepared[a.Worker].ID != a.TaskID {
+			t.Errorf("task for %s not prepared", a.Worker)
+		}
+		if len(launched[a.Worker]) == 0 {
+			t.Errorf("Claude not launched for %s", a.Worker)
+		}
+		task, _ := client.GetTask(ctx, a.TaskID)
+		if task.Status != airyra.StatusInProgress {
+			t.Errorf("task %s status = %v, want %v", a.TaskID, task.Status, airyra.StatusInProgress)
+		}
+	}
+
+	if assignments[0].TaskID == assignments[1].TaskID {
+		t.Error("both workers got the same task")
+	}
+}
+
+// mockLogs implements LogRecorder
+type mockLogs struct {
+	prepared map[string]string // worker -> task
+}
+
+func (l *mockLogs) Prepare(workerName, taskID string) error {
+	l.prepared[workerName] = taskID
+	return nil
+}
+
+func TestTick_RecordsLaunchToTaskLog(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task 1")
+
+	var launched []string
+	launch := func(name string, cmd []string) error {
+		launched = cmd
+		return nil
+	}
+
+	logs := &mockLogs{prepared: make(map[string]string)}
+	d := New(newMockManager(client, running("worker-1")), client, newMockPreparer(), launch, &bytes.Buffer{})
+	d.SetLogs(logs)
+
+	if _, err := d.Tick(ctx); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	if logs.prepared["worker-1"] != task.ID {
+		t.Errorf("log prepared for %q, want %s", logs.prepared["worker-1"], task.ID)
+	}
+	if len(launched) == 0 || launched[0] != "script" || launched[len(launched)-1] != claude.LogMountPath+"/"+task.ID+".log" {
+		t.Errorf("launch command = %v, want it recorded with script", launched)
+	}
+}
+
+func TestTick_SkipsBusyAndStoppedWorkers(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	busyTask, _ := client.AddTask(ctx, "Busy")
+	client.ClaimTask(ctx, busyTask.ID)
+	client.AddTask(ctx, "Ready")
+
+	busy := running("worker-1")
+	busy.TaskID = busyTask.ID
+	stopped := worker.WorkerInfo{Name: "worker-2", Status: "STOPPED"}
+
+	mgr := newMockManager(client, busy, stopped)
+	d := New(mgr, client, newMockPreparer(), nil, &bytes.Buffer{})
+
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if len(assignments) != 0 {
+		t.Errorf("Tick() assigned %d tasks, want 0", len(assignments))
+	}
+	if len(mgr.cleared) != 0 {
+		t.Errorf("Tick() cleared %v, want none", mgr.cleared)
+	}
+}
+
+func TestTick_FreesWorkersWithFinishedTasks(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	done, _ := client.AddTask(ctx, "Done")
+	client.ClaimTask(ctx, done.ID)
+	client.CompleteTask(ctx, done.ID)
+	next, _ := client.AddTask(ctx, "Next")
+
+	w := running("worker-1")
+	w.TaskID = done.ID
+	mgr := newMockManager(client, w)
+
+	d := New(mgr, client, newMockPreparer(), nil, &bytes.Buffer{})
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	if len(mgr.cleared) != 1 || mgr.cleared[0] != "worker-1" {
+		t.Errorf("cleared = %v, want [worker-1]", mgr.cleared)
+	}
+	if len(mgr.stopped) != 1 || mgr.stopped[0] != "worker-1" {
+		t.Errorf("stopped = %v, want Claude stopped in worker-1", mgr.stopped)
+	}
+	if len(assignments) != 1 || assignments[0].TaskID != next.ID {
+		t.Errorf("assignments = %+v, want %s", assignments, next.ID)
+	}
+}
+
+func TestTick_SkipsBusyPanes(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Ready")
+
+	mgr := newMockManager(client, running("worker-1"), running("worker-2"))
+	mgr.busy["worker-1"] = true
+
+	var launched []string
+	launch := func(name string, cmd []string) error {
+		launched = append(launched, name)
+		return nil
+	}
+
+	d := New(mgr, client, newMockPreparer(), launch, &bytes.Buffer{})
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	if len(assignments) != 1 || assignments[0].Worker != "worker-2" || assignments[0].TaskID != task.ID {
+		t.Errorf("assignments = %+v, want %s on worker-2", assignments, task.ID)
+	}
+	if len(launched) != 1 || launched[0] != "worker-2" {
+		t.Errorf("launched in %v, want only worker-2", launched)
+	}
+	if len(mgr.stopped) != 0 {
+		t.Errorf("stopped %v, want a busy pane without a task left alone", mgr.stopped)
+	}
+}
+
+func TestTick_AlreadyClaimedTriesNextTask(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	first, _ := client.AddTask(ctx, "First")
+	second, _ := client.AddTask(ctx, "Second")
+
+	// Another agent grabs the first task between list and claim
+	client.OnClaimTask = func(ctx context.Context, id string) (*airyra.Task, error) {
+		client.OnClaimTask = nil
+		if id == first.ID {
+			client.ClaimTask(ctx, id)
+			return client.ClaimTask(ctx, id)
+		}
+		return client.ClaimTask(ctx, id)
+	}
+
+	mgr := newMockManager(client, running("worker-1"))
+	d := New(mgr, client, newMockPreparer(), nil, &bytes.Buffer{})
+
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	ids := map[string]bool{first.ID: true, second.ID: true}
+	if len(assignments) != 1 || !ids[assignments[0].TaskID] {
+		t.Fatalf("assignments = %+v, want one of the two tasks", assignments)
+	}
+}
+
+func TestTick_BranchFailureReleasesTask(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+
+	mgr := newMockManager(client, running("worker-1"))
+	mgr.branchErr = errors.New("fetch failed")
+
+	out := &bytes.Buffer{}
+	d := New(mgr, client, newMockPreparer(), nil, out)
+
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if len(assignments) != 0 {
+		t.Errorf("Tick() assigned %d tasks, want 0", len(assignments))
+	}
+	if len(mgr.released) != 1 {
+		t.Errorf("released = %v, want [worker-1]", mgr.released)
+	}
+
+	got, _ := client.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusOpen {
+		t.Errorf("task status = %v, want %v", got.Status, airyra.StatusOpen)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("fetch failed")) {
+		t.Errorf("output %q does not mention the failure", out.String())
+	}
+}
+
+func TestTick_LaunchFailureReleasesTask(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+
+	mgr := newMockManager(client, running("worker-1"))
+	launch := func(name string, cmd []string) error {
+		return errors.New("pane not found")
+	}
+
+	out := &bytes.Buffer{}
+	d := New(mgr, client, newMockPreparer(), launch, out)
+
+	assignments, _ := d.Tick(ctx)
+	if len(assignments) != 0 {
+		t.Errorf("Tick() assigned %d tasks, want 0", len(assignments))
+	}
+	if len(mgr.released) != 1 || mgr.released[0] != "worker-1" {
+		t.Errorf("released = %v, want [worker-1]", mgr.released)
+	}
+
+	got, _ := client.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusOpen {
+		t.Errorf("task status = %v, want %v", got.Status, airyra.StatusOpen)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("pane not found")) {
+		t.Errorf("output %q does not mention the failure", out.String())
+	}
+}
+
+func TestTick_CompletesRequestedTasks(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+	client.ClaimTask(ctx, task.ID)
+	next, _ := client.AddTask(ctx, "Next")
+
+	w := running("worker-1")
+	w.TaskID = task.ID
+	mgr := newMockManager(client, w)
+	mgr.doneReqs["worker-1"] = true
+
+	d := New(mgr, client, newMockPreparer(), nil, &bytes.Buffer{})
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	got, _ := client.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusDone {
+		t.Errorf("task status = %v, want %v", got.Status, airyra.StatusDone)
+	}
+	if len(assignments) != 1 || assignments[0].TaskID != next.ID {
+		t.Errorf("assignments = %+v, want %s", assignments, next.ID)
+	}
+}
+
+func TestTick_FailedVerificationKeepsWorker(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+	client.ClaimTask(ctx, task.ID)
+	client.AddTask(ctx, "Next")
+
+	w := running("worker-1")
+	w.TaskID = task.ID
+	mgr := newMockManager(client, w)
+	mgr.doneReqs["worker-1"] = true
+	mgr.completeErr = &verify.FailedError{
+		TaskID: task.ID,
+		Report: &verify.Report{Results: []verify.Result{{Command: "make test", Err: errors.New("exit status 1")}}},
+	}
+
+	out := &bytes.Buffer{}
+	d := New(mgr, client, newMockPreparer(), nil, out)
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if len(assignments) != 0 {
+		t.Errorf("assignments = %+v, want none", assignments)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Blocked "+task.ID)) {
+		t.Errorf("output %q does not report the blocked task", out.String())
+	}
+	if mgr.doneReqs["worker-1"] {
+		t.Error("done request kept after the task was blocked by verification")
+	}
+}
+
+func TestTick_KeepsDoneRequestWhenCompleteFails(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+	client.ClaimTask(ctx, task.ID)
+
+	w := running("worker-1")
+	w.TaskID = task.ID
+	mgr := newMockManager(client, w)
+	mgr.doneReqs["worker-1"] = true
+	mgr.completeErr = errors.New("airyra unavailable")
+
+	d := New(mgr, client, newMockPreparer(), nil, &bytes.Buffer{})
+	if _, err := d.Tick(ctx); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if !mgr.doneReqs["worker-1"] {
+		t.Fatal("done request removed although the task was not completed")
+	}
+	if len(mgr.cleared) != 0 {
+		t.Errorf("cleared = %v, want the worker kept on its task", mgr.cleared)
+	}
+
+	mgr.completeErr = nil
+	if _, err := d.Tick(ctx); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if mgr.doneReqs["worker-1"] {
+		t.Error("done request kept after the task was completed")
+	}
+}
+
+func TestTick_BlocksRequestedTasks(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+	client.ClaimTask(ctx, task.ID)
+	client.AddTask(ctx, "Next")
+
+	w := running("worker-1")
+	w.TaskID = task.ID
+	mgr := newMockManager(client, w)
+	mgr.blockReqs["worker-1"] = "Which API version?"
+	mgr.doneReqs["worker-1"] = true
+
+	out := &bytes.Buffer{}
+	d := New(mgr, client, newMockPreparer(), nil, out)
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	got, _ := client.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusBlocked {
+		t.Errorf("task status = %v, want %v", got.Status, airyra.StatusBlocked)
+	}
+	if len(assignments) != 0 || len(mgr.cleared) != 0 {
+		t.Errorf("blocked worker was freed: assignments %+v, cleared %v", assignments, mgr.cleared)
+	}
+	if !mgr.doneReqs["worker-1"] {
+		t.Error("done request taken while blocking")
+	}
+	if len(mgr.stopped) != 1 || mgr.stopped[0] != "worker-1" {
+		t.Errorf("stopped = %v, want Claude stopped until the answer", mgr.stopped)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Blocked "+task.ID+" on worker-1: Which API version?")) {
+		t.Errorf("output %q does not report the blocker", out.String())
+	}
+}
+
+func TestTick_KeepsBlockRequestWhenBlockFails(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+	client.ClaimTask(ctx, task.ID)
+
+	w := running("worker-1")
+	w.TaskID = task.ID
+	mgr := newMockManager(client, w)
+	mgr.blockReqs["worker-1"] = "Which API version?"
+	mgr.blockErr = errors.New("airyra unavailable")
+
+	out := &bytes.Buffer{}
+	d := New(mgr, client, newMockPreparer(), nil, out)
+	if _, err := d.Tick(ctx); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	if mgr.blockReqs["worker-1"] != "Which API version?" {
+		t.Error("block request removed although the task was not blocked")
+	}
+	if !bytes.Contains(out.Bytes(), []byte("failed to block "+task.ID)) {
+		t.Errorf("output %q does not report the failure", out.String())
+	}
+
+	mgr.blockErr = nil
+	if _, err := d.Tick(ctx); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if _, ok := mgr.blockReqs["worker-1"]; ok {
+		t.Error("block request kept after the task was blocked")
+	}
+}
+
+func TestTick_SplitsPlannedTasks(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Big task")
+	client.ClaimTask(ctx, task.ID)
+
+	w := running("worker-1")
+	w.TaskID = task.ID
+	mgr := newMockManager(client, w)
+	mgr.planStates[task.ID] = plans.StatePending
+	mgr.planReqs["worker-1"] = []byte("Subtask")
+
+	prep := newMockPreparer()
+	out := &bytes.Buffer{}
+	d := New(mgr, client, prep, func(string, []string) error { return nil }, out)
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	if len(mgr.cleared) != 1 || mgr.cleared[0] != "worker-1" {
+		t.Errorf("cleared = %v, want [worker-1]", mgr.cleared)
+	}
+	if len(assignments) != 1 || assignments[0].TaskID == task.ID {
+		t.Fatalf("assignments = %+v, want the subtask and not the split task", assignments)
+	}
+	if prep.prepared["worker-1"].Plan {
+		t.Error("subtask prepared as a planning task")
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Split "+task.ID+" on worker-1 into 1 subtasks")) {
+		t.Errorf("output %q does not report the split", out.String())
+	}
+	if _, ok := mgr.planReqs["worker-1"]; ok {
+		t.Error("plan kept after the task was split")
+	}
+}
+
+func TestTick_KeepsPlanWhenSplitFails(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKept bool
+	}{
+		{"transient failure", errors.New("airyra unavailable"), true},
+		{"rejected plan", worker.ErrPlanRejected, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := airyra.NewMockClient()
+			task, _ := client.AddTask(ctx, "Big task")
+			client.ClaimTask(ctx, task.ID)
+
+			w := running("worker-1")
+			w.TaskID = task.ID
+			mgr := newMockManager(client, w)
+			mgr.planStates[task.ID] = plans.StatePending
+			mgr.planReqs["worker-1"] = []byte("Subtask")
+			mgr.splitErr = tt.err
+
+			d := New(mgr, client, newMockPreparer(), func(string, []string) error { return nil }, &bytes.Buffer{})
+			if _, err := d.Tick(ctx); err != nil {
+				t.Fatalf("Tick() error = %v", err)
+			}
+
+			if _, kept := mgr.planReqs["worker-1"]; kept != tt.wantKept {
+				t.Errorf("plan kept = %v, want %v", kept, tt.wantKept)
+			}
+			if len(mgr.cleared) != 0 {
+				t.Errorf("cleared = %v, want the worker to keep its task", mgr.cleared)
+			}
+		})
+	}
+}
+
+func TestTick_AssignsPlanningTasks(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Big task")
+
+	mgr := newMockManager(client, running("worker-1"))
+	mgr.planStates[task.ID] = plans.StatePending
+	mgr.planDone = []string{"ar-done"}
+
+	prep := newMockPreparer()
+	out := &bytes.Buffer{}
+	d := New(mgr, client, prep, func(string, []string) error { return nil }, out)
+	if _, err := d.Tick(ctx); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	if a := prep.prepared["worker-1"]; a == nil || !a.Plan {
+		t.Errorf("prepared %+v, want a planning task", a)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Completed ar-done: all subtasks done")) {
+		t.Errorf("output %q does not report the completed plan", out.String())
+	}
+}
+
+// reclaimFunc implements Reclaimer
+type reclaimFunc func(ctx context.Context) ([]watchdog.Reclaim, error)
+
+func (f reclaimFunc) Check(ctx context.Context) ([]watchdog.Reclaim, error) {
+	return f(ctx)
+}
+
+func TestTick_ReclaimsBeforeAssigning(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	stale, _ := client.AddTask(ctx, "Stale")
+	client.ClaimTask(ctx, stale.ID)
+
+	w := running("worker-1")
+	w.TaskID = stale.ID
+	mgr := newMockManager(client, w)
+
+	d := New(mgr, client, newMockPreparer(), nil, &bytes.Buffer{})
+	d.SetReclaimer(reclaimFunc(func(ctx context.Context) ([]watchdog.Reclaim, error) {
+		client.ReleaseTask(ctx, stale.ID, true)
+		mgr.ClearTask("worker-1")
+		return []watchdog.Reclaim{{Worker: "worker-1", TaskID: stale.ID}}, nil
+	}))
+
+	assignments, err := d.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if len(assignments) != 1 || assignments[0].TaskID != stale.ID {
+		t.Errorf("assignments = %+v, want reclaimed task %s reassigned", assignments, stale.ID)
+	}
+}
+
+func TestTick_NoAiryra(t *testing.T) {
+	d := New(newMockManager(nil), nil, newMockPreparer(), nil, &bytes.Buffer{})
+	if _, err := d.Tick(context.Background()); err == nil {
+		t.Error("Tick() with nil airyra client = nil, want error")
+	}
+}
+
+func TestRun_StopsOnCancel(t *testing.T) {
+	client := airyra.NewMockClient()
+	d := New(newMockManager(client), client, newMockPreparer(), nil, &bytes.Buffer{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := d.Run(ctx); err != nil {
+		t.Errorf("Run() error = %v, want nil", err)
+	}
+}
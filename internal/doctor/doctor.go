@@ -0,0 +1,378 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"isollm/internal/airyra"
+	"isollm/internal/barerepo"
+	"isollm/internal/state"
+	"isollm/internal/worker"
+)
+
+// Sources of an issue
+const (
+	SourceServices   = "services"
+	SourceState      = "state"
+	SourceContainers = "containers"
+	SourceTasks      = "tasks"
+	SourceRepo       = "repo"
+)
+
+// WorkerManager is the subset of worker.Manager used by doctor
+type WorkerManager interface {
+	List() ([]worker.WorkerInfo, error)
+	ClearTask(name string) error
+}
+
+// Repo is the subset of barerepo.BareRepo used by doctor
+type Repo interface {
+	ListTaskBranches() ([]barerepo.BranchInfo, error)
+	GetBranchCommitCount(branchName, baseBranch string) (int, error)
+	DeleteBranch(branchName string) error
+}
+
+// Issue is one inconsistency between worker state, containers, airyra and
+// the bare repo
+type Issue struct {
+	Source  string
+	Problem string
+	Repair  string // What Fix does, or what to do by hand
+	fix     func(ctx context.Context) error
+}
+
+// Fixable returns true if Fix can repair the issue
+func (i Issue) Fixable() bool {
+	return i.fix != nil
+}
+
+// Fix applies the issue's repair
+func (i Issue) Fix(ctx context.Context) error {
+	if i.fix == nil {
+		return fmt.Errorf("no automatic fix: %s", i.Repair)
+	}
+	return i.fix(ctx)
+}
+
+// Doctor cross-checks worker state, containers, airyra and the bare repo
+type Doctor struct {
+	state       state.State
+	mgr         WorkerManager
+	airyra      airyra.TaskClient
+	repo        Repo // Nil if there is no bare repo
+	baseBranch  string
+	startAiryra func(ctx context.Context) error
+}
+
+// New creates a Doctor. repo may be nil if the bare repo does not exist.
+func New(st state.State, mgr WorkerManager, client airyra.TaskClient, repo Repo, baseBranch string) *Doctor {
+	return &Doctor{
+		state:      st,
+		mgr:        mgr,
+		airyra:     client,
+		repo:       repo,
+		baseBranch: baseBranch,
+	}
+}
+
+// SetAiryraStarter lets Fix start the airyra server when it is not running
+func (d *Doctor) SetAiryraStarter(start func(ctx context.Context) error) {
+	d.startAiryra = start
+}
+
+// Check returns every inconsistency it finds. Checks that need airyra are
+// skipped while it is not running.
+func (d *Doctor) Check(ctx context.Context) ([]Issue, error) {
+	var issues []Issue
+
+	running := d.airyra != nil && d.airyra.IsServerRunning(ctx)
+	if !running {
+		issue := Issue{
+			Source:  SourceServices,
+			Problem: "airyra server is not running; task checks were skipped",
+			Repair:  "start it with 'isollm up'",
+		}
+		if d.startAiryra != nil {
+			issue.Repair = "start the airyra server"
+			issue.fix = d.startAiryra
+		}
+		issues = append(issues, issue)
+	}
+
+	issues = append(issues, d.checkSession()...)
+
+	containers, err := d.mgr.List()
+	if err != nil {
+		return issues, fmt.Errorf("failed to list workers: %w", err)
+	}
+	byName := make(map[string]worker.WorkerInfo, len(containers))
+	for _, c := range containers {
+		byName[c.Name] = c
+	}
+
+	loaded, err := d.state.LoadAllWorkers()
+	if err != nil {
+		return issues, fmt.Errorf("failed to load worker state: %w", err)
+	}
+	for _, e := range loaded.Errors {
+		issues = append(issues, Issue{
+			Source:  SourceState,
+			Problem: e.Error(),
+			Repair:  "move the unreadable file aside",
+			fix:     d.recover,
+		})
+	}
+	sort.Slice(loaded.Workers, func(i, j int) bool { return loaded.Workers[i].Name < loaded.Workers[j].Name })
+
+	held := make(map[string]string) // Task ID -> worker holding it in state
+	for _, w := range loaded.Workers {
+		if w.HasTask() {
+			held[w.CurrentTask.ID] = w.Name
+		}
+		if c, ok := byName[w.Name]; ok {
+			if running && w.HasTask() {
+				issues = append(issues, d.checkAssignment(ctx, w, c)...)
+			}
+			continue
+		}
+		issues = append(issues, d.orphanState(w, running))
+	}
+
+	if !running {
+		return issues, nil
+	}
+
+	tasks, err := airyra.ListAllTasks(ctx, d.airyra)
+	if err != nil {
+		return issues, err
+	}
+	byID := make(map[string]*airyra.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+		if t.Status == airyra.StatusInProgress && held[t.ID] == "" {
+			if issue, ok := d.checkUnheld(t, byName); ok {
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	if d.repo != nil {
+		repoIssues, err := d.checkBranches(byID)
+		issues = append(issues, repoIssues...)
+		if err != nil {
+			return issues, err
+		}
+	}
+
+	return issues, nil
+}
+
+// checkSession reports a session whose isollm up or down died part way
+func (d *Doctor) checkSession() []Issue {
+	session, err := d.state.LoadSession()
+	if errors.Is(err, state.ErrNoSession) {
+		return nil
+	}
+	if err != nil {
+		return []Issue{{
+			Source:  SourceState,
+			Problem: err.Error(),
+			Repair:  "move the unreadable session aside",
+			fix:     d.recover,
+		}}
+	}
+	if session.Status == state.SessionStatusRunning {
+		return nil
+	}
+	if active, err := d.state.HasActiveSession(); err != nil || active {
+		return nil
+	}
+
+	return []Issue{{
+		Source:  SourceState,
+		Problem: fmt.Sprintf("session is %s but its process (pid %d) is gone", session.Status, session.PID),
+		Repair:  "clear the session",
+		fix:     d.recover,
+	}}
+}
+
+// checkAssignment compares a worker's task assignment with airyra
+func (d *Doctor) checkAssignment(ctx context.Context, w *state.WorkerState, c worker.WorkerInfo) []Issue {
+	id := w.CurrentTask.ID
+	task, err := d.airyra.GetTask(ctx, id)
+	switch {
+	case err != nil && airyra.IsTaskNotFound(err):
+		return []Issue{{
+			Source:  SourceState,
+			Problem: fmt.Sprintf("%s is assigned %s, which no longer exists", w.Name, id),
+			Repair:  "clear the assignment",
+			fix:     d.clearTask(w.Name),
+		}}
+	case err != nil:
+		return nil // Can't tell
+	case task.Status == airyra.StatusDone || task.Status == airyra.StatusOpen:
+		return []Issue{{
+			Source:  SourceState,
+			Problem: fmt.Sprintf("%s is assigned %s, which is %s", w.Name, id, task.Status),
+			Repair:  "clear the assignment",
+			fix:     d.clearTask(w.Name),
+		}}
+	case task.Status == airyra.StatusInProgress && c.Status != "RUNNING":
+		return []Issue{{
+			Source:  SourceContainers,
+			Problem: fmt.Sprintf("%s is %s but holds in-progress task %s", w.Name, c.Status, id),
+			Repair:  "release the task back to the queue",
+			fix:     d.releaseTask(id, w.Name),
+		}}
+	}
+	return nil
+}
+
+// orphanState reports state kept for a worker that has no container
+func (d *Doctor) orphanState(w *state.WorkerState, running bool) Issue {
+	issue := Issue{
+		Source:  SourceState,
+		Problem: fmt.Sprintf("state kept for %s, which has no container", w.Name),
+		Repair:  "delete the state",
+	}
+	if w.HasTask() {
+		issue.Problem += fmt.Sprintf(" (assigned %s)", w.CurrentTask.ID)
+		if running {
+			issue.Repair = "release " + w.CurrentTask.ID + " if in progress and delete the state"
+		}
+	}
+
+	name := w.Name
+	issue.fix = func(ctx context.Context) error {
+		if w.HasTask() && running {
+			if err := d.releaseIfInProgress(ctx, w.CurrentTask.ID); err != nil {
+				return err
+			}
+		}
+		return d.state.DeleteWorker(name)
+	}
+	return issue
+}
+
+// checkUnheld reports an in-progress task no worker state holds, when the
+// agent that claimed it is not a running worker. Tasks claimed by other
+// agents, such as a person on the host, are left alone.
+func (d *Doctor) checkUnheld(t *airyra.Task, containers map[string]worker.WorkerInfo) (Issue, bool) {
+	var problem string
+	switch {
+	case t.ClaimedBy == nil || *t.ClaimedBy == "":
+		problem = fmt.Sprintf("%s is in progress but nobody holds it", t.ID)
+	case !strings.HasPrefix(*t.ClaimedBy, worker.WorkerPrefix):
+		return Issue{}, false
+	default:
+		c, ok := containers[*t.ClaimedBy]
+		switch {
+		case !ok:
+			problem = fmt.Sprintf("%s is in progress for %s, which has no container", t.ID, *t.ClaimedBy)
+		case c.Status != "RUNNING":
+			problem = fmt.Sprintf("%s is in progress for %s, which is %s", t.ID, c.Name, c.Status)
+		default:
+			return Issue{}, false // Claimed from inside the worker
+		}
+	}
+
+	return Issue{
+		Source:  SourceTasks,
+		Problem: problem,
+		Repair:  "release it back to the queue",
+		fix:     d.releaseTask(t.ID, ""),
+	}, true
+}
+
+// checkBranches reports task branches whose task is gone or done
+func (d *Doctor) checkBranches(tasks map[string]*airyra.Task) ([]Issue, error) {
+	branches, err := d.repo.ListTaskBranches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task branches: %w", err)
+	}
+
+	var issues []Issue
+	for _, b := range branches {
+		task, ok := tasks[b.TaskID]
+		if ok && task.Status != airyra.StatusDone {
+			continue
+		}
+
+		status := "deleted"
+		if ok {
+			status = "done"
+		}
+
+		ahead, err := d.repo.GetBranchCommitCount(b.Name, d.baseBranch)
+		if err != nil {
+			continue
+		}
+		if ahead > 0 {
+			issues = append(issues, Issue{
+				Source:  SourceRepo,
+				Problem: fmt.Sprintf("%s has %d commit(s) not on %s, but task %s is %s", b.Name, ahead, d.baseBranch, b.TaskID, status),
+				Repair:  "merge it with 'isollm merge " + b.TaskID + "' or delete it by hand",
+			})
+			continue
+		}
+
+		name := b.Name
+		issues = append(issues, Issue{
+			Source:  SourceRepo,
+			Problem: fmt.Sprintf("%s is merged into %s and task %s is %s", b.Name, d.baseBranch, b.TaskID, status),
+			Repair:  "delete the branch",
+			fix: func(ctx context.Context) error {
+				return d.repo.DeleteBranch(name)
+			},
+		})
+	}
+	return issues, nil
+}
+
+// recover repairs state files with state.Recover
+func (d *Doctor) recover(ctx context.Context) error {
+	_, err := d.state.Recover()
+	return err
+}
+
+// clearTask returns a fix clearing a worker's task assignment
+func (d *Doctor) clearTask(workerName string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return d.mgr.ClearTask(workerName)
+	}
+}
+
+// releaseTask returns a fix force-releasing a task and, if a worker is
+// given, clearing its assignment
+func (d *Doctor) releaseTask(taskID, workerName string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if _, err := d.airyra.ReleaseTask(ctx, taskID, true); err != nil {
+			return fmt.Errorf("failed to release %s: %w", taskID, err)
+		}
+		if workerName == "" {
+			return nil
+		}
+		return d.mgr.ClearTask(workerName)
+	}
+}
+
+// releaseIfInProgress force-releases a task that is still in progress
+func (d *Doctor) releaseIfInProgress(ctx context.Context, taskID string) error {
+	task, err := d.airyra.GetTask(ctx, taskID)
+	if err != nil {
+		if airyra.IsTaskNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get %s: %w", taskID, err)
+	}
+	if task.Status != airyra.StatusInProgress {
+		return nil
+	}
+	if _, err := d.airyra.ReleaseTask(ctx, taskID, true); err != nil {
+		return fmt.Errorf("failed to release %s: %w", taskID, err)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"isollm/internal/airyra"
+	"isollm/internal/barerepo"
+	"isollm/internal/state"
+	"isollm/internal/worker"
+)
+
+type mockManager struct {
+	workers []worker.WorkerInfo
+	cleared []string
+}
+
+func (m *mockManager) List() ([]worker.WorkerInfo, error) {
+	return m.workers, nil
+}
+
+func (m *mockManager) ClearTask(name string) error {
+	m.cleared = append(m.cleared, name)
+	return nil
+}
+
+type mockRepo struct {
+	branches []barerepo.BranchInfo
+	ahead    map[string]int
+	deleted  []string
+}
+
+func (r *mockRepo) ListTaskBranches() ([]barerepo.BranchInfo, error) {
+	return r.branches, nil
+}
+
+func (r *mockRepo) GetBranchCommitCount(branchName, baseBranch string) (int, error) {
+	return r.ahead[branchName], nil
+}
+
+func (r *mockRepo) DeleteBranch(branchName string) error {
+	r.deleted = append(r.deleted, branchName)
+	return nil
+}
+
+type env struct {
+	doctor *Doctor
+	state  *state.FileState
+	mgr    *mockManager
+	client *airyra.MockClient
+	repo   *mockRepo
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	e := &env{
+		state:  state.NewWithDir(t.TempDir()),
+		mgr:    &mockManager{},
+		client: airyra.NewMockClient(),
+		repo:   &mockRepo{ahead: map[string]int{}},
+	}
+	e.doctor = New(e.state, e.mgr, e.client, e.repo, "main")
+	return e
+}
+
+// claim adds a task claimed by agent
+func (e *env) claim(t *testing.T, agent string) *airyra.Task {
+	t.Helper()
+	ctx := context.Background()
+	task, _ := e.client.AddTask(ctx, "Task for "+agent)
+	e.client.SetAgentID(agent)
+	if _, err := e.client.ClaimTask(ctx, task.ID); err != nil {
+		t.Fatalf("ClaimTask() error = %v", err)
+	}
+	return task
+}
+
+func (e *env) assign(t *testing.T, name, taskID string) {
+	t.Helper()
+	err := e.state.UpdateWorker(name, func(w *state.WorkerState) {
+		w.Status = state.WorkerStatusBusy
+		w.CurrentTask = &state.TaskRef{ID: taskID}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (e *env) check(t *testing.T) []Issue {
+	t.Helper()
+	issues, err := e.doctor.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	return issues
+}
+
+func fixAll(t *testing.T, issues []Issue) {
+	t.Helper()
+	for _, issue := range issues {
+		if err := issue.Fix(context.Background()); err != nil {
+			t.Fatalf("Fix(%q) error = %v", issue.Problem, err)
+		}
+	}
+}
+
+func TestCheck_Healthy(t *testing.T) {
+	e := newEnv(t)
+	task := e.claim(t, "worker-1")
+	e.assign(t, "worker-1", task.ID)
+	e.mgr.workers = []worker.WorkerInfo{{Name: "worker-1", Status: "RUNNING", TaskID: task.ID}}
+	e.repo.branches = []barerepo.BranchInfo{{Name: "isollm/" + task.ID, TaskID: task.ID}}
+
+	// Claimed from inside a running worker, without isollm's state
+	e.claim(t, "worker-2")
+	e.mgr.workers = append(e.mgr.workers, worker.WorkerInfo{Name: "worker-2", Status: "RUNNING"})
+
+	// Claimed by someone on the host
+	e.claim(t, "alice@host:/src")
+
+	if issues := e.check(t); len(issues) != 0 {
+		t.Errorf("Check() = %+v, want no issues", issues)
+	}
+}
+
+func TestCheck_StateForRemovedWorker(t *testing.T) {
+	e := newEnv(t)
+	task := e.claim(t, "worker-3")
+	e.assign(t, "worker-3", task.ID)
+
+	issues := e.check(t)
+	if len(issues) != 1 || issues[0].Source != SourceState || !strings.Contains(issues[0].Problem, "no container") {
+		t.Fatalf("Check() = %+v, want the orphaned state", issues)
+	}
+
+	fixAll(t, issues)
+	if _, err := e.state.LoadWorker("worker-3"); !errors.Is(err, state.ErrNoWorker) {
+		t.Errorf("LoadWorker() error = %v, want the state deleted", err)
+	}
+	if got, _ := e.client.GetTask(context.Background(), task.ID); got.Status != airyra.StatusOpen {
+		t.Errorf("task status = %s, want released", got.Status)
+	}
+	if issues := e.check(t); len(issues) != 0 {
+		t.Errorf("Check() after fix = %+v", issues)
+	}
+}
+
+func TestCheck_StaleAssignment(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+	task := e.claim(t, "worker-1")
+	e.client.CompleteTask(ctx, task.ID)
+	e.assign(t, "worker-1", task.ID)
+	e.assign(t, "worker-2", "ar-gone")
+	e.mgr.workers = []worker.WorkerInfo{
+		{Name: "worker-1", Status: "RUNNING"},
+		{Name: "worker-2", Status: "RUNNING"},
+	}
+
+	issues := e.check(t)
+	if len(issues) != 2 {
+		t.Fatalf("Check() = %+v, want 2 issues", issues)
+	}
+
+	fixAll(t, issues)
+	if len(e.mgr.cleared) != 2 {
+		t.Errorf("cleared = %v, want both workers", e.mgr.cleared)
+	}
+}
+
+func TestCheck_TaskOfStoppedWorker(t *testing.T) {
+	e := newEnv(t)
+	held := e.claim(t, "worker-1")
+	e.assign(t, "worker-1", held.ID)
+	unheld := e.claim(t, "worker-2")
+	e.mgr.workers = []worker.WorkerInfo{
+		{Name: "worker-1", Status: "STOPPED"},
+		{Name: "worker-2", Status: "STOPPED"},
+	}
+
+	issues := e.check(t)
+	if len(issues) != 2 {
+		t.Fatalf("Check() = %+v, want 2 issues", issues)
+	}
+
+	fixAll(t, issues)
+	ctx := context.Background()
+	for _, id := range []string{held.ID, unheld.ID} {
+		if got, _ := e.client.GetTask(ctx, id); got.Status != airyra.StatusOpen {
+			t.Errorf("%s status = %s, want released", id, got.Status)
+		}
+	}
+	if len(e.mgr.cleared) != 1 || e.mgr.cleared[0] != "worker-1" {
+		t.Errorf("cleared = %v, want worker-1", e.mgr.cleared)
+	}
+}
+
+func TestCheck_Branches(t *testing.T) {
+	e := newEnv(t)
+	ctx := context.Background()
+	done := e.claim(t, "worker-1")
+	e.client.CompleteTask(ctx, done.ID)
+	e.repo.branches = []barerepo.BranchInfo{
+		{Name: "isollm/" + done.ID, TaskID: done.ID},
+		{Name: "isollm/ar-gone", TaskID: "ar-gone"},
+		{Name: "isollm/ar-lost", TaskID: "ar-lost"},
+	}
+	e.repo.ahead["isollm/ar-lost"] = 2
+
+	issues := e.check(t)
+	if len(issues) != 3 {
+		t.Fatalf("Check() = %+v, want 3 issues", issues)
+	}
+	for _, issue := range issues {
+		if issue.Fixable() {
+			issue.Fix(ctx)
+		} else if !strings.Contains(issue.Problem, "ar-lost") {
+			t.Errorf("%q not fixable", issue.Problem)
+		}
+	}
+
+	// Unmerged work is never deleted
+	if len(e.repo.deleted) != 2 {
+		t.Errorf("deleted = %v, want the 2 merged branches", e.repo.deleted)
+	}
+}
+
+func TestCheck_AiryraDown(t *testing.T) {
+	e := newEnv(t)
+	e.client.ServerRunning = false
+
+	issues := e.check(t)
+	if len(issues) != 1 || issues[0].Source != SourceServices || issues[0].Fixable() {
+		t.Fatalf("Check() = %+v, want airyra reported without a fix", issues)
+	}
+
+	started := false
+	e.doctor.SetAiryraStarter(func(ctx context.Context) error {
+		started = true
+		return nil
+	})
+	issues = e.check(t)
+	fixAll(t, issues)
+	if !started {
+		t.Error("Fix() did not start airyra")
+	}
+}
+
+func TestCheck_InterruptedSession(t *testing.T) {
+	e := newEnv(t)
+	e.state.CreateSession(&state.Session{Status: state.SessionStatusShuttingDown, PID: 999999999})
+
+	issues := e.check(t)
+	if len(issues) != 1 || !strings.Contains(issues[0].Problem, "shutting_down") {
+		t.Fatalf("Check() = %+v, want the session reported", issues)
+	}
+
+	fixAll(t, issues)
+	if _, err := e.state.LoadSession(); !errors.Is(err, state.ErrNoSession) {
+		t.Errorf("LoadSession() error = %v, want the session cleared", err)
+	}
+}
@@ -0,0 +1,324 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Type names what happened. Types are "<subject>.<verb>" so a filter on
+// the subject ("task") matches every task event.
+type Type string
+
+const (
+	SessionStarted Type = "session.started" // isollm up
+	SessionStopped Type = "session.stopped" // isollm down
+
+	WorkerCreated Type = "worker.created"
+	WorkerFailed  Type = "worker.failed" // Provisioning failed
+	WorkerStarted Type = "worker.started"
+	WorkerStopped Type = "worker.stopped"
+	WorkerReset   Type = "worker.reset"
+	WorkerRemoved Type = "worker.removed"
+
+	TaskClaimed   Type = "task.claimed"
+	TaskReleased  Type = "task.released"
+	TaskReclaimed Type = "task.reclaimed" // Taken back by the watchdog
+	TaskCompleted Type = "task.completed"
+	TaskBlocked   Type = "task.blocked"
+	TaskUnblocked Type = "task.unblocked" // Blocker answered or cleared
+	TaskSplit     Type = "task.split"     // Divided into subtasks by a planner
+
+	BranchSalvaged Type = "branch.salvaged"
+	BranchMerged   Type = "branch.merged"
+
+	SyncPushed Type = "sync.pushed"
+	SyncPulled Type = "sync.pulled"
+)
+
+// Event is one entry in the session history
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    Type      `json:"type"`
+	Worker  string    `json:"worker,omitempty"`
+	TaskID  string    `json:"task_id,omitempty"`
+	Branch  string    `json:"branch,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Filter selects events. Zero fields match everything.
+type Filter struct {
+	Types  []string  // Exact types or subjects ("task")
+	Since  time.Time // Only events at or after this time
+	Worker string
+	TaskID string
+}
+
+// Match reports whether an event passes the filter
+func (f Filter) Match(e Event) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.Worker != "" && e.Worker != f.Worker {
+		return false
+	}
+	if f.TaskID != "" && e.TaskID != f.TaskID {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if string(e.Type) == t || strings.HasPrefix(string(e.Type), t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Log is an append-only event log, kept as one JSONL file per day in
+// .isollm/events
+type Log struct {
+	dir       string
+	now       func() time.Time
+	listeners []func(Event)
+}
+
+// New creates a Log for the project's .isollm directory
+func New(projectRoot string) *Log {
+	return NewWithDir(filepath.Join(projectRoot, ".isollm", "events"))
+}
+
+// NewWithDir creates a Log with a custom directory (for testing)
+func NewWithDir(dir string) *Log {
+	return &Log{dir: dir, now: time.Now}
+}
+
+// OnEmit registers fn to be called with every emitted event, whether or
+// not it could be written
+func (l *Log) OnEmit(fn func(Event)) {
+	l.listeners = append(l.listeners, fn)
+}
+
+// Emit appends an event. Each event is written with a single append, so
+// several isollm processes can share the log.
+func (l *Log) Emit(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = l.now()
+	}
+	err := l.write(e)
+	for _, fn := range l.listeners {
+		fn(e)
+	}
+	return err
+}
+
+// write appends an event to its day's file
+func (l *Log) write(e Event) error {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create events directory: %w", err)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	path := l.path(e.Time)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write event log %s: %w", path, err)
+	}
+	return nil
+}
+
+// path returns the file holding a day's events
+func (l *Log) path(t time.Time) string {
+	return filepath.Join(l.dir, t.Local().Format("2006-01-02")+".jsonl")
+}
+
+// files returns the daily files that may hold events since a time, oldest
+// first
+func (l *Log) files(since time.Time) ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read events directory: %w", err)
+	}
+
+	// Files are named by local date; compare dates, not times
+	first := ""
+	if !since.IsZero() {
+		first = since.Local().Format("2006-01-02")
+	}
+
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		if strings.TrimSuffix(name, ".jsonl") < first {
+			continue
+		}
+		files = append(files, filepath.Join(l.dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Position is where a read of the log stopped: the last daily file read
+// and the offset of the first line not read from it
+type Position struct {
+	Path   string
+	Offset int64
+}
+
+// Read returns the events matching a filter, oldest first
+func (l *Log) Read(f Filter) ([]Event, error) {
+	events, _, err := l.ReadPosition(f)
+	return events, err
+}
+
+// ReadPosition is Read that also returns where reading stopped, for
+// FollowFrom to carry on without missing or repeating events
+func (l *Log) ReadPosition(f Filter) ([]Event, Position, error) {
+	files, err := l.files(f.Since)
+	if err != nil {
+		return nil, Position{}, err
+	}
+
+	var events []Event
+	var pos Position
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, Position{}, fmt.Errorf("failed to open event log %s: %w", path, err)
+		}
+		n, err := scan(file, f, func(e Event) error {
+			events = append(events, e)
+			return nil
+		})
+		file.Close()
+		if err != nil {
+			return nil, Position{}, fmt.Errorf("failed to read event log %s: %w", path, err)
+		}
+		pos = Position{Path: path, Offset: n}
+	}
+	return events, pos, nil
+}
+
+// Follow calls fn for every matching event appended after it is called,
+// polling every interval until the context is cancelled
+func (l *Log) Follow(ctx context.Context, f Filter, interval time.Duration, fn func(Event) error) error {
+	// Start from the current end of today's file
+	pos := Position{Path: l.path(l.now())}
+	if info, err := os.Stat(pos.Path); err == nil {
+		pos.Offset = info.Size()
+	}
+	return l.FollowFrom(ctx, f, pos, interval, fn)
+}
+
+// FollowFrom calls fn for every matching event from a position on, polling
+// every interval until the context is cancelled. A zero position starts at
+// the beginning of today's file.
+func (l *Log) FollowFrom(ctx context.Context, f Filter, pos Position, interval time.Duration, fn func(Event) error) error {
+	path, offset := pos.Path, pos.Offset
+	if path == "" {
+		path, offset = l.path(l.now()), 0
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Carry on in the next day's file after midnight
+		if next := l.path(l.now()); next != path {
+			if err := l.readFrom(path, offset, f, fn); err != nil {
+				return err
+			}
+			path, offset = next, 0
+		}
+
+		file, err := os.Open(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to open event log %s: %w", path, err)
+		}
+		if err == nil {
+			if _, err := file.Seek(offset, io.SeekStart); err != nil {
+				file.Close()
+				return fmt.Errorf("failed to seek event log %s: %w", path, err)
+			}
+			n, err := scan(file, f, fn)
+			file.Close()
+			offset += n
+			if err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readFrom reads the rest of a file from offset
+func (l *Log) readFrom(path string, offset int64, f Filter, fn func(Event) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open event log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek event log %s: %w", path, err)
+	}
+	_, err = scan(file, f, fn)
+	return err
+}
+
+// scan decodes complete lines from r, passing matching events to fn. It
+// returns the number of bytes consumed; a trailing partial line is left
+// for the next read. Lines that do not decode are skipped.
+func scan(r io.Reader, f Filter, fn func(Event) error) (int64, error) {
+	reader := bufio.NewReader(r)
+	var consumed int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF {
+				return consumed, nil
+			}
+			return consumed, err
+		}
+		consumed += int64(len(line))
+
+		var e Event
+		if json.Unmarshal(line, &e) != nil || !f.Match(e) {
+			continue
+		}
+		if err := fn(e); err != nil {
+			return consumed, err
+		}
+	}
+}
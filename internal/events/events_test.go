@@ -0,0 +1,215 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEmitAndRead(t *testing.T) {
+	log := NewWithDir(t.TempDir())
+	day1 := time.Date(2026, 3, 1, 23, 0, 0, 0, time.Local)
+	day2 := day1.Add(2 * time.Hour)
+
+	for _, e := range []Event{
+		{Time: day1, Type: WorkerCreated, Worker: "worker-1"},
+		{Time: day1.Add(time.Minute), Type: TaskClaimed, Worker: "worker-1", TaskID: "ar-1"},
+		{Time: day2, Type: TaskCompleted, Worker: "worker-1", TaskID: "ar-1"},
+		{Time: day2.Add(time.Minute), Type: SyncPushed, Message: "main"},
+	} {
+		if err := log.Emit(e); err != nil {
+			t.Fatalf("Emit() error = %v", err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(log.dir, "*.jsonl"))
+	if len(files) != 2 {
+		t.Errorf("wrote %d files, want one per day", len(files))
+	}
+
+	all, err := log.Read(Filter{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(all) != 4 || all[0].Type != WorkerCreated || all[3].Type != SyncPushed {
+		t.Errorf("Read() = %+v, want all 4 in order", all)
+	}
+
+	tasks, _ := log.Read(Filter{Types: []string{"task"}})
+	if len(tasks) != 2 {
+		t.Errorf("Read(task) = %d events, want 2", len(tasks))
+	}
+
+	since, _ := log.Read(Filter{Since: day2})
+	if len(since) != 2 || since[0].Type != TaskCompleted {
+		t.Errorf("Read(since) = %+v, want the second day's events", since)
+	}
+
+	exact, _ := log.Read(Filter{Types: []string{"task.claimed", "sync"}})
+	if len(exact) != 2 {
+		t.Errorf("Read(task.claimed, sync) = %d events, want 2", len(exact))
+	}
+}
+
+func TestOnEmit(t *testing.T) {
+	// A file where the directory should be makes writes fail
+	dir := filepath.Join(t.TempDir(), "events")
+	os.WriteFile(dir, nil, 0644)
+	log := NewWithDir(dir)
+
+	var got []Event
+	log.OnEmit(func(e Event) { got = append(got, e) })
+
+	if err := log.Emit(Event{Type: TaskBlocked, TaskID: "ar-1"}); err == nil {
+		t.Error("Emit() should fail when the log cannot be written")
+	}
+	if len(got) != 1 || got[0].Type != TaskBlocked || got[0].Time.IsZero() {
+		t.Errorf("listener got %+v, want the stamped event even on write failure", got)
+	}
+}
+
+func TestRead_Empty(t *testing.T) {
+	events, err := NewWithDir(filepath.Join(t.TempDir(), "missing")).Read(Filter{})
+	if err != nil || len(events) != 0 {
+		t.Errorf("Read() = %v, %v; want nothing", events, err)
+	}
+}
+
+func TestRead_SkipsCorruptLines(t *testing.T) {
+	log := NewWithDir(t.TempDir())
+	log.Emit(Event{Type: WorkerStarted, Worker: "worker-1"})
+
+	f, _ := os.OpenFile(log.path(time.Now()), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("not json\n")
+	f.Close()
+	log.Emit(Event{Type: WorkerStopped, Worker: "worker-1"})
+
+	events, err := log.Read(Filter{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("Read() = %d events, want 2", len(events))
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	e := Event{Time: time.Now(), Type: TaskReleased, Worker: "worker-2", TaskID: "ar-9"}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty", Filter{}, true},
+		{"subject", Filter{Types: []string{"task"}}, true},
+		{"exact", Filter{Types: []string{"task.released"}}, true},
+		{"other type", Filter{Types: []string{"worker"}}, false},
+		{"prefix is not a subject", Filter{Types: []string{"ta"}}, false},
+		{"worker", Filter{Worker: "worker-2"}, true},
+		{"other worker", Filter{Worker: "worker-1"}, false},
+		{"task", Filter{TaskID: "ar-9"}, true},
+		{"future", Filter{Since: time.Now().Add(time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(e); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollow(t *testing.T) {
+	log := NewWithDir(t.TempDir())
+	log.Emit(Event{Type: WorkerCreated, Worker: "worker-1"}) // Before following
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var got []Event
+	done := make(chan error)
+	go func() {
+		done <- log.Follow(ctx, Filter{Types: []string{"task"}}, 5*time.Millisecond, func(e Event) error {
+			mu.Lock()
+			got = append(got, e)
+			mu.Unlock()
+			return nil
+		})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	log.Emit(Event{Type: WorkerStarted, Worker: "worker-1"})
+	log.Emit(Event{Type: TaskClaimed, Worker: "worker-1", TaskID: "ar-1"})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(got)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Follow() error = %v", err)
+	}
+
+	if len(got) != 1 || got[0].TaskID != "ar-1" {
+		t.Errorf("followed %+v, want only the new task event", got)
+	}
+}
+
+func TestFollowFrom_ReadPosition(t *testing.T) {
+	log := NewWithDir(t.TempDir())
+	log.Emit(Event{Type: TaskClaimed, TaskID: "ar-1"})
+
+	history, pos, err := log.ReadPosition(Filter{})
+	if err != nil {
+		t.Fatalf("ReadPosition() error = %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("ReadPosition() = %+v, want one event", history)
+	}
+
+	// Written after reading but before following
+	log.Emit(Event{Type: TaskCompleted, TaskID: "ar-1"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []Event
+	err = log.FollowFrom(ctx, Filter{}, pos, time.Hour, func(e Event) error {
+		got = append(got, e)
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FollowFrom() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Type != TaskCompleted {
+		t.Errorf("followed %+v, want only the event written after reading", got)
+	}
+}
+
+func TestRead_SinceInOtherZone(t *testing.T) {
+	log := NewWithDir(t.TempDir())
+	now := time.Now()
+	log.Emit(Event{Type: TaskClaimed, TaskID: "ar-1", Time: now})
+
+	// The same instant in a zone where it is another day must still find
+	// today's file, which is named by the local date
+	for _, zone := range []*time.Location{time.FixedZone("east", 14*3600), time.FixedZone("west", -12*3600)} {
+		got, err := log.Read(Filter{Since: now.Add(-time.Second).In(zone)})
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if len(got) != 1 {
+			t.Errorf("Read() since %v = %d events, want 1", zone, len(got))
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"isollm/internal/config"
+	"isollm/internal/events"
+)
+
+// eventHooks maps the event names used in isollm.yaml to the events that
+// fire them
+var eventHooks = map[events.Type]string{
+	events.TaskClaimed:    "task.claimed",
+	events.TaskCompleted:  "task.done",
+	events.TaskBlocked:    "task.blocked",
+	events.WorkerFailed:   "worker.error",
+	events.SessionStopped: "session.down",
+}
+
+// Payload is the JSON sent to a hook on stdin or as the request body
+type Payload struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	Project string    `json:"project"`
+	Worker  string    `json:"worker,omitempty"`
+	TaskID  string    `json:"task_id,omitempty"`
+	Branch  string    `json:"branch,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Runner fires the hooks configured in isollm.yaml
+type Runner struct {
+	project string
+	hooks   []config.HookConfig
+	client  *http.Client
+	errOut  io.Writer
+}
+
+// New creates a Runner for the config's hooks. Hook failures are reported
+// to errOut.
+func New(cfg *config.Config, errOut io.Writer) *Runner {
+	return &Runner{
+		project: cfg.Project,
+		hooks:   cfg.Hooks,
+		client:  &http.Client{},
+		errOut:  errOut,
+	}
+}
+
+// Attach fires the config's hooks for events emitted on log. It does
+// nothing when no hooks are configured.
+func Attach(log *events.Log, cfg *config.Config) {
+	if len(cfg.Hooks) == 0 {
+		return
+	}
+	r := New(cfg, os.Stderr)
+	log.OnEmit(r.Fire)
+}
+
+// Fire runs every hook subscribed to the event and waits for them to
+// finish. Failures are reported, never returned: a broken hook must not
+// stop a task or a session.
+func (r *Runner) Fire(e events.Event) {
+	name, ok := eventHooks[e.Type]
+	if !ok {
+		return
+	}
+
+	payload := Payload{
+		Event:   name,
+		Time:    e.Time,
+		Project: r.project,
+		Worker:  e.Worker,
+		TaskID:  e.TaskID,
+		Branch:  e.Branch,
+		Message: e.Message,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	for i, h := range r.hooks {
+		if !subscribed(h, name) {
+			continue
+		}
+		if err := r.run(h, payload, data); err != nil {
+			fmt.Fprintf(r.errOut, "Warning: hooks[%d] for %s failed: %v\n", i, name, err)
+		}
+	}
+}
+
+// subscribed reports whether a hook fires on an event
+func subscribed(h config.HookConfig, name string) bool {
+	for _, on := range h.On {
+		if on == name {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs one hook within its timeout
+func (r *Runner) run(h config.HookConfig, p Payload, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.TimeoutDuration())
+	defer cancel()
+
+	if h.URL != "" {
+		return r.post(ctx, h.URL, data)
+	}
+	return runCommand(ctx, h.Command, p, data)
+}
+
+// runCommand runs a hook command with sh -c, passing the payload on stdin
+// and its fields as ISOLLM_* environment variables
+func runCommand(ctx context.Context, command string, p Payload, data []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(data)
+	// Don't wait on background processes the command left holding output
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(os.Environ(),
+		"ISOLLM_EVENT="+p.Event,
+		"ISOLLM_PROJECT="+p.Project,
+		"ISOLLM_WORKER="+p.Worker,
+		"ISOLLM_TASK_ID="+p.TaskID,
+		"ISOLLM_BRANCH="+p.Branch,
+		"ISOLLM_MESSAGE="+p.Message,
+	)
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("command timed out")
+	}
+	if err != nil {
+		if len(out) > 0 {
+			return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+		}
+		return err
+	}
+	return nil
+}
+
+// post sends the payload to a URL
+func (r *Runner) post(ctx context.Context, url string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "isollm")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to POST %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s returned %s", url, resp.Status)
+	}
+	return nil
+}
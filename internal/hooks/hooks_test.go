@@ -0,0 +1,122 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"isollm/internal/config"
+	"isollm/internal/events"
+)
+
+func TestFire_Command(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "payload.json")
+	cfg := &config.Config{
+		Project: "myproject",
+		Hooks: []config.HookConfig{
+			{On: []string{"task.done"}, Command: `cat > "` + out + `"; echo "$ISOLLM_EVENT $ISOLLM_TASK_ID" >> "` + out + `.env"`},
+		},
+	}
+	r := New(cfg, io.Discard)
+
+	r.Fire(events.Event{Type: events.TaskClaimed, Worker: "worker-1", TaskID: "ar-1"})
+	if _, err := os.Stat(out); err == nil {
+		t.Fatal("hook fired for an event it is not subscribed to")
+	}
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r.Fire(events.Event{Time: at, Type: events.TaskCompleted, Worker: "worker-1", TaskID: "ar-1", Branch: "isollm/ar-1"})
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	var p Payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	want := Payload{Event: "task.done", Time: at, Project: "myproject", Worker: "worker-1", TaskID: "ar-1", Branch: "isollm/ar-1"}
+	if p != want {
+		t.Errorf("payload = %+v, want %+v", p, want)
+	}
+
+	env, _ := os.ReadFile(out + ".env")
+	if got := strings.TrimSpace(string(env)); got != "task.done ar-1" {
+		t.Errorf("env = %q, want ISOLLM_EVENT and ISOLLM_TASK_ID set", got)
+	}
+}
+
+func TestFire_URL(t *testing.T) {
+	var got Payload
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Project: "myproject",
+		Hooks:   []config.HookConfig{{On: []string{"task.blocked", "session.down"}, URL: server.URL}},
+	}
+	var errOut bytes.Buffer
+	New(cfg, &errOut).Fire(events.Event{Type: events.TaskBlocked, TaskID: "ar-2", Message: "needs an API key"})
+
+	if got.Event != "task.blocked" || got.TaskID != "ar-2" || got.Message != "needs an API key" {
+		t.Errorf("posted %+v", got)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("unexpected warning: %s", errOut.String())
+	}
+}
+
+func TestFire_ReportsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Hooks: []config.HookConfig{
+			{On: []string{"worker.error"}, Command: "echo broken >&2; exit 3"},
+			{On: []string{"worker.error"}, URL: server.URL},
+			{On: []string{"worker.error"}, Command: "sleep 5", Timeout: "50ms"},
+		},
+	}
+	var errOut bytes.Buffer
+	New(cfg, &errOut).Fire(events.Event{Type: events.WorkerFailed, Worker: "worker-1"})
+
+	for _, want := range []string{
+		"hooks[0] for worker.error failed: exit status 3: broken",
+		"hooks[1] for worker.error failed: POST " + server.URL + " returned 502",
+		"hooks[2] for worker.error failed: command timed out",
+	} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("warnings missing %q:\n%s", want, errOut.String())
+		}
+	}
+}
+
+func TestAttach(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "fired")
+	cfg := &config.Config{
+		Hooks: []config.HookConfig{{On: []string{"session.down"}, Command: "touch " + out}},
+	}
+	log := events.NewWithDir(t.TempDir())
+	Attach(log, cfg)
+
+	log.Emit(events.Event{Type: events.SessionStopped})
+	if _, err := os.Stat(out); err != nil {
+		t.Error("hook did not fire on Emit")
+	}
+}
@@ -0,0 +1,250 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Ext is the extension of task log files
+	Ext = ".log"
+	// SessionLog is the log name used when Claude runs without a task
+	SessionLog = "session"
+	// ProvisionLog is the log of a worker's provisioning run
+	ProvisionLog = "provision"
+)
+
+// Log is one recorded pane session for a worker
+type Log struct {
+	Worker  string
+	TaskID  string
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Store keeps worker logs as .isollm/logs/<worker>/<task-id>.log.
+// The per-worker directory is mounted into the container, where the pane
+// output is recorded with script(1).
+type Store struct {
+	dir      string
+	maxBytes int64 // Rotate threshold; 0 disables rotation
+}
+
+// New creates a Store for the project's .isollm directory
+func New(projectRoot string) *Store {
+	return &Store{dir: filepath.Join(projectRoot, ".isollm", "logs")}
+}
+
+// NewWithDir creates a Store with a custom directory (for testing)
+func NewWithDir(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// SetMaxSize sets the size past which a task log is rotated when its task
+// is launched again
+func (s *Store) SetMaxSize(bytes int64) {
+	s.maxBytes = bytes
+}
+
+// WorkerDir returns the directory holding a worker's logs
+func (s *Store) WorkerDir(worker string) string {
+	return filepath.Join(s.dir, worker)
+}
+
+// Path returns the log file for a worker's task
+func (s *Store) Path(worker, taskID string) string {
+	return filepath.Join(s.WorkerDir(worker), taskID+Ext)
+}
+
+// Prepare readies a task log before Claude is launched: the worker
+// directory is created, an oversized log is rotated to <task-id>.log.1 and
+// a header marking the start of the session is appended.
+func (s *Store) Prepare(worker, taskID string) error {
+	if err := os.MkdirAll(s.WorkerDir(worker), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory for %s: %w", worker, err)
+	}
+
+	path := s.Path(worker, taskID)
+	if info, err := os.Stat(path); err == nil && s.maxBytes > 0 && info.Size() > s.maxBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate log %s: %w", path, err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	header := fmt.Sprintf("\n── isollm: %s on %s started %s ──\n", taskID, worker, time.Now().Format(time.RFC3339))
+	if _, err := f.WriteString(header); err != nil {
+		return fmt.Errorf("failed to write log %s: %w", path, err)
+	}
+	return nil
+}
+
+// List returns a worker's logs, least recently written first
+func (s *Store) List(worker string) ([]Log, error) {
+	entries, err := os.ReadDir(s.WorkerDir(worker))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read logs for %s: %w", worker, err)
+	}
+
+	var logs []Log
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), Ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, Log{
+			Worker:  worker,
+			TaskID:  strings.TrimSuffix(e.Name(), Ext),
+			Path:    filepath.Join(s.WorkerDir(worker), e.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].ModTime.Before(logs[j].ModTime)
+	})
+	return logs, nil
+}
+
+// Latest returns a worker's most recently written log, or nil
+func (s *Store) Latest(worker string) (*Log, error) {
+	logs, err := s.List(worker)
+	if err != nil || len(logs) == 0 {
+		return nil, err
+	}
+	return &logs[len(logs)-1], nil
+}
+
+// Prune deletes logs, including rotated ones, that have not been written
+// within the retention period. It returns the number of files removed.
+func (s *Store) Prune(retention time.Duration) (int, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+
+	workers, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read logs directory: %w", err)
+	}
+
+	cutoff := time.Now().Add(-retention)
+	removed := 0
+	for _, w := range workers {
+		if !w.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.dir, w.Name())
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return removed, fmt.Errorf("failed to read logs for %s: %w", w.Name(), err)
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.Contains(e.Name(), Ext) {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil || info.ModTime().After(cutoff) {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+				return removed, fmt.Errorf("failed to remove log %s: %w", e.Name(), err)
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// ansiPattern matches CSI and OSC terminal escape sequences
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// Clean strips terminal escape sequences and carriage returns from raw pane
+// output so it reads as plain text
+func Clean(data []byte) []byte {
+	data = ansiPattern.ReplaceAll(data, nil)
+	data = []byte(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	return []byte(strings.ReplaceAll(string(data), "\r", "\n"))
+}
+
+// Tail returns the last n lines of a log, cleaned. n <= 0 returns the
+// whole log.
+func Tail(path string, n int) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read log %s: %w", path, err)
+	}
+
+	text := strings.TrimRight(string(Clean(data)), "\n")
+	if n <= 0 {
+		return text, nil
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Follow streams whatever is appended to a log after offset until ctx is
+// cancelled, polling every interval
+func Follow(ctx context.Context, path string, offset int64, w io.Writer, interval time.Duration) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek log %s: %w", path, err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	buf := make([]byte, 32*1024)
+	for {
+		for {
+			n, err := f.Read(buf)
+			if n > 0 {
+				if _, werr := w.Write(Clean(buf[:n])); werr != nil {
+					return werr
+				}
+			}
+			if err == io.EOF || n == 0 {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read log %s: %w", path, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
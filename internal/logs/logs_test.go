@@ -0,0 +1,154 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrepare_CreatesLogWithHeader(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+
+	if err := s.Prepare("worker-1", "ar-0001"); err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+
+	data, err := os.ReadFile(s.Path("worker-1", "ar-0001"))
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if !strings.Contains(string(data), "isollm: ar-0001 on worker-1 started") {
+		t.Errorf("log = %q, want a session header", data)
+	}
+}
+
+func TestPrepare_RotatesOversizedLog(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+	path := s.Path("worker-1", "ar-0001")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, bytes.Repeat([]byte("x"), 100), 0644)
+
+	s.SetMaxSize(50)
+	if err := s.Prepare("worker-1", "ar-0001"); err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+
+	rotated, err := os.ReadFile(path + ".1")
+	if err != nil {
+		t.Fatalf("rotated log missing: %v", err)
+	}
+	if len(rotated) != 100 {
+		t.Errorf("rotated log has %d bytes, want 100", len(rotated))
+	}
+	if data, _ := os.ReadFile(path); bytes.Contains(data, []byte("xxx")) {
+		t.Error("new log still holds the old output")
+	}
+}
+
+func TestListAndLatest(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+	s.Prepare("worker-1", "ar-0001")
+	s.Prepare("worker-1", "ar-0002")
+
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(s.Path("worker-1", "ar-0002"), old, old)
+
+	logs, err := s.List("worker-1")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(logs) != 2 || logs[0].TaskID != "ar-0002" {
+		t.Fatalf("List() = %+v, want ar-0002 first", logs)
+	}
+
+	latest, err := s.Latest("worker-1")
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest == nil || latest.TaskID != "ar-0001" {
+		t.Errorf("Latest() = %+v, want ar-0001", latest)
+	}
+
+	if none, _ := s.Latest("worker-9"); none != nil {
+		t.Errorf("Latest() for unknown worker = %+v, want nil", none)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+	s.Prepare("worker-1", "ar-0001")
+	s.Prepare("worker-2", "ar-0002")
+	rotated := s.Path("worker-2", "ar-0002") + ".1"
+	os.WriteFile(rotated, []byte("old"), 0644)
+
+	old := time.Now().Add(-48 * time.Hour)
+	os.Chtimes(s.Path("worker-2", "ar-0002"), old, old)
+	os.Chtimes(rotated, old, old)
+
+	removed, err := s.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Prune() removed %d, want 2", removed)
+	}
+	if _, err := os.Stat(s.Path("worker-1", "ar-0001")); err != nil {
+		t.Error("Prune() removed a recent log")
+	}
+}
+
+func TestClean(t *testing.T) {
+	raw := []byte("\x1b[1;32mok\x1b[0m done\r\n\x1b]0;title\x07next\rline")
+	got := string(Clean(raw))
+	want := "ok done\nnext\nline"
+	if got != want {
+		t.Errorf("Clean() = %q, want %q", got, want)
+	}
+}
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+	os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0644)
+
+	got, err := Tail(path, 2)
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	if got != "two\nthree" {
+		t.Errorf("Tail() = %q, want last two lines", got)
+	}
+
+	all, _ := Tail(path, 0)
+	if all != "one\ntwo\nthree" {
+		t.Errorf("Tail(0) = %q, want whole log", all)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+	os.WriteFile(path, []byte("before\n"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out bytes.Buffer
+	done := make(chan error)
+	go func() {
+		done <- Follow(ctx, path, int64(len("before\n")), &out, 10*time.Millisecond)
+	}()
+
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("after\n")
+	f.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Follow() error = %v", err)
+	}
+	if out.String() != "after\n" {
+		t.Errorf("Follow() wrote %q, want only appended output", out.String())
+	}
+}
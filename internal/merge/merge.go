@@ -0,0 +1,312 @@
+package merge
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"isollm/internal/git"
+)
+
+// Strategy controls how a task branch is landed on the base branch
+type Strategy string
+
+const (
+	StrategyMerge  Strategy = "merge"  // One merge commit per task
+	StrategyRebase Strategy = "rebase" // Replay the task's commits on top of base
+	StrategySquash Strategy = "squash" // One plain commit per task
+)
+
+// ParseStrategy validates a strategy name
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(s) {
+	case StrategyMerge, StrategyRebase, StrategySquash:
+		return Strategy(s), nil
+	case "":
+		return StrategyMerge, nil
+	}
+	return "", fmt.Errorf("unknown merge strategy %q (use merge, rebase or squash)", s)
+}
+
+// Outcome is what happened to a task branch
+type Outcome string
+
+const (
+	OutcomeMerged       Outcome = "merged"        // Landed on the base branch
+	OutcomeClean        Outcome = "clean"         // Dry run: would land cleanly
+	OutcomeConflict     Outcome = "conflict"      // Does not apply to the base branch
+	OutcomeVerifyFailed Outcome = "verify failed" // Verification command failed
+	OutcomeFailed       Outcome = "failed"        // Any other error
+)
+
+// Task is a task branch to land
+type Task struct {
+	ID     string
+	Title  string
+	Branch string
+}
+
+// Result reports the outcome for one task
+type Result struct {
+	Task      Task
+	Outcome   Outcome
+	Commit    string   // Base branch head after landing (or would-be head in a dry run)
+	Conflicts []string // Conflicting paths
+	Output    string   // Verification output
+	Err       error
+}
+
+// Options configures a merge run
+type Options struct {
+	Strategy Strategy
+	Verify   string // Shell command run in the scratch worktree before the base branch moves
+	DryRun   bool
+}
+
+// Repo is the subset of barerepo.BareRepo used by the merger
+type Repo interface {
+	Path() string
+	DeleteBranch(branchName string) error
+	PushToBare(projectPath, branch string) error
+}
+
+// Merger lands task branches from the bare repo onto the host's base branch.
+// All merging happens in a scratch worktree so the host checkout is only
+// touched by a final fast-forward.
+type Merger struct {
+	projectDir string
+	baseBranch string
+	repo       Repo
+	opts       Options
+	executor   git.Executor
+	verify     func(dir, command string) (string, error)
+}
+
+// New creates a Merger for the host repo at projectDir
+func New(projectDir, baseBranch string, repo Repo, opts Options) *Merger {
+	if opts.Strategy == "" {
+		opts.Strategy = StrategyMerge
+	}
+	return &Merger{
+		projectDir: projectDir,
+		baseBranch: baseBranch,
+		repo:       repo,
+		opts:       opts,
+		executor:   git.DefaultExecutor,
+		verify:     runShell,
+	}
+}
+
+// Run lands each task in order. Every successful task becomes the base for
+// the next one. On success the new base branch is pushed to the bare repo so
+// workers start from it. A dry run applies the tasks on top of each other
+// in the same way, only in the scratch worktree, so it reports the
+// conflicts a real run would hit.
+func (m *Merger) Run(tasks []Task) ([]Result, error) {
+	base, err := m.executor.Run(m.projectDir, "rev-parse", "--verify", "refs/heads/"+m.baseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("base branch %s not found in host repo: %w", m.baseBranch, err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "isollm-merge-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	worktree := filepath.Join(tmpDir, "worktree")
+	if err := m.executor.RunSilent(m.projectDir, "worktree", "add", "--detach", worktree, base); err != nil {
+		return nil, fmt.Errorf("failed to create scratch worktree: %w", err)
+	}
+	defer func() {
+		m.executor.RunSilent(m.projectDir, "worktree", "remove", "--force", worktree)
+		m.executor.RunSilent(m.projectDir, "worktree", "prune")
+	}()
+
+	var results []Result
+	merged := false
+	for _, task := range tasks {
+		r := m.land(worktree, base, task)
+		switch r.Outcome {
+		case OutcomeMerged:
+			base = r.Commit
+			merged = true
+		case OutcomeClean:
+			base = r.Commit // Only in the scratch worktree
+		}
+		results = append(results, r)
+	}
+
+	if merged {
+		if err := m.repo.PushToBare(m.projectDir, m.baseBranch); err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// land applies one task branch on top of base in the worktree, verifies it
+// and fast-forwards the host base branch
+func (m *Merger) land(worktree, base string, task Task) Result {
+	r := Result{Task: task}
+
+	if err := m.reset(worktree, base); err != nil {
+		r.Outcome, r.Err = OutcomeFailed, err
+		return r
+	}
+
+	if err := m.executor.RunSilent(worktree, "fetch", m.repo.Path(), "refs/heads/"+task.Branch); err != nil {
+		r.Outcome, r.Err = OutcomeFailed, fmt.Errorf("branch %s not found in bare repo: %w", task.Branch, err)
+		return r
+	}
+	tip, err := m.executor.Run(worktree, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		r.Outcome, r.Err = OutcomeFailed, fmt.Errorf("failed to resolve %s: %w", task.Branch, err)
+		return r
+	}
+
+	head := base
+	if m.executor.RunSilent(worktree, "merge-base", "--is-ancestor", tip, base) != nil {
+		head, r.Conflicts, err = m.apply(worktree, base, tip, task)
+		if err != nil {
+			r.Outcome, r.Err = OutcomeFailed, err
+			if len(r.Conflicts) > 0 {
+				r.Outcome = OutcomeConflict
+			}
+			return r
+		}
+	}
+	r.Commit = head
+
+	if m.opts.DryRun {
+		r.Outcome = OutcomeClean
+		return r
+	}
+
+	if m.opts.Verify != "" && head != base {
+		if out, err := m.verify(worktree, m.opts.Verify); err != nil {
+			r.Outcome, r.Output, r.Err = OutcomeVerifyFailed, out, fmt.Errorf("verification failed: %w", err)
+			return r
+		}
+	}
+
+	if err := m.advanceBase(base, head); err != nil {
+		r.Outcome, r.Err = OutcomeFailed, err
+		return r
+	}
+	r.Outcome = OutcomeMerged
+
+	// The branch has landed; a failure to clean it up is reported but does
+	// not undo the merge
+	if err := m.repo.DeleteBranch(task.Branch); err != nil {
+		r.Err = err
+	}
+	m.executor.RunSilent(m.projectDir, "update-ref", "-d", "refs/remotes/"+task.Branch)
+
+	return r
+}
+
+// apply lands tip on base with the configured strategy and returns the new
+// head. On conflict the worktree is cleaned up and the conflicting paths
+// are returned along with the error.
+func (m *Merger) apply(worktree, base, tip string, task Task) (string, []string, error) {
+	switch m.opts.Strategy {
+	case StrategyRebase:
+		if err := m.executor.RunSilent(worktree, "checkout", "--detach", tip); err != nil {
+			return "", nil, fmt.Errorf("failed to check out %s: %w", task.Branch, err)
+		}
+		if err := m.executor.RunSilent(worktree, "rebase", base); err != nil {
+			conflicts := m.conflicts(worktree)
+			m.executor.RunSilent(worktree, "rebase", "--abort")
+			return "", conflicts, fmt.Errorf("failed to rebase %s onto %s: %w", task.Branch, m.baseBranch, err)
+		}
+
+	case StrategySquash:
+		if err := m.executor.RunSilent(worktree, "merge", "--squash", tip); err != nil {
+			conflicts := m.conflicts(worktree)
+			m.reset(worktree, base)
+			return "", conflicts, fmt.Errorf("failed to squash %s onto %s: %w", task.Branch, m.baseBranch, err)
+		}
+		if m.executor.RunSilent(worktree, "diff", "--cached", "--quiet") == nil {
+			return base, nil, nil // Nothing new on the branch
+		}
+		msg := fmt.Sprintf("%s\n\nTask: %s", title(task), task.ID)
+		if err := m.executor.RunSilent(worktree, "commit", "-m", msg); err != nil {
+			return "", nil, fmt.Errorf("failed to commit squashed %s: %w", task.Branch, err)
+		}
+
+	default:
+		msg := fmt.Sprintf("Merge %s: %s", task.Branch, title(task))
+		if err := m.executor.RunSilent(worktree, "merge", "--no-ff", "-m", msg, tip); err != nil {
+			conflicts := m.conflicts(worktree)
+			m.executor.RunSilent(worktree, "merge", "--abort")
+			return "", conflicts, fmt.Errorf("failed to merge %s into %s: %w", task.Branch, m.baseBranch, err)
+		}
+	}
+
+	head, err := m.executor.Run(worktree, "rev-parse", "HEAD")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read merge result: %w", err)
+	}
+	return head, nil, nil
+}
+
+// advanceBase fast-forwards the host base branch from old to new. When the
+// base branch is checked out, the working tree is updated too.
+func (m *Merger) advanceBase(old, new string) error {
+	if old == new {
+		return nil
+	}
+
+	current, _ := m.executor.Run(m.projectDir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if current == m.baseBranch {
+		if err := m.executor.RunSilent(m.projectDir, "merge", "--ff-only", new); err != nil {
+			return fmt.Errorf("failed to fast-forward %s (commit or stash local changes first): %w", m.baseBranch, err)
+		}
+		return nil
+	}
+
+	if err := m.executor.RunSilent(m.projectDir, "update-ref", "refs/heads/"+m.baseBranch, new, old); err != nil {
+		return fmt.Errorf("failed to fast-forward %s: %w", m.baseBranch, err)
+	}
+	return nil
+}
+
+// reset puts the worktree back on base with no local changes
+func (m *Merger) reset(worktree, base string) error {
+	if err := m.executor.RunSilent(worktree, "reset", "--hard", base); err != nil {
+		return fmt.Errorf("failed to reset scratch worktree: %w", err)
+	}
+	if err := m.executor.RunSilent(worktree, "clean", "-fd"); err != nil {
+		return fmt.Errorf("failed to clean scratch worktree: %w", err)
+	}
+	return nil
+}
+
+// conflicts returns the unmerged paths in the worktree
+func (m *Merger) conflicts(worktree string) []string {
+	output, err := m.executor.Run(worktree, "diff", "--name-only", "--diff-filter=U")
+	if err != nil || output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
+
+// title returns the commit title for a task
+func title(task Task) string {
+	if task.Title != "" {
+		return task.Title
+	}
+	return task.Branch
+}
+
+// runShell runs a shell command in dir and returns its combined output
+func runShell(dir, command string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
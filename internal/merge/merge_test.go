@@ -0,0 +1,326 @@
+package merge
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"isollm/internal/barerepo"
+)
+
+// fixture is a host repo, its bare hub and a worker clone
+type fixture struct {
+	host   string
+	worker string
+	repo   *barerepo.BareRepo
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	tmpDir := t.TempDir()
+	f := &fixture{
+		host:   filepath.Join(tmpDir, "host"),
+		worker: filepath.Join(tmpDir, "worker"),
+	}
+
+	os.MkdirAll(f.host, 0755)
+	run(t, f.host, "init", "-b", "main")
+	configure(t, f.host)
+	writeFile(t, f.host, "README.md", "# Test\n")
+	run(t, f.host, "add", ".")
+	run(t, f.host, "commit", "-m", "initial")
+
+	repo, err := barerepo.Create(f.host, filepath.Join(tmpDir, "host.git"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	f.repo = repo
+
+	run(t, tmpDir, "clone", repo.Path(), f.worker)
+	configure(t, f.worker)
+	return f
+}
+
+// taskBranch pushes a branch from the worker with one file change
+func (f *fixture) taskBranch(t *testing.T, branch, file, content string) {
+	t.Helper()
+	run(t, f.worker, "checkout", "-B", branch, "origin/main")
+	writeFile(t, f.worker, file, content)
+	run(t, f.worker, "add", ".")
+	run(t, f.worker, "commit", "-m", "work on "+branch)
+	run(t, f.worker, "push", "-f", "origin", branch)
+}
+
+func (f *fixture) merger(opts Options) *Merger {
+	return New(f.host, "main", f.repo, opts)
+}
+
+func run(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func configure(t *testing.T, dir string) {
+	t.Helper()
+	run(t, dir, "config", "user.email", "test@test.com")
+	run(t, dir, "config", "user.name", "Test")
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func branchExists(f *fixture, branch string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "refs/heads/"+branch)
+	cmd.Dir = f.repo.Path()
+	return cmd.Run() == nil
+}
+
+func TestParseStrategy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Strategy
+		wantErr bool
+	}{
+		{"", StrategyMerge, false},
+		{"merge", StrategyMerge, false},
+		{"rebase", StrategyRebase, false},
+		{"squash", StrategySquash, false},
+		{"octopus", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStrategy(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStrategy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseStrategy(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRun_Strategies(t *testing.T) {
+	tests := []struct {
+		strategy    Strategy
+		wantParents int    // Parents of the new head
+		wantSubject string // Subject of the new head
+	}{
+		{StrategyMerge, 2, "Merge isollm/ar-0001: Add a"},
+		{StrategyRebase, 1, "work on isollm/ar-0001"},
+		{StrategySquash, 1, "Add a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			f := newFixture(t)
+			f.taskBranch(t, "isollm/ar-0001", "a.txt", "a\n")
+
+			results, err := f.merger(Options{Strategy: tt.strategy}).Run([]Task{
+				{ID: "ar-0001", Title: "Add a", Branch: "isollm/ar-0001"},
+			})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if len(results) != 1 || results[0].Outcome != OutcomeMerged {
+				t.Fatalf("results = %+v, want merged", results)
+			}
+
+			head := run(t, f.host, "rev-parse", "main")
+			if head != results[0].Commit {
+				t.Errorf("main = %s, want %s", head, results[0].Commit)
+			}
+			if _, err := os.Stat(filepath.Join(f.host, "a.txt")); err != nil {
+				t.Errorf("host working tree not updated: %v", err)
+			}
+
+			parents := strings.Fields(run(t, f.host, "log", "-1", "--format=%P", "main"))
+			if len(parents) != tt.wantParents {
+				t.Errorf("head has %d parents, want %d", len(parents), tt.wantParents)
+			}
+			if subject := run(t, f.host, "log", "-1", "--format=%s", "main"); subject != tt.wantSubject {
+				t.Errorf("head subject = %q, want %q", subject, tt.wantSubject)
+			}
+
+			if branchExists(f, "isollm/ar-0001") {
+				t.Error("task branch still exists in bare repo")
+			}
+			if bareMain := run(t, f.repo.Path(), "rev-parse", "main"); bareMain != head {
+				t.Errorf("bare main = %s, want %s", bareMain, head)
+			}
+		})
+	}
+}
+
+func TestRun_SequentialTasksBuildOnEachOther(t *testing.T) {
+	f := newFixture(t)
+	f.taskBranch(t, "isollm/ar-0001", "a.txt", "a\n")
+	f.taskBranch(t, "isollm/ar-0002", "b.txt", "b\n")
+
+	results, err := f.merger(Options{Strategy: StrategyRebase}).Run([]Task{
+		{ID: "ar-0001", Branch: "isollm/ar-0001"},
+		{ID: "ar-0002", Branch: "isollm/ar-0002"},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, r := range results {
+		if r.Outcome != OutcomeMerged {
+			t.Errorf("%s outcome = %s (%v), want merged", r.Task.ID, r.Outcome, r.Err)
+		}
+	}
+
+	for _, file := range []string{"a.txt", "b.txt"} {
+		if _, err := os.Stat(filepath.Join(f.host, file)); err != nil {
+			t.Errorf("%s missing from host: %v", file, err)
+		}
+	}
+}
+
+func TestRun_ReportsConflicts(t *testing.T) {
+	f := newFixture(t)
+	f.taskBranch(t, "isollm/ar-0001", "README.md", "# From task 1\n")
+	f.taskBranch(t, "isollm/ar-0002", "README.md", "# From task 2\n")
+
+	results, err := f.merger(Options{}).Run([]Task{
+		{ID: "ar-0001", Branch: "isollm/ar-0001"},
+		{ID: "ar-0002", Branch: "isollm/ar-0002"},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if results[0].Outcome != OutcomeMerged {
+		t.Errorf("first outcome = %s, want merged", results[0].Outcome)
+	}
+	if results[1].Outcome != OutcomeConflict {
+		t.Fatalf("second outcome = %s (%v), want conflict", results[1].Outcome, results[1].Err)
+	}
+	if len(results[1].Conflicts) != 1 || results[1].Conflicts[0] != "README.md" {
+		t.Errorf("conflicts = %v, want [README.md]", results[1].Conflicts)
+	}
+	if !branchExists(f, "isollm/ar-0002") {
+		t.Error("conflicting branch was deleted")
+	}
+}
+
+func TestRun_VerifyFailureLeavesBaseAlone(t *testing.T) {
+	f := newFixture(t)
+	f.taskBranch(t, "isollm/ar-0001", "a.txt", "a\n")
+	before := run(t, f.host, "rev-parse", "main")
+
+	m := f.merger(Options{Verify: "make test"})
+	m.verify = func(dir, command string) (string, error) {
+		if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+			t.Errorf("verify ran without the task's changes: %v", err)
+		}
+		return "FAIL: TestA\n", errors.New("exit status 1")
+	}
+
+	results, err := m.Run([]Task{{ID: "ar-0001", Branch: "isollm/ar-0001"}})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if results[0].Outcome != OutcomeVerifyFailed {
+		t.Fatalf("outcome = %s, want verify failed", results[0].Outcome)
+	}
+	if !strings.Contains(results[0].Output, "FAIL: TestA") {
+		t.Errorf("output = %q, want verification output", results[0].Output)
+	}
+	if after := run(t, f.host, "rev-parse", "main"); after != before {
+		t.Error("main moved despite failed verification")
+	}
+	if !branchExists(f, "isollm/ar-0001") {
+		t.Error("branch deleted despite failed verification")
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	f := newFixture(t)
+	f.taskBranch(t, "isollm/ar-0001", "a.txt", "a\n")
+	before := run(t, f.host, "rev-parse", "main")
+
+	results, err := f.merger(Options{DryRun: true}).Run([]Task{
+		{ID: "ar-0001", Branch: "isollm/ar-0001"},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if results[0].Outcome != OutcomeClean {
+		t.Errorf("outcome = %s, want clean", results[0].Outcome)
+	}
+	if after := run(t, f.host, "rev-parse", "main"); after != before {
+		t.Error("dry run moved main")
+	}
+	if !branchExists(f, "isollm/ar-0001") {
+		t.Error("dry run deleted the branch")
+	}
+}
+
+func TestRun_DryRunBuildsOnEarlierTasks(t *testing.T) {
+	f := newFixture(t)
+	f.taskBranch(t, "isollm/ar-0001", "README.md", "# From task 1\n")
+	f.taskBranch(t, "isollm/ar-0002", "README.md", "# From task 2\n")
+	before := run(t, f.host, "rev-parse", "main")
+
+	results, err := f.merger(Options{DryRun: true}).Run([]Task{
+		{ID: "ar-0001", Branch: "isollm/ar-0001"},
+		{ID: "ar-0002", Branch: "isollm/ar-0002"},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if results[0].Outcome != OutcomeClean {
+		t.Errorf("first outcome = %s (%v), want clean", results[0].Outcome, results[0].Err)
+	}
+	if results[1].Outcome != OutcomeConflict {
+		t.Errorf("second outcome = %s (%v), want the conflict with the first", results[1].Outcome, results[1].Err)
+	}
+	if after := run(t, f.host, "rev-parse", "main"); after != before {
+		t.Error("dry run moved main")
+	}
+}
+
+func TestRun_MissingBranch(t *testing.T) {
+	f := newFixture(t)
+
+	results, err := f.merger(Options{}).Run([]Task{{ID: "ar-none", Branch: "isollm/ar-none"}})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if results[0].Outcome != OutcomeFailed || results[0].Err == nil {
+		t.Errorf("result = %+v, want failure", results[0])
+	}
+}
+
+func TestRun_BaseNotCheckedOut(t *testing.T) {
+	f := newFixture(t)
+	f.taskBranch(t, "isollm/ar-0001", "a.txt", "a\n")
+	run(t, f.host, "checkout", "-b", "feature")
+
+	results, err := f.merger(Options{}).Run([]Task{{ID: "ar-0001", Branch: "isollm/ar-0001"}})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if results[0].Outcome != OutcomeMerged {
+		t.Fatalf("outcome = %s (%v), want merged", results[0].Outcome, results[0].Err)
+	}
+	if head := run(t, f.host, "rev-parse", "main"); head != results[0].Commit {
+		t.Errorf("main = %s, want %s", head, results[0].Commit)
+	}
+	if current := run(t, f.host, "symbolic-ref", "--short", "HEAD"); current != "feature" {
+		t.Errorf("host switched to %s, want feature", current)
+	}
+}
@@ -0,0 +1,127 @@
+package notes
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Kind classifies a note
+type Kind string
+
+const (
+	KindWatchdog Kind = "watchdog" // Task reclaimed from a dead or silent worker
+	KindVerify   Kind = "verify"   // Verification output for a task that failed its checks
+	KindBlocker  Kind = "blocker"  // Why a task is blocked, or a question for a human
+	KindAnswer   Kind = "answer"   // A human's answer to a blocker
+)
+
+// Note is a piece of text attached to a task.
+// Airyra has no notion of comments, so isollm keeps notes locally.
+type Note struct {
+	Time   time.Time `json:"time"`
+	Kind   Kind      `json:"kind"`
+	Author string    `json:"author,omitempty"`
+	Text   string    `json:"text"`
+}
+
+// Store keeps notes as one JSONL file per task
+type Store struct {
+	dir string
+}
+
+// New creates a Store for the project's .isollm directory
+func New(projectRoot string) *Store {
+	return &Store{dir: filepath.Join(projectRoot, ".isollm", "notes")}
+}
+
+// NewWithDir creates a Store with a custom directory (for testing)
+func NewWithDir(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Add appends a note to a task
+func (s *Store) Add(taskID string, n Note) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to marshal note: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path(taskID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open notes for %s: %w", taskID, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write note for %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// List returns all notes for a task, oldest first
+func (s *Store) List(taskID string) ([]Note, error) {
+	f, err := os.Open(s.path(taskID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open notes for %s: %w", taskID, err)
+	}
+	defer f.Close()
+
+	var result []Note
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var n Note
+		if err := json.Unmarshal(scanner.Bytes(), &n); err != nil {
+			continue // Skip partially written lines
+		}
+		result = append(result, n)
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("failed to read notes for %s: %w", taskID, err)
+	}
+
+	return result, nil
+}
+
+// OpenQuestion returns the last blocker or verify note that has not been
+// answered since, or nil
+func OpenQuestion(list []Note) *Note {
+	for i := len(list) - 1; i >= 0; i-- {
+		switch list[i].Kind {
+		case KindAnswer:
+			return nil
+		case KindBlocker, KindVerify:
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// Delete removes all notes for a task
+func (s *Store) Delete(taskID string) error {
+	err := os.Remove(s.path(taskID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// path returns the notes file for a task
+func (s *Store) path(taskID string) string {
+	return filepath.Join(s.dir, taskID+".jsonl")
+}
@@ -0,0 +1,121 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_AddAndList(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+
+	if err := s.Add("ar-0001", Note{Kind: KindWatchdog, Text: "first"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := s.Add("ar-0001", Note{Kind: KindWatchdog, Text: "second", Author: "isollm"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	got, err := s.List("ar-0001")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("List() returned %d notes, want 2", len(got))
+	}
+	if got[0].Text != "first" || got[1].Text != "second" {
+		t.Errorf("List() order = [%q %q], want [first second]", got[0].Text, got[1].Text)
+	}
+	if got[0].Time.IsZero() {
+		t.Error("Add() did not set Time")
+	}
+	if got[1].Author != "isollm" {
+		t.Errorf("Author = %q, want isollm", got[1].Author)
+	}
+}
+
+func TestStore_AddKeepsExplicitTime(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	s.Add("ar-0001", Note{Time: when, Text: "x"})
+
+	got, _ := s.List("ar-0001")
+	if len(got) != 1 || !got[0].Time.Equal(when) {
+		t.Errorf("List() = %+v, want time %v", got, when)
+	}
+}
+
+func TestStore_ListMissing(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+
+	got, err := s.List("ar-none")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("List() = %v, want nil", got)
+	}
+}
+
+func TestStore_ListSkipsCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+	s := NewWithDir(dir)
+
+	s.Add("ar-0001", Note{Text: "good"})
+	f, _ := os.OpenFile(filepath.Join(dir, "ar-0001.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("{not json\n")
+	f.Close()
+
+	got, err := s.List("ar-0001")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Text != "good" {
+		t.Errorf("List() = %+v, want only the good note", got)
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+	s.Add("ar-0001", Note{Text: "x"})
+
+	if err := s.Delete("ar-0001"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got, _ := s.List("ar-0001"); len(got) != 0 {
+		t.Errorf("List() after Delete = %v, want empty", got)
+	}
+
+	// Deleting again is not an error
+	if err := s.Delete("ar-0001"); err != nil {
+		t.Errorf("Delete() of missing notes error = %v", err)
+	}
+}
+
+func TestOpenQuestion(t *testing.T) {
+	tests := []struct {
+		name  string
+		notes []Note
+		want  string
+	}{
+		{"no notes", nil, ""},
+		{"watchdog only", []Note{{Kind: KindWatchdog, Text: "reclaimed"}}, ""},
+		{"blocker", []Note{{Kind: KindBlocker, Text: "which API?"}, {Kind: KindWatchdog, Text: "reclaimed"}}, "which API?"},
+		{"answered", []Note{{Kind: KindBlocker, Text: "which API?"}, {Kind: KindAnswer, Text: "v2"}}, ""},
+		{"asked again", []Note{{Kind: KindBlocker, Text: "which API?"}, {Kind: KindAnswer, Text: "v2"}, {Kind: KindVerify, Text: "tests failed"}}, "tests failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OpenQuestion(tt.notes)
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("OpenQuestion() = %+v, want nil", got)
+			case tt.want != "" && (got == nil || got.Text != tt.want):
+				t.Errorf("OpenQuestion() = %+v, want %q", got, tt.want)
+			}
+		})
+	}
+}
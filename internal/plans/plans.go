@@ -0,0 +1,123 @@
+package plans
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"isollm/internal/state"
+)
+
+// State is how far a planning task has got
+type State string
+
+const (
+	StatePending State = "pending" // Waiting for a planner worker to split it
+	StateSplit   State = "split"   // Split into subtasks, done when they are
+)
+
+// Plan marks a task to be split into subtasks by a planner worker rather
+// than worked on directly
+type Plan struct {
+	TaskID   string    `json:"task_id"`
+	State    State     `json:"state"`
+	Children []string  `json:"children,omitempty"` // Subtask IDs once split
+	Worker   string    `json:"worker,omitempty"`   // Planner that split it
+	Created  time.Time `json:"created"`
+	SplitAt  time.Time `json:"split_at,omitempty"`
+}
+
+// Store keeps plans as one JSON file per task
+type Store struct {
+	dir string
+}
+
+// New creates a Store for the project's .isollm directory
+func New(projectRoot string) *Store {
+	return &Store{dir: filepath.Join(projectRoot, ".isollm", "plans")}
+}
+
+// NewWithDir creates a Store with a custom directory (for testing)
+func NewWithDir(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Mark flags a task for planning
+func (s *Store) Mark(taskID string) error {
+	return s.Save(&Plan{TaskID: taskID, State: StatePending, Created: time.Now()})
+}
+
+// Save writes a plan
+func (s *Store) Save(p *Plan) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %w", err)
+	}
+
+	if err := state.WriteFile(s.path(p.TaskID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write plan for %s: %w", p.TaskID, err)
+	}
+	return nil
+}
+
+// Get returns the plan for a task, or nil if the task is not a planning
+// task
+func (s *Store) Get(taskID string) (*Plan, error) {
+	data, err := os.ReadFile(s.path(taskID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read plan for %s: %w", taskID, err)
+	}
+
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid plan for %s: %w", taskID, err)
+	}
+	return &p, nil
+}
+
+// List returns every plan, ordered by task ID
+func (s *Store) List() ([]*Plan, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read plans directory: %w", err)
+	}
+
+	var list []*Plan
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		p, err := s.Get(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].TaskID < list[j].TaskID })
+	return list, nil
+}
+
+// Delete removes the plan for a task
+func (s *Store) Delete(taskID string) error {
+	err := os.Remove(s.path(taskID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// path returns the plan file for a task
+func (s *Store) path(taskID string) string {
+	return filepath.Join(s.dir, taskID+".json")
+}
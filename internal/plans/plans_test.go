@@ -0,0 +1,60 @@
+package plans
+
+import (
+	"testing"
+)
+
+func TestStore_MarkAndGet(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+
+	if p, err := s.Get("ar-0001"); err != nil || p != nil {
+		t.Fatalf("Get() before Mark = %+v, %v; want nil, nil", p, err)
+	}
+
+	if err := s.Mark("ar-0001"); err != nil {
+		t.Fatalf("Mark() error = %v", err)
+	}
+
+	p, err := s.Get("ar-0001")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if p == nil || p.State != StatePending || p.Created.IsZero() {
+		t.Errorf("Get() = %+v, want a pending plan", p)
+	}
+}
+
+func TestStore_SaveListDelete(t *testing.T) {
+	s := NewWithDir(t.TempDir())
+
+	s.Mark("ar-0002")
+	s.Save(&Plan{TaskID: "ar-0001", State: StateSplit, Children: []string{"ar-0003", "ar-0004"}})
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 2 || list[0].TaskID != "ar-0001" || list[1].TaskID != "ar-0002" {
+		t.Fatalf("List() = %+v, want ar-0001 then ar-0002", list)
+	}
+	if len(list[0].Children) != 2 {
+		t.Errorf("Children = %v, want 2", list[0].Children)
+	}
+
+	if err := s.Delete("ar-0001"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete("ar-0001"); err != nil {
+		t.Errorf("Delete() twice error = %v", err)
+	}
+	if list, _ := s.List(); len(list) != 1 {
+		t.Errorf("List() after delete = %+v, want 1 plan", list)
+	}
+}
+
+func TestStore_ListEmpty(t *testing.T) {
+	s := NewWithDir(t.TempDir() + "/missing")
+	if list, err := s.List(); err != nil || len(list) != 0 {
+		t.Errorf("List() = %+v, %v; want empty", list, err)
+	}
+}
@@ -0,0 +1,257 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is an output format for a report
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+)
+
+// ParseFormat validates a format name
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatMarkdown, FormatHTML, FormatJSON:
+		return Format(s), nil
+	case "md", "":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown report format %q (use markdown, html or json)", s)
+}
+
+// Write renders a report in a format
+func Write(w io.Writer, r *Report, f Format) error {
+	switch f {
+	case FormatHTML:
+		return HTML(w, r)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	default:
+		return Markdown(w, r)
+	}
+}
+
+// Markdown renders a report as markdown: a summary, a table of tasks and a
+// section per task with its branch and history
+func Markdown(w io.Writer, r *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# isollm report: %s\n\n", r.Project)
+	fmt.Fprintf(&b, "Generated %s", r.GeneratedAt.Format(timeFormat))
+	if r.Since != nil {
+		fmt.Fprintf(&b, ", covering tasks since %s", r.Since.Format(timeFormat))
+	}
+	b.WriteString(".\n\n")
+
+	s := r.Summary
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- Tasks: %d (%d done, %d in progress, %d blocked, %d open)\n", s.Tasks, s.Done, s.InProgress, s.Blocked, s.Open)
+	fmt.Fprintf(&b, "- Branches: %d with %d commits ahead of %s\n", s.Branches, s.Commits, r.BaseBranch)
+	fmt.Fprintf(&b, "- Changes: %d files, +%d -%d\n", s.Files, s.Insertions, s.Deletions)
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(&b, "- Warning: %s\n", warning)
+	}
+
+	if len(r.Tasks) == 0 {
+		b.WriteString("\nNo tasks.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("\n## Tasks\n\n")
+	b.WriteString("| Task | Title | Status | Worker | Took | Commits | Changes |\n")
+	b.WriteString("|------|-------|--------|--------|------|---------|---------|\n")
+	for _, t := range r.Tasks {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			t.ID, markdownCell(t.Title), t.Status, dash(t.Worker), dash(formatDuration(t.Duration())),
+			dash(commits(t.Branch)), dash(changes(t.Branch)))
+	}
+
+	for _, t := range r.Tasks {
+		fmt.Fprintf(&b, "\n### %s: %s\n\n", t.ID, t.Title)
+		fmt.Fprintf(&b, "- Status: %s, priority %s\n", t.Status, t.Priority)
+		if t.Worker != "" {
+			fmt.Fprintf(&b, "- Worker: %s\n", t.Worker)
+		}
+		fmt.Fprintf(&b, "- Created: %s\n", t.CreatedAt.Format(timeFormat))
+		if t.ClaimedAt != nil {
+			fmt.Fprintf(&b, "- Claimed: %s\n", t.ClaimedAt.Format(timeFormat))
+		}
+		if t.CompletedAt != nil {
+			fmt.Fprintf(&b, "- Completed: %s\n", t.CompletedAt.Format(timeFormat))
+		}
+		if br := t.Branch; br != nil {
+			fmt.Fprintf(&b, "- Branch: `%s` at %s, %s, %s\n", br.Name, br.Commit, commits(br), changes(br))
+			if br.Subject != "" {
+				fmt.Fprintf(&b, "- Last commit: %s\n", br.Subject)
+			}
+		}
+		if len(t.History) > 0 {
+			b.WriteString("\nHistory:\n\n")
+			for _, e := range t.History {
+				fmt.Fprintf(&b, "- %s %s%s\n", e.Time.Format(timeFormat), e.Type, eventDetail(e.Worker, e.Message))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// HTML renders a report as a standalone HTML page with the same content
+// as Markdown
+func HTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
+
+const timeFormat = "2006-01-02 15:04"
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"time":     func(t time.Time) string { return t.Format(timeFormat) },
+	"dash":     dash,
+	"duration": formatDuration,
+	"commits":  commits,
+	"changes":  changes,
+	"detail":   eventDetail,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>isollm report: {{.Project}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.3rem 0.6rem; border-bottom: 1px solid #ddd; }
+code { background: #f4f4f4; padding: 0 0.2rem; }
+.status-done { color: #2a7a2a; }
+.status-blocked { color: #b03030; }
+.status-in_progress { color: #b07800; }
+.warning { color: #b03030; }
+</style>
+</head>
+<body>
+<h1>isollm report: {{.Project}}</h1>
+<p>Generated {{time .GeneratedAt}}{{with .Since}}, covering tasks since {{time .}}{{end}}.</p>
+
+<h2>Summary</h2>
+<ul>
+<li>Tasks: {{.Summary.Tasks}} ({{.Summary.Done}} done, {{.Summary.InProgress}} in progress, {{.Summary.Blocked}} blocked, {{.Summary.Open}} open)</li>
+<li>Branches: {{.Summary.Branches}} with {{.Summary.Commits}} commits ahead of {{.BaseBranch}}</li>
+<li>Changes: {{.Summary.Files}} files, +{{.Summary.Insertions}} -{{.Summary.Deletions}}</li>
+{{- range .Warnings}}
+<li class="warning">Warning: {{.}}</li>
+{{- end}}
+</ul>
+{{if not .Tasks}}
+<p>No tasks.</p>
+{{else}}
+<h2>Tasks</h2>
+<table>
+<tr><th>Task</th><th>Title</th><th>Status</th><th>Worker</th><th>Took</th><th>Commits</th><th>Changes</th></tr>
+{{- range .Tasks}}
+<tr><td><a href="#{{.ID}}">{{.ID}}</a></td><td>{{.Title}}</td><td class="status-{{.Status}}">{{.Status}}</td><td>{{dash .Worker}}</td><td>{{dash (duration .Duration)}}</td><td>{{dash (commits .Branch)}}</td><td>{{dash (changes .Branch)}}</td></tr>
+{{- end}}
+</table>
+{{range .Tasks}}
+<h3 id="{{.ID}}">{{.ID}}: {{.Title}}</h3>
+<ul>
+<li>Status: {{.Status}}, priority {{.Priority}}</li>
+{{- with .Worker}}
+<li>Worker: {{.}}</li>
+{{- end}}
+<li>Created: {{time .CreatedAt}}</li>
+{{- with .ClaimedAt}}
+<li>Claimed: {{time .}}</li>
+{{- end}}
+{{- with .CompletedAt}}
+<li>Completed: {{time .}}</li>
+{{- end}}
+{{- with .Branch}}
+<li>Branch: <code>{{.Name}}</code> at {{.Commit}}, {{commits .}}, {{changes .}}</li>
+{{- with .Subject}}
+<li>Last commit: {{.}}</li>
+{{- end}}
+{{- end}}
+</ul>
+{{- with .History}}
+<p>History:</p>
+<ul>
+{{- range .}}
+<li>{{time .Time}} {{.Type}}{{detail .Worker .Message}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// commits describes how far a branch is ahead of base, "" for no branch
+func commits(b *Branch) string {
+	if b == nil {
+		return ""
+	}
+	if b.Commits == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", b.Commits)
+}
+
+// changes describes a branch's diffstat, "" for no branch
+func changes(b *Branch) string {
+	if b == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d files +%d -%d", b.Files, b.Insertions, b.Deletions)
+}
+
+// eventDetail formats who did something and why, after an event type
+func eventDetail(worker, message string) string {
+	var parts []string
+	if worker != "" {
+		parts = append(parts, "by "+worker)
+	}
+	if message != "" {
+		parts = append(parts, "("+strings.Join(strings.Fields(message), " ")+")")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " ")
+}
+
+// formatDuration rounds a duration for display, "" for zero
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
+
+// markdownCell escapes text for a markdown table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "|", `\|`)
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"isollm/internal/barerepo"
+	"isollm/internal/events"
+)
+
+func testReport() *Report {
+	claimed := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	completed := claimed.Add(90 * time.Minute)
+	return &Report{
+		Project:     "demo",
+		GeneratedAt: completed,
+		BaseBranch:  "main",
+		Summary:     Summary{Tasks: 2, Done: 1, Open: 1, Branches: 1, Commits: 2},
+		Tasks: []Task{
+			{
+				ID: "ar-0001", Title: "Add <login> | form", Status: "done", Priority: "high",
+				Worker: "isollm-worker-1", CreatedAt: claimed, ClaimedAt: &claimed, CompletedAt: &completed,
+				Branch: &Branch{Name: "isollm/ar-0001", Commit: "abc1234", Commits: 2,
+					DiffStat: barerepo.DiffStat{Files: 3, Insertions: 40, Deletions: 2}},
+				History: []events.Event{{Time: claimed, Type: events.TaskClaimed, Worker: "isollm-worker-1"}},
+			},
+			{ID: "ar-0002", Title: "Write docs", Status: "open", Priority: "normal", CreatedAt: claimed},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": FormatMarkdown, "md": FormatMarkdown, "html": FormatHTML, "json": FormatJSON} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("ParseFormat(pdf) = nil error, want error")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Markdown(&buf, testReport()); err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# isollm report: demo",
+		"- Tasks: 2 (1 done, 0 in progress, 0 blocked, 1 open)",
+		`| ar-0001 | Add <login> \| form | done | isollm-worker-1 | 1h30m | 2 commits | 3 files +40 -2 |`,
+		"| ar-0002 | Write docs | open | - | - | - | - |",
+		"- Branch: `isollm/ar-0001` at abc1234, 2 commits, 3 files +40 -2",
+		"- 2026-05-01 10:00 task.claimed by isollm-worker-1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTML(&buf, testReport()); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "Add &lt;login&gt; | form") {
+		t.Error("HTML() did not escape the task title")
+	}
+	if !strings.Contains(out, `<h3 id="ar-0001">`) || !strings.Contains(out, "<code>isollm/ar-0001</code>") {
+		t.Errorf("HTML() missing task section:\n%s", out)
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testReport(), FormatJSON); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	tasks := got["tasks"].([]interface{})
+	branch := tasks[0].(map[string]interface{})["branch"].(map[string]interface{})
+	if branch["insertions"] != float64(40) {
+		t.Errorf("branch = %v, want diffstat fields inline", branch)
+	}
+}
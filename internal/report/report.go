@@ -0,0 +1,229 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"isollm/internal/airyra"
+	"isollm/internal/barerepo"
+	"isollm/internal/events"
+	"isollm/internal/state"
+)
+
+// Repo is the subset of barerepo.BareRepo used for reports
+type Repo interface {
+	ListTaskBranches() ([]barerepo.BranchInfo, error)
+	GetBranchCommitCount(branchName, baseBranch string) (int, error)
+	DiffStat(branchName, baseBranch string) (barerepo.DiffStat, error)
+}
+
+// Sources is where a report's data comes from
+type Sources struct {
+	Project    string
+	BaseBranch string
+	Tasks      airyra.TaskClient
+	Repo       Repo                 // Nil if there is no bare repo
+	Events     *events.Log          // Nil to leave out task history
+	Workers    []*state.WorkerState // Current worker assignments
+	Since      time.Time            // Only tasks created or updated since, zero for all
+}
+
+// Report is what happened to the tasks of a project
+type Report struct {
+	Project     string     `json:"project"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Since       *time.Time `json:"since,omitempty"`
+	BaseBranch  string     `json:"base_branch"`
+	Summary     Summary    `json:"summary"`
+	Tasks       []Task     `json:"tasks"`
+	Warnings    []string   `json:"warnings,omitempty"` // Sources that could not be read
+}
+
+// Summary totals a report
+type Summary struct {
+	Tasks      int `json:"tasks"`
+	Done       int `json:"done"`
+	InProgress int `json:"in_progress"`
+	Blocked    int `json:"blocked"`
+	Open       int `json:"open"`
+	Branches   int `json:"branches"`
+	Commits    int `json:"commits"`
+	barerepo.DiffStat
+}
+
+// Task is one task with its branch and history
+type Task struct {
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Status      string         `json:"status"`
+	Priority    string         `json:"priority"`
+	Worker      string         `json:"worker,omitempty"` // Worker holding the task, or the last to claim it
+	CreatedAt   time.Time      `json:"created_at"`
+	ClaimedAt   *time.Time     `json:"claimed_at,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	Branch      *Branch        `json:"branch,omitempty"`
+	History     []events.Event `json:"history,omitempty"`
+}
+
+// Duration returns how long the task took from claim to completion, or
+// zero if it has not been both claimed and completed
+func (t Task) Duration() time.Duration {
+	if t.ClaimedAt == nil || t.CompletedAt == nil {
+		return 0
+	}
+	return t.CompletedAt.Sub(*t.ClaimedAt)
+}
+
+// Branch is a task branch in the bare repo
+type Branch struct {
+	Name    string `json:"name"`
+	Commit  string `json:"commit"`
+	Subject string `json:"subject"`
+	Commits int    `json:"commits"` // Ahead of the base branch
+	barerepo.DiffStat
+}
+
+// Build gathers a report. Airyra must be reachable; the bare repo and
+// event log are optional, and failures reading them are recorded as
+// warnings.
+func Build(ctx context.Context, src Sources) (*Report, error) {
+	r := &Report{
+		Project:     src.Project,
+		GeneratedAt: time.Now(),
+		BaseBranch:  src.BaseBranch,
+	}
+	if !src.Since.IsZero() {
+		r.Since = &src.Since
+	}
+
+	tasks, err := airyra.ListAllTasks(ctx, src.Tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	history := map[string][]events.Event{}
+	if src.Events != nil {
+		list, err := src.Events.Read(events.Filter{Types: []string{"task", "branch"}, Since: src.Since})
+		if err != nil {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("event log: %v", err))
+		}
+		for _, e := range list {
+			if e.TaskID != "" {
+				history[e.TaskID] = append(history[e.TaskID], e)
+			}
+		}
+	}
+
+	branches := map[string]*Branch{}
+	if src.Repo != nil {
+		branches, err = listBranches(src.Repo, src.BaseBranch)
+		if err != nil {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("bare repo: %v", err))
+		}
+	}
+
+	holders := make(map[string]string, len(src.Workers))
+	for _, w := range src.Workers {
+		if w.HasTask() {
+			holders[w.CurrentTask.ID] = w.Name
+		}
+	}
+
+	for _, t := range tasks {
+		if !src.Since.IsZero() && t.UpdatedAt.Before(src.Since) && len(history[t.ID]) == 0 {
+			continue
+		}
+
+		task := Task{
+			ID:        t.ID,
+			Title:     t.Title,
+			Status:    string(t.Status),
+			Priority:  airyra.PriorityToString(t.Priority),
+			Worker:    holders[t.ID],
+			CreatedAt: t.CreatedAt,
+			ClaimedAt: t.ClaimedAt,
+			Branch:    branches[t.ID],
+			History:   history[t.ID],
+		}
+		task.applyHistory()
+		if task.CompletedAt == nil && t.Status == airyra.StatusDone {
+			updated := t.UpdatedAt
+			task.CompletedAt = &updated
+		}
+
+		r.Tasks = append(r.Tasks, task)
+		r.Summary.add(task)
+	}
+
+	sort.SliceStable(r.Tasks, func(i, j int) bool {
+		return r.Tasks[i].CreatedAt.Before(r.Tasks[j].CreatedAt)
+	})
+	return r, nil
+}
+
+// applyHistory fills in the worker and times the event log knows better
+// than airyra: who last claimed the task and when it was completed
+func (t *Task) applyHistory() {
+	held := t.Worker != ""
+	for _, e := range t.History {
+		switch e.Type {
+		case events.TaskClaimed:
+			if !held {
+				t.Worker = e.Worker
+			}
+			claimed := e.Time
+			t.ClaimedAt = &claimed
+		case events.TaskCompleted:
+			completed := e.Time
+			t.CompletedAt = &completed
+		}
+	}
+}
+
+// add counts a task in the summary
+func (s *Summary) add(t Task) {
+	s.Tasks++
+	switch airyra.TaskStatus(t.Status) {
+	case airyra.StatusDone:
+		s.Done++
+	case airyra.StatusInProgress:
+		s.InProgress++
+	case airyra.StatusBlocked:
+		s.Blocked++
+	case airyra.StatusOpen:
+		s.Open++
+	}
+
+	if t.Branch != nil {
+		s.Branches++
+		s.Commits += t.Branch.Commits
+		s.Files += t.Branch.Files
+		s.Insertions += t.Branch.Insertions
+		s.Deletions += t.Branch.Deletions
+	}
+}
+
+// listBranches maps task IDs to their branches. A branch whose commits or
+// diff cannot be read is still listed.
+func listBranches(repo Repo, baseBranch string) (map[string]*Branch, error) {
+	infos, err := repo.ListTaskBranches()
+	if err != nil {
+		return map[string]*Branch{}, err
+	}
+
+	branches := make(map[string]*Branch, len(infos))
+	var firstErr error
+	for _, info := range infos {
+		b := &Branch{Name: info.Name, Commit: info.CommitHash, Subject: info.Subject}
+		if b.Commits, err = repo.GetBranchCommitCount(info.Name, baseBranch); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if b.DiffStat, err = repo.DiffStat(info.Name, baseBranch); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		branches[info.TaskID] = b
+	}
+	return branches, firstErr
+}
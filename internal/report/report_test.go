@@ -0,0 +1,142 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"isollm/internal/airyra"
+	"isollm/internal/barerepo"
+	"isollm/internal/events"
+	"isollm/internal/state"
+)
+
+type mockRepo struct {
+	branches []barerepo.BranchInfo
+	commits  map[string]int
+	stats    map[string]barerepo.DiffStat
+	listErr  error
+}
+
+func (m *mockRepo) ListTaskBranches() ([]barerepo.BranchInfo, error) {
+	return m.branches, m.listErr
+}
+
+func (m *mockRepo) GetBranchCommitCount(branchName, baseBranch string) (int, error) {
+	return m.commits[branchName], nil
+}
+
+func (m *mockRepo) DiffStat(branchName, baseBranch string) (barerepo.DiffStat, error) {
+	return m.stats[branchName], nil
+}
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	mock := airyra.NewMockClient()
+
+	done, _ := mock.AddTask(ctx, "Add login")
+	active, _ := mock.AddTask(ctx, "Fix cache")
+	mock.AddTask(ctx, "Write docs")
+	mock.ClaimTask(ctx, done.ID)
+	mock.CompleteTask(ctx, done.ID)
+	mock.ClaimTask(ctx, active.ID)
+
+	log := events.NewWithDir(t.TempDir())
+	claimed := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	log.Emit(events.Event{Time: claimed, Type: events.TaskClaimed, Worker: "isollm-worker-1", TaskID: done.ID})
+	log.Emit(events.Event{Time: claimed.Add(25 * time.Minute), Type: events.TaskCompleted, Worker: "isollm-worker-1", TaskID: done.ID})
+	log.Emit(events.Event{Time: claimed, Type: events.TaskClaimed, Worker: "isollm-worker-3", TaskID: active.ID})
+
+	repo := &mockRepo{
+		branches: []barerepo.BranchInfo{
+			{Name: "isollm/" + done.ID, TaskID: done.ID, CommitHash: "abc1234", Subject: "Add login form"},
+		},
+		commits: map[string]int{"isollm/" + done.ID: 3},
+		stats:   map[string]barerepo.DiffStat{"isollm/" + done.ID: {Files: 4, Insertions: 120, Deletions: 7}},
+	}
+
+	r, err := Build(ctx, Sources{
+		Project:    "demo",
+		BaseBranch: "main",
+		Tasks:      mock,
+		Repo:       repo,
+		Events:     log,
+		Workers:    []*state.WorkerState{{Name: "isollm-worker-2", CurrentTask: &state.TaskRef{ID: active.ID}}},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	want := Summary{Tasks: 3, Done: 1, InProgress: 1, Open: 1, Branches: 1, Commits: 3,
+		DiffStat: barerepo.DiffStat{Files: 4, Insertions: 120, Deletions: 7}}
+	if r.Summary != want {
+		t.Errorf("Summary = %+v, want %+v", r.Summary, want)
+	}
+
+	byID := map[string]Task{}
+	for _, task := range r.Tasks {
+		byID[task.ID] = task
+	}
+
+	got := byID[done.ID]
+	if got.Worker != "isollm-worker-1" {
+		t.Errorf("done task worker = %q, want the last claimer", got.Worker)
+	}
+	if got.Duration() != 25*time.Minute {
+		t.Errorf("done task Duration() = %v, want 25m", got.Duration())
+	}
+	if got.Branch == nil || got.Branch.Commits != 3 || got.Branch.Files != 4 {
+		t.Errorf("done task branch = %+v, want 3 commits and 4 files", got.Branch)
+	}
+	if len(got.History) != 2 {
+		t.Errorf("done task history = %d events, want 2", len(got.History))
+	}
+
+	if w := byID[active.ID].Worker; w != "isollm-worker-2" {
+		t.Errorf("active task worker = %q, want the current holder", w)
+	}
+}
+
+func TestBuild_Since(t *testing.T) {
+	ctx := context.Background()
+	mock := airyra.NewMockClient()
+
+	old := time.Now().Add(-48 * time.Hour)
+	mock.AddTaskDirect(&airyra.Task{ID: "ar-old", Title: "Old", Status: airyra.StatusDone, CreatedAt: old, UpdatedAt: old})
+	mock.AddTask(ctx, "New")
+
+	r, err := Build(ctx, Sources{Tasks: mock, Since: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(r.Tasks) != 1 || r.Tasks[0].Title != "New" {
+		t.Errorf("Build() tasks = %+v, want only the new task", r.Tasks)
+	}
+	if r.Since == nil {
+		t.Error("Build() Since = nil, want the cutoff")
+	}
+}
+
+func TestBuild_RepoErrorIsWarning(t *testing.T) {
+	ctx := context.Background()
+	mock := airyra.NewMockClient()
+	mock.AddTask(ctx, "Task 1")
+
+	r, err := Build(ctx, Sources{Tasks: mock, Repo: &mockRepo{listErr: errors.New("no repo")}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(r.Warnings) != 1 || len(r.Tasks) != 1 {
+		t.Errorf("Build() = %d warnings, %d tasks; want 1 and 1", len(r.Warnings), len(r.Tasks))
+	}
+}
+
+func TestBuild_AiryraDown(t *testing.T) {
+	mock := airyra.NewMockClient()
+	mock.ServerRunning = false
+
+	if _, err := Build(context.Background(), Sources{Tasks: mock}); err == nil {
+		t.Error("Build() = nil error, want airyra failure")
+	}
+}
@@ -3,6 +3,7 @@ package session
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,7 +12,10 @@ import (
 
 	"isollm/internal/airyra"
 	"isollm/internal/config"
+	"isollm/internal/events"
 	"isollm/internal/git"
+	"isollm/internal/hooks"
+	"isollm/internal/state"
 	"isollm/internal/worker"
 	"isollm/internal/zellij"
 )
@@ -21,6 +25,7 @@ type ShutdownOptions struct {
 	Destroy             bool
 	SaveSnapshots       bool
 	SkipConfirm         bool
+	StopServer          bool
 	ReleaseTasksTimeout time.Duration
 }
 
@@ -41,8 +46,11 @@ type Shutdown struct {
 	mgr        *worker.Manager
 	zellij     *zellij.Manager
 	airyra     airyra.TaskClient
+	users      *airyra.Users
 	reader     *bufio.Reader
 	gitExec    git.Executor
+	events     *events.Log
+	state      state.State
 }
 
 // NewShutdown creates a new Shutdown handler
@@ -62,6 +70,10 @@ func NewShutdown(projectDir string, cfg *config.Config, opts ShutdownOptions) (*
 
 	// Initialize airyra client (may fail if not running)
 	airyraClient, _ := airyra.NewClientFromConfig(cfg)
+	users, _ := airyra.NewUsers(cfg.Airyra.Host, cfg.Airyra.Port)
+
+	eventLog := events.New(projectDir)
+	hooks.Attach(eventLog, cfg)
 
 	return &Shutdown{
 		projectDir: projectDir,
@@ -70,8 +82,11 @@ func NewShutdown(projectDir string, cfg *config.Config, opts ShutdownOptions) (*
 		mgr:        mgr,
 		zellij:     zellijMgr,
 		airyra:     airyraClient,
+		users:      users,
 		reader:     bufio.NewReader(os.Stdin),
 		gitExec:    git.DefaultExecutor,
+		events:     eventLog,
+		state:      state.New(projectDir),
 	}, nil
 }
 
@@ -88,7 +103,11 @@ func (s *Shutdown) Execute() error {
 
 	if len(workers) == 0 {
 		fmt.Println("No workers to shut down")
-		return s.cleanup()
+		if err := s.cleanup(); err != nil {
+			return err
+		}
+		s.releaseAiryra()
+		return nil
 	}
 
 	// Step 2: Check for uncommitted/unpushed work
@@ -117,6 +136,8 @@ func (s *Shutdown) Execute() error {
 		}
 	}
 
+	s.markShuttingDown()
+
 	// Step 4: Release claimed tasks back to airyra queue
 	if err := s.releaseTasks(workers); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to release some tasks: %v\n", err)
@@ -155,6 +176,15 @@ func (s *Shutdown) Execute() error {
 		fmt.Fprintf(os.Stderr, "Warning: failed to clear session state: %v\n", err)
 	}
 
+	// Step 10: Stop the airyra server if asked and nobody else needs it
+	s.releaseAiryra()
+
+	action := "workers stopped"
+	if s.opts.Destroy {
+		action = "workers destroyed"
+	}
+	s.emit(events.Event{Type: events.SessionStopped, Message: action})
+
 	fmt.Println()
 	fmt.Println("Shutdown complete")
 	return nil
@@ -342,6 +372,13 @@ func (s *Shutdown) releaseTasks(workers []worker.WorkerInfo) error {
 			fmt.Fprintf(os.Stderr, "  Warning: failed to clear task state for %s: %v\n", w.Name, err)
 		}
 
+		s.emit(events.Event{
+			Type:    events.TaskReleased,
+			Worker:  w.Name,
+			TaskID:  w.TaskID,
+			Branch:  w.Branch,
+			Message: "session shutdown",
+		})
 		fmt.Printf("  Released %s from %s\n", w.TaskID, w.Name)
 	}
 
@@ -468,18 +505,101 @@ func (s *Shutdown) runBareRepoGC() error {
 	return s.gitExec.RunSilent(bareRepoPath, "gc", "--auto")
 }
 
+// emit records an event in the session history
+func (s *Shutdown) emit(e events.Event) {
+	if s.events != nil {
+		s.events.Emit(e)
+	}
+}
+
+// markShuttingDown records that the session is going down, so status and
+// a later isollm up can tell an interrupted shutdown from a running session.
+// The session takes this process's PID, as isollm up may have exited long
+// ago, so the shutdown counts as interrupted only once this process is gone.
+func (s *Shutdown) markShuttingDown() {
+	session, err := s.state.LoadSession()
+	if err != nil {
+		return // No session recorded, e.g. isollm up was run by an older isollm
+	}
+	session.Status = state.SessionStatusShuttingDown
+	session.PID = os.Getpid()
+	if err := s.state.SaveSession(session); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update session state: %v\n", err)
+	}
+}
+
 // cleanup clears any remaining session state
 func (s *Shutdown) cleanup() error {
-	// Clear any stale session state files
-	sessionStateDir := filepath.Join(s.projectDir, config.StateDir, "session")
-	if _, err := os.Stat(sessionStateDir); err == nil {
-		if err := os.RemoveAll(sessionStateDir); err != nil {
-			return fmt.Errorf("failed to clear session state: %w", err)
-		}
+	if err := s.state.ClearSession(); err != nil {
+		return fmt.Errorf("failed to clear session state: %w", err)
 	}
 	return nil
 }
 
+// releaseAiryra records that the project no longer uses the airyra server
+// and, with StopServer, stops the server once no other project with a
+// session uses it
+func (s *Shutdown) releaseAiryra() {
+	if s.users == nil {
+		return
+	}
+	if err := s.users.Remove(s.projectDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if !s.opts.StopServer {
+		return
+	}
+
+	roots, err := s.users.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; leaving airyra server running\n", err)
+		return
+	}
+
+	// Whoever started the server may have gone down already, leaving the
+	// pidfile in their project
+	servers := []*airyra.Server{airyra.NewServer(s.projectDir, s.cfg.Airyra.Host, s.cfg.Airyra.Port)}
+	var inUse []string
+	for _, root := range roots {
+		if root != s.projectDir && hasSession(root) {
+			inUse = append(inUse, root)
+			continue
+		}
+		s.users.Remove(root)
+		servers = append(servers, airyra.NewServer(root, s.cfg.Airyra.Host, s.cfg.Airyra.Port))
+	}
+	if len(inUse) > 0 {
+		fmt.Printf("Leaving airyra server running, still used by %s\n", strings.Join(inUse, ", "))
+		return
+	}
+
+	for _, server := range servers {
+		if server.PID() == 0 {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), airyra.DefaultStopTimeout)
+		err := server.Stop(ctx)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			return
+		}
+		fmt.Println("Stopped airyra server")
+		return
+	}
+	fmt.Println("Leaving airyra server running, it was not started by isollm")
+}
+
+// hasSession reports whether the project at root has an isollm session
+func hasSession(root string) bool {
+	// Don't let the state lock recreate a project that was deleted
+	if _, err := os.Stat(filepath.Join(root, config.StateDir)); err != nil {
+		return false
+	}
+	_, err := state.New(root).LoadSession()
+	return !errors.Is(err, state.ErrNoSession)
+}
+
 // getBareRepoPath returns the standard bare repo path for a project
 func getBareRepoPath(projectName string) (string, error) {
 	home, err := os.UserHomeDir()
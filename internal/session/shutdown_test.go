@@ -6,12 +6,15 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"isollm/internal/airyra"
 	"isollm/internal/config"
+	"isollm/internal/state"
 	"isollm/internal/worker"
 )
 
@@ -181,6 +184,7 @@ func newTestShutdownEnv(t *testing.T) *testShutdownEnv {
 		},
 		reader:  bufio.NewReader(inputBuffer),
 		gitExec: mockGit,
+		state:   state.NewWithDir(t.TempDir()),
 	}
 
 	// We need to set up the mock manager and other dependencies
@@ -651,15 +655,41 @@ func TestStopContainers(t *testing.T) {
 }
 
 func TestCleanup(t *testing.T) {
-	t.Run("removes session state directory", func(t *testing.T) {
+	t.Run("removes session state", func(t *testing.T) {
 		env := newTestShutdownEnv(t)
+		st := env.shutdown.state
 
-		// cleanup removes the session state directory
-		// We can't easily test file operations without a real filesystem
-		// but we verify the method exists and can be called
-		err := env.shutdown.cleanup()
-		// Error is expected because /test/project doesn't exist
-		_ = err
+		if err := st.CreateSession(&state.Session{Status: state.SessionStatusRunning}); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+
+		env.shutdown.markShuttingDown()
+		session, err := st.LoadSession()
+		if err != nil {
+			t.Fatalf("LoadSession() error = %v", err)
+		}
+		if session.Status != state.SessionStatusShuttingDown {
+			t.Errorf("Status = %q, want shutting_down", session.Status)
+		}
+		if session.PID != os.Getpid() {
+			t.Errorf("PID = %d, want the shutting down process %d", session.PID, os.Getpid())
+		}
+
+		if err := env.shutdown.cleanup(); err != nil {
+			t.Fatalf("cleanup() error = %v", err)
+		}
+		if _, err := st.LoadSession(); !errors.Is(err, state.ErrNoSession) {
+			t.Errorf("LoadSession() after cleanup error = %v, want ErrNoSession", err)
+		}
+	})
+
+	t.Run("no session", func(t *testing.T) {
+		env := newTestShutdownEnv(t)
+
+		env.shutdown.markShuttingDown()
+		if err := env.shutdown.cleanup(); err != nil {
+			t.Errorf("cleanup() error = %v", err)
+		}
 	})
 }
 
@@ -891,3 +921,48 @@ func TestShutdown_ErrorHandling(t *testing.T) {
 		}
 	})
 }
+
+func TestReleaseAiryra(t *testing.T) {
+	t.Run("kept while another project has a session", func(t *testing.T) {
+		env := newTestShutdownEnv(t)
+		env.shutdown.projectDir = t.TempDir()
+		env.shutdown.opts.StopServer = true
+		users := airyra.NewUsersWithDir(t.TempDir())
+		env.shutdown.users = users
+
+		other := t.TempDir()
+		if err := state.New(other).CreateSession(&state.Session{Status: state.SessionStatusRunning}); err != nil {
+			t.Fatal(err)
+		}
+		users.Add(env.shutdown.projectDir)
+		users.Add(other)
+
+		env.shutdown.releaseAiryra()
+
+		roots, _ := users.List()
+		if len(roots) != 1 || roots[0] != other {
+			t.Errorf("users = %v, want only %s", roots, other)
+		}
+	})
+
+	t.Run("forgets projects without a session", func(t *testing.T) {
+		env := newTestShutdownEnv(t)
+		env.shutdown.projectDir = t.TempDir()
+		env.shutdown.opts.StopServer = true
+		users := airyra.NewUsersWithDir(t.TempDir())
+		env.shutdown.users = users
+
+		gone := filepath.Join(t.TempDir(), "deleted")
+		users.Add(env.shutdown.projectDir)
+		users.Add(gone)
+
+		env.shutdown.releaseAiryra()
+
+		if roots, _ := users.List(); len(roots) != 0 {
+			t.Errorf("users = %v, want none", roots)
+		}
+		if _, err := os.Stat(gone); !os.IsNotExist(err) {
+			t.Errorf("deleted project was recreated: %v", err)
+		}
+	})
+}
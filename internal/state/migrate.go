@@ -0,0 +1,133 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Session versions:
+//
+//	0: written by cmd/up before it used this package: project, workers,
+//	   base branch and airyra port, without status, PID or paths
+//	1: status, PID, project root, bare repo and zellij session
+//	2: version 1 plus project, workers, base branch and airyra port
+//
+// Worker versions:
+//
+//	1: container, status and provisioning errors
+//	2: also the worker's task assignment, which used to be kept on its own
+//	   in .isollm/tasks/<worker>.json
+
+// legacyTasksDir held one task assignment file per worker before version 2
+const legacyTasksDir = "tasks"
+
+// legacyTask is a task assignment file from legacyTasksDir
+type legacyTask struct {
+	WorkerName string    `json:"worker_name"`
+	TaskID     string    `json:"task_id,omitempty"`
+	Branch     string    `json:"branch,omitempty"`
+	ClaimedAt  time.Time `json:"claimed_at,omitempty"`
+}
+
+// migrateSession upgrades a session read from disk to the current version
+func (m *FileState) migrateSession(s *Session) error {
+	if s.Version > CurrentSessionVersion {
+		return fmt.Errorf("session was written by a newer isollm (version %d, this one knows %d)", s.Version, CurrentSessionVersion)
+	}
+
+	if s.Version == 0 {
+		// cmd/up only saved the session once everything was running. It
+		// did not record its PID, so the session reads as stale.
+		if s.Status == "" {
+			s.Status = SessionStatusRunning
+		}
+		if s.ProjectRoot == "" {
+			s.ProjectRoot = filepath.Dir(m.stateDir)
+		}
+	}
+
+	s.Version = CurrentSessionVersion
+	return nil
+}
+
+// migrateWorker upgrades a worker read from disk to the current version.
+// Task assignments from before version 2 are folded in by migrateTasks.
+func migrateWorker(w *WorkerState) error {
+	if w.Version > CurrentWorkerVersion {
+		return fmt.Errorf("state was written by a newer isollm (version %d, this one knows %d)", w.Version, CurrentWorkerVersion)
+	}
+	w.Version = CurrentWorkerVersion
+	return nil
+}
+
+// migrate runs the one-off migrations that move files around, once per
+// FileState
+func (m *FileState) migrate() error {
+	m.migrateOnce.Do(func() {
+		m.migrateErr = m.migrateTasks()
+	})
+	return m.migrateErr
+}
+
+// migrateTasks moves task assignments from legacyTasksDir into worker
+// state and removes the old files
+func (m *FileState) migrateTasks() error {
+	dir := filepath.Join(m.stateDir, legacyTasksDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var task legacyTask
+		if err := json.Unmarshal(data, &task); err != nil {
+			return fmt.Errorf("invalid task state %s: %w", path, err)
+		}
+		if task.WorkerName == "" {
+			task.WorkerName = strings.TrimSuffix(e.Name(), ".json")
+		}
+
+		w, err := m.loadWorker(task.WorkerName + ".json")
+		if errors.Is(err, ErrNoWorker) {
+			w = &WorkerState{Name: task.WorkerName, Status: WorkerStatusIdle}
+		} else if err != nil {
+			return err
+		}
+
+		if task.TaskID != "" && !w.HasTask() {
+			w.CurrentTask = &TaskRef{ID: task.TaskID, ClaimedAt: task.ClaimedAt}
+			w.CurrentBranch = task.Branch
+			if w.Status == WorkerStatusIdle {
+				w.Status = WorkerStatusBusy
+			}
+		}
+
+		w.Version = CurrentWorkerVersion
+		if err := m.saveJSON(filepath.Join("workers", task.WorkerName+".json"), w); err != nil {
+			return fmt.Errorf("failed to migrate task state for %s: %w", task.WorkerName, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove migrated %s: %w", path, err)
+		}
+	}
+
+	os.Remove(dir) // Only succeeds once empty
+	return nil
+}
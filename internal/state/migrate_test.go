@@ -0,0 +1,138 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrate_LegacySession(t *testing.T) {
+	fs, dir := newTestState(t)
+	writeFile(t, filepath.Join(dir, "session.json"), `{
+  "project": "demo",
+  "workers": ["isollm-worker-1", "isollm-worker-2"],
+  "started_at": "2026-03-01T10:00:00Z",
+  "zellij_session": "isollm-demo",
+  "airyra_port": 7432,
+  "base_branch": "main"
+}`)
+
+	s, err := fs.LoadSession()
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	if s.Version != CurrentSessionVersion {
+		t.Errorf("Version = %d, want %d", s.Version, CurrentSessionVersion)
+	}
+	if s.Project != "demo" || len(s.Workers) != 2 || s.AiryraPort != 7432 || s.BaseBranch != "main" {
+		t.Errorf("session = %+v, want the legacy fields kept", s)
+	}
+	if s.Status != SessionStatusRunning || s.ProjectRoot != filepath.Dir(dir) {
+		t.Errorf("Status = %q, ProjectRoot = %q, want running in %s", s.Status, s.ProjectRoot, filepath.Dir(dir))
+	}
+
+	// Upgraded on disk
+	data, _ := os.ReadFile(filepath.Join(dir, "session.json"))
+	if !strings.Contains(string(data), `"version": 2`) {
+		t.Errorf("session.json not rewritten: %s", data)
+	}
+
+	// Without a PID the session cannot be alive
+	if active, _ := fs.HasActiveSession(); active {
+		t.Error("HasActiveSession() = true for a legacy session")
+	}
+}
+
+func TestMigrate_NewerSession(t *testing.T) {
+	fs, dir := newTestState(t)
+	writeFile(t, filepath.Join(dir, "session.json"), `{"version": 99}`)
+
+	if _, err := fs.LoadSession(); err == nil || !strings.Contains(err.Error(), "newer isollm") {
+		t.Errorf("LoadSession() error = %v, want a newer version error", err)
+	}
+}
+
+func TestMigrate_LegacyTaskFiles(t *testing.T) {
+	fs, dir := newTestState(t)
+	writeFile(t, filepath.Join(dir, "tasks", "worker-1.json"), `{
+  "worker_name": "worker-1",
+  "task_id": "ar-0001",
+  "branch": "isollm/ar-0001",
+  "claimed_at": "2026-03-01T10:00:00Z"
+}`)
+	writeFile(t, filepath.Join(dir, "tasks", "worker-2.json"), `{"worker_name": "worker-2"}`)
+	writeFile(t, filepath.Join(dir, "workers", "worker-2.json"), `{
+  "version": 1,
+  "name": "worker-2",
+  "status": "error",
+  "last_error": "provisioning failed"
+}`)
+
+	result, err := fs.LoadAllWorkers()
+	if err != nil {
+		t.Fatalf("LoadAllWorkers() error = %v", err)
+	}
+	if len(result.Workers) != 2 || len(result.Errors) != 0 {
+		t.Fatalf("LoadAllWorkers() = %d workers, errors %v", len(result.Workers), result.Errors)
+	}
+
+	w1, err := fs.LoadWorker("worker-1")
+	if err != nil {
+		t.Fatalf("LoadWorker() error = %v", err)
+	}
+	if !w1.HasTask() || w1.CurrentTask.ID != "ar-0001" || w1.CurrentBranch != "isollm/ar-0001" {
+		t.Errorf("worker-1 = %+v, want the migrated task", w1)
+	}
+	if w1.Status != WorkerStatusBusy || w1.Version != CurrentWorkerVersion {
+		t.Errorf("worker-1 status %q version %d, want busy at %d", w1.Status, w1.Version, CurrentWorkerVersion)
+	}
+
+	w2, _ := fs.LoadWorker("worker-2")
+	if w2.Status != WorkerStatusError || w2.LastError != "provisioning failed" || w2.HasTask() {
+		t.Errorf("worker-2 = %+v, want its error kept and no task", w2)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "tasks")); !os.IsNotExist(err) {
+		t.Error("legacy tasks directory not removed")
+	}
+}
+
+func TestUpdateWorker(t *testing.T) {
+	fs, _ := newTestState(t)
+
+	err := fs.UpdateWorker("worker-1", func(w *WorkerState) {
+		w.Status = WorkerStatusBusy
+		w.CurrentTask = &TaskRef{ID: "ar-0001"}
+	})
+	if err != nil {
+		t.Fatalf("UpdateWorker() error = %v", err)
+	}
+
+	fs.UpdateWorker("worker-1", func(w *WorkerState) {
+		w.LastError = "oops"
+	})
+
+	w, err := fs.LoadWorker("worker-1")
+	if err != nil {
+		t.Fatalf("LoadWorker() error = %v", err)
+	}
+	if w.Name != "worker-1" || !w.HasTask() || w.LastError != "oops" {
+		t.Errorf("worker = %+v, want both updates", w)
+	}
+
+	if _, err := fs.LoadWorker("worker-2"); !errors.Is(err, ErrNoWorker) {
+		t.Errorf("LoadWorker(missing) error = %v, want ErrNoWorker", err)
+	}
+}
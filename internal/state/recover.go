@@ -0,0 +1,102 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Recover repairs what a crashed or killed isollm can leave behind and
+// describes each repair:
+//   - temp files from writes that were never renamed into place
+//   - state files that are not valid JSON, moved aside to <file>.corrupt
+//   - the session of an isollm up or down that died part way through
+//
+// A running session whose process is gone is left alone: isollm up exits
+// once the zellij session is detached.
+func (m *FileState) Recover() ([]string, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var repairs []string
+	for _, dir := range []string{m.stateDir, filepath.Join(m.stateDir, "workers")} {
+		temps, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+		for _, path := range temps {
+			if err := os.Remove(path); err != nil {
+				return repairs, fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+			repairs = append(repairs, "removed unfinished write "+m.rel(path))
+		}
+
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		for _, path := range files {
+			moved, err := moveCorrupt(path)
+			if err != nil {
+				return repairs, err
+			}
+			if moved {
+				repairs = append(repairs, fmt.Sprintf("moved unreadable %s to %s.corrupt", m.rel(path), m.rel(path)))
+			}
+		}
+	}
+
+	if err := m.migrate(); err != nil {
+		return repairs, err
+	}
+
+	session, err := m.loadSession()
+	if err != nil {
+		if errors.Is(err, ErrNoSession) {
+			return repairs, nil
+		}
+		return repairs, err
+	}
+
+	var cmd string
+	switch session.Status {
+	case SessionStatusInitializing:
+		cmd = "isollm up"
+	case SessionStatusShuttingDown:
+		cmd = "isollm down"
+	default:
+		return repairs, nil
+	}
+	if isProcessAlive(session.PID) {
+		return repairs, nil
+	}
+
+	if err := m.clearSession(); err != nil {
+		return repairs, fmt.Errorf("failed to clear session: %w", err)
+	}
+	repairs = append(repairs, fmt.Sprintf("cleared the session of an %s that did not finish (pid %d)", cmd, session.PID))
+	return repairs, nil
+}
+
+// moveCorrupt renames a state file that is not valid JSON out of the way,
+// reporting whether it did
+func moveCorrupt(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if json.Valid(data) {
+		return false, nil
+	}
+	if err := os.Rename(path, path+".corrupt"); err != nil {
+		return false, fmt.Errorf("failed to move %s aside: %w", path, err)
+	}
+	return true, nil
+}
+
+// rel returns path relative to the state directory, for messages
+func (m *FileState) rel(path string) string {
+	if rel, err := filepath.Rel(m.stateDir, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,161 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRecover_Clean(t *testing.T) {
+	fs, _ := newTestState(t)
+	fs.CreateSession(sampleSession())
+	fs.SaveWorker(sampleWorker("worker-1"))
+
+	repairs, err := fs.Recover()
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if len(repairs) != 0 {
+		t.Errorf("Recover() = %v, want no repairs", repairs)
+	}
+	if active, _ := fs.HasActiveSession(); !active {
+		t.Error("Recover() removed a live session")
+	}
+}
+
+func TestRecover_InterruptedShutdown(t *testing.T) {
+	fs, _ := newTestState(t)
+
+	session := sampleSession()
+	session.Status = SessionStatusShuttingDown
+	session.PID = 999999999
+	fs.CreateSession(session)
+
+	repairs, err := fs.Recover()
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if len(repairs) != 1 || !strings.Contains(repairs[0], "isollm down") {
+		t.Errorf("Recover() = %v, want the session cleared", repairs)
+	}
+	if _, err := fs.LoadSession(); !errors.Is(err, ErrNoSession) {
+		t.Errorf("LoadSession() error = %v, want ErrNoSession", err)
+	}
+}
+
+func TestRecover_KeepsDetachedSession(t *testing.T) {
+	fs, _ := newTestState(t)
+
+	// isollm up exits once zellij is detached; the session is still running
+	session := sampleSession()
+	session.PID = 999999999
+	fs.CreateSession(session)
+
+	repairs, _ := fs.Recover()
+	if len(repairs) != 0 {
+		t.Errorf("Recover() = %v, want no repairs", repairs)
+	}
+	if _, err := fs.LoadSession(); err != nil {
+		t.Errorf("LoadSession() error = %v", err)
+	}
+}
+
+func TestRecover_PartialFiles(t *testing.T) {
+	fs, dir := newTestState(t)
+	fs.SaveWorker(sampleWorker("worker-1"))
+	writeFile(t, filepath.Join(dir, "workers", "worker-2.json"), `{"name": "worker-2", "sta`)
+	writeFile(t, filepath.Join(dir, "session.json"), "")
+	writeFile(t, filepath.Join(dir, "workers", ".worker-1.json.123.tmp"), `{"name"`)
+
+	repairs, err := fs.Recover()
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if len(repairs) != 3 {
+		t.Errorf("Recover() = %v, want 3 repairs", repairs)
+	}
+
+	result, err := fs.LoadAllWorkers()
+	if err != nil {
+		t.Fatalf("LoadAllWorkers() error = %v", err)
+	}
+	if len(result.Workers) != 1 || len(result.Errors) != 0 {
+		t.Errorf("LoadAllWorkers() = %d workers, errors %v", len(result.Workers), result.Errors)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "workers", "worker-2.json.corrupt")); err != nil {
+		t.Errorf("corrupt worker not kept: %v", err)
+	}
+	if _, err := fs.LoadSession(); !errors.Is(err, ErrNoSession) {
+		t.Errorf("LoadSession() error = %v, want ErrNoSession", err)
+	}
+}
+
+func TestStartSession(t *testing.T) {
+	fs, _ := newTestState(t)
+
+	stale := sampleSession()
+	stale.PID = 999999999
+	fs.CreateSession(stale)
+
+	if err := fs.StartSession(sampleSession()); err != nil {
+		t.Fatalf("StartSession() over a stale session error = %v", err)
+	}
+	if err := fs.StartSession(sampleSession()); err == nil || !strings.Contains(err.Error(), "pid") {
+		t.Errorf("StartSession() over a live session error = %v, want ErrSessionExists", err)
+	}
+}
+
+func TestUpdateWorker_Concurrent(t *testing.T) {
+	fs, dir := newTestState(t)
+
+	// Separate FileStates stand in for separate isollm processes
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := NewWithDir(dir).UpdateWorker("worker-1", func(w *WorkerState) {
+				w.LastError += "x"
+			})
+			if err != nil {
+				t.Errorf("UpdateWorker() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	w, err := fs.LoadWorker("worker-1")
+	if err != nil {
+		t.Fatalf("LoadWorker() error = %v", err)
+	}
+	if len(w.LastError) != n {
+		t.Errorf("%d of %d updates kept", len(w.LastError), n)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "file.json")
+
+	if err := WriteFile(path, []byte("one"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := WriteFile(path, []byte("two"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "two" {
+		t.Errorf("content = %q, want two", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the file", len(entries))
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
 )
 
@@ -17,18 +19,26 @@ type State interface {
 	LoadSession() (*Session, error)      // Returns ErrNoSession if not found
 	ClearSession() error                 // Delete session file
 	HasActiveSession() (bool, error)     // Checks existence AND liveness
+	StartSession(s *Session) error       // Create, replacing a stale session
 
 	// Worker state
 	SaveWorker(w *WorkerState) error
-	LoadWorker(name string) (*WorkerState, error)
-	LoadAllWorkers() (*LoadResult, error) // Partial results + errors
+	LoadWorker(name string) (*WorkerState, error) // Wraps ErrNoWorker if not found
+	LoadAllWorkers() (*LoadResult, error)         // Partial results + errors
+	UpdateWorker(name string, fn func(w *WorkerState)) error
 	DeleteWorker(name string) error
 	ClearAllWorkers() error
+
+	// Recover repairs state left behind by a crashed isollm
+	Recover() ([]string, error)
 }
 
 // FileState implements State using the filesystem
 type FileState struct {
 	stateDir string
+
+	migrateOnce sync.Once
+	migrateErr  error
 }
 
 // Compile-time interface check
@@ -48,43 +58,65 @@ func NewWithDir(stateDir string) *FileState {
 
 // CreateSession atomically creates a new session (fails if exists)
 func (m *FileState) CreateSession(s *Session) error {
-	if err := os.MkdirAll(m.stateDir, 0755); err != nil {
-		return fmt.Errorf("failed to create state dir: %w", err)
+	unlock, err := m.lock()
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	fullPath := filepath.Join(m.stateDir, "session.json")
-	s.Version = CurrentSessionVersion
-
-	data, err := json.MarshalIndent(s, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal session: %w", err)
+	if _, err := os.Stat(filepath.Join(m.stateDir, "session.json")); err == nil {
+		return ErrSessionExists
 	}
+	return m.saveSession(s)
+}
 
-	// O_EXCL = fail if file exists (atomic check-and-create)
-	f, err := os.OpenFile(fullPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+// StartSession creates a new session. A session whose process is gone is
+// replaced; a live one is an error wrapping ErrSessionExists.
+func (m *FileState) StartSession(s *Session) error {
+	unlock, err := m.lock()
 	if err != nil {
-		if os.IsExist(err) {
-			return ErrSessionExists
-		}
-		return fmt.Errorf("failed to create session file: %w", err)
+		return err
 	}
-	defer f.Close()
+	defer unlock()
 
-	if _, err := f.Write(data); err != nil {
-		os.Remove(fullPath)
-		return fmt.Errorf("failed to write session: %w", err)
+	existing, err := m.loadSession()
+	if err == nil && isProcessAlive(existing.PID) {
+		return fmt.Errorf("%w (pid %d)", ErrSessionExists, existing.PID)
 	}
-	return nil
+	if err != nil && !errors.Is(err, ErrNoSession) {
+		return err
+	}
+	return m.saveSession(s)
 }
 
 // SaveSession updates an existing session (atomic write)
 func (m *FileState) SaveSession(s *Session) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.saveSession(s)
+}
+
+func (m *FileState) saveSession(s *Session) error {
 	s.Version = CurrentSessionVersion
 	return m.saveJSON("session.json", s)
 }
 
 // LoadSession loads the session, returns ErrNoSession if not found
 func (m *FileState) LoadSession() (*Session, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return m.loadSession()
+}
+
+func (m *FileState) loadSession() (*Session, error) {
 	var s Session
 	if err := m.loadJSON("session.json", &s); err != nil {
 		if os.IsNotExist(err) {
@@ -93,9 +125,14 @@ func (m *FileState) LoadSession() (*Session, error) {
 		return nil, fmt.Errorf("failed to load session: %w", err)
 	}
 
-	// Migrate old versions if needed
-	if s.Version < CurrentSessionVersion {
-		// Future migrations go here
+	// Migrate old versions, saving the upgrade so it only happens once
+	if s.Version != CurrentSessionVersion {
+		if err := m.migrateSession(&s); err != nil {
+			return nil, err
+		}
+		if err := m.saveJSON("session.json", &s); err != nil {
+			return nil, fmt.Errorf("failed to save migrated session: %w", err)
+		}
 	}
 
 	return &s, nil
@@ -103,8 +140,17 @@ func (m *FileState) LoadSession() (*Session, error) {
 
 // ClearSession removes the session file
 func (m *FileState) ClearSession() error {
-	fullPath := filepath.Join(m.stateDir, "session.json")
-	err := os.Remove(fullPath)
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.clearSession()
+}
+
+func (m *FileState) clearSession() error {
+	err := os.Remove(filepath.Join(m.stateDir, "session.json"))
 	if os.IsNotExist(err) {
 		return nil // Already gone
 	}
@@ -145,28 +191,96 @@ func isProcessAlive(pid int) bool {
 
 // SaveWorker saves worker state (atomic write)
 func (m *FileState) SaveWorker(w *WorkerState) error {
-	w.Version = CurrentWorkerVersion
-	workersDir := filepath.Join(m.stateDir, "workers")
-	if err := os.MkdirAll(workersDir, 0755); err != nil {
-		return fmt.Errorf("failed to create workers dir: %w", err)
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.migrate(); err != nil {
+		return err
 	}
+	return m.saveWorker(w)
+}
+
+func (m *FileState) saveWorker(w *WorkerState) error {
+	w.Version = CurrentWorkerVersion
 	return m.saveJSON(filepath.Join("workers", w.Name+".json"), w)
 }
 
 // LoadWorker loads a single worker's state
 func (m *FileState) LoadWorker(name string) (*WorkerState, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := m.migrate(); err != nil {
+		return nil, err
+	}
+	return m.loadWorker(name + ".json")
+}
+
+// loadWorker reads a worker file, upgrading it if it is from an older
+// version
+func (m *FileState) loadWorker(fileName string) (*WorkerState, error) {
+	name := strings.TrimSuffix(fileName, ".json")
+
 	var w WorkerState
-	if err := m.loadJSON(filepath.Join("workers", name+".json"), &w); err != nil {
+	if err := m.loadJSON(filepath.Join("workers", fileName), &w); err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("worker %s not found", name)
+			return nil, fmt.Errorf("worker %s: %w", name, ErrNoWorker)
 		}
 		return nil, fmt.Errorf("failed to load worker %s: %w", name, err)
 	}
+
+	if w.Version != CurrentWorkerVersion {
+		if err := migrateWorker(&w); err != nil {
+			return nil, fmt.Errorf("worker %s: %w", name, err)
+		}
+		if err := m.saveJSON(filepath.Join("workers", fileName), &w); err != nil {
+			return nil, fmt.Errorf("failed to save migrated worker %s: %w", name, err)
+		}
+	}
 	return &w, nil
 }
 
+// UpdateWorker applies fn to a worker's state and saves it, holding the
+// lock throughout. A worker without state starts from an empty one.
+func (m *FileState) UpdateWorker(name string, fn func(w *WorkerState)) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.migrate(); err != nil {
+		return err
+	}
+	w, err := m.loadWorker(name + ".json")
+	if errors.Is(err, ErrNoWorker) {
+		w = &WorkerState{Name: name}
+	} else if err != nil {
+		return err
+	}
+
+	fn(w)
+	return m.saveWorker(w)
+}
+
 // LoadAllWorkers loads all workers, returning partial results on errors
 func (m *FileState) LoadAllWorkers() (*LoadResult, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := m.migrate(); err != nil {
+		return nil, err
+	}
+
 	result := &LoadResult{}
 
 	workersDir := filepath.Join(m.stateDir, "workers")
@@ -182,21 +296,27 @@ func (m *FileState) LoadAllWorkers() (*LoadResult, error) {
 		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
 			continue
 		}
-		var w WorkerState
-		if err := m.loadJSON(filepath.Join("workers", e.Name()), &w); err != nil {
+		w, err := m.loadWorker(e.Name())
+		if err != nil {
 			result.Errors = append(result.Errors,
 				fmt.Errorf("failed to load %s: %w", e.Name(), err))
 			continue
 		}
-		result.Workers = append(result.Workers, &w)
+		result.Workers = append(result.Workers, w)
 	}
 	return result, nil
 }
 
 // DeleteWorker removes a worker's state file
 func (m *FileState) DeleteWorker(name string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	fullPath := filepath.Join(m.stateDir, "workers", name+".json")
-	err := os.Remove(fullPath)
+	err = os.Remove(fullPath)
 	if os.IsNotExist(err) {
 		return nil
 	}
@@ -205,8 +325,14 @@ func (m *FileState) DeleteWorker(name string) error {
 
 // ClearAllWorkers removes all worker state files
 func (m *FileState) ClearAllWorkers() error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	workersDir := filepath.Join(m.stateDir, "workers")
-	err := os.RemoveAll(workersDir)
+	err = os.RemoveAll(workersDir)
 	if os.IsNotExist(err) {
 		return nil
 	}
@@ -215,31 +341,11 @@ func (m *FileState) ClearAllWorkers() error {
 
 // saveJSON writes JSON atomically using temp file + rename
 func (m *FileState) saveJSON(relPath string, v interface{}) error {
-	fullPath := filepath.Join(m.stateDir, relPath)
-
-	// Ensure parent directory exists
-	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal: %w", err)
 	}
-
-	// Write to temp file first
-	tmpPath := fullPath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, fullPath); err != nil {
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to rename: %w", err)
-	}
-
-	return nil
+	return WriteFile(filepath.Join(m.stateDir, relPath), data, 0644)
 }
 
 // loadJSON reads and unmarshals JSON
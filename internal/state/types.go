@@ -5,16 +5,18 @@ import (
 	"time"
 )
 
-// Schema versions - increment when structure changes
+// Schema versions - increment when structure changes and add a step to
+// migrate.go
 const (
-	CurrentSessionVersion = 1
-	CurrentWorkerVersion  = 1
+	CurrentSessionVersion = 2
+	CurrentWorkerVersion  = 2
 )
 
 // Sentinel errors
 var (
 	ErrNoSession     = errors.New("no active session")
 	ErrSessionExists = errors.New("session already exists")
+	ErrNoWorker      = errors.New("no state for worker")
 )
 
 // SessionStatus represents session lifecycle
@@ -32,9 +34,13 @@ type Session struct {
 	Status        SessionStatus `json:"status"`
 	StartedAt     time.Time     `json:"started_at"`
 	PID           int           `json:"pid"`             // Orchestrator process ID
+	Project       string        `json:"project"`
 	ProjectRoot   string        `json:"project_root"`
 	BareRepoPath  string        `json:"bare_repo_path"`
 	ZellijSession string        `json:"zellij_session"`
+	BaseBranch    string        `json:"base_branch,omitempty"`
+	AiryraPort    int           `json:"airyra_port,omitempty"`
+	Workers       []string      `json:"workers,omitempty"` // Workers the session started with
 }
 
 // WorkerStatus represents worker lifecycle states
@@ -64,6 +70,11 @@ type WorkerState struct {
 	ErrorTime     time.Time    `json:"error_time,omitempty"`
 }
 
+// HasTask returns true if the worker is assigned a task
+func (w *WorkerState) HasTask() bool {
+	return w.CurrentTask != nil && w.CurrentTask.ID != ""
+}
+
 // TaskRef is a cache of task info (airyra is source of truth)
 type TaskRef struct {
 	ID        string    `json:"id"`
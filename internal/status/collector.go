@@ -11,6 +11,7 @@ import (
 	"isollm/internal/barerepo"
 	"isollm/internal/config"
 	"isollm/internal/git"
+	"isollm/internal/state"
 	"isollm/internal/worker"
 )
 
@@ -22,6 +23,14 @@ type Collector struct {
 	airyra     airyra.TaskClient
 	bareRepo   *barerepo.BareRepo
 	gitExec    git.Executor
+	state      state.State // Nil to leave out the session
+
+	// Overlap detection merges every pair of active branches, so with an
+	// interval the last result is reused until it has passed
+	overlapInterval time.Duration
+	overlapMu       sync.Mutex
+	overlaps        []BranchOverlap
+	overlapsAt      time.Time
 }
 
 // NewCollector creates a new status collector
@@ -53,6 +62,7 @@ func NewCollector(projectDir string, cfg *config.Config) (*Collector, error) {
 		airyra:     airyraClient,
 		bareRepo:   repo,
 		gitExec:    git.DefaultExecutor,
+		state:      state.New(projectDir),
 	}, nil
 }
 
@@ -61,7 +71,9 @@ func (c *Collector) Collect(ctx context.Context) (*Status, error) {
 	status := &Status{
 		Project:   c.cfg.Project,
 		Timestamp: time.Now(),
+		Session:   c.collectSession(),
 	}
+	status.SessionActive = status.Session != nil && status.Session.Status == string(state.SessionStatusRunning)
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -118,6 +130,23 @@ func (c *Collector) Collect(ctx context.Context) (*Status, error) {
 	return status, nil
 }
 
+// collectSession reads the session recorded by isollm up, or returns nil
+// if there is none
+func (c *Collector) collectSession() *SessionInfo {
+	if c.state == nil {
+		return nil
+	}
+	s, err := c.state.LoadSession()
+	if err != nil {
+		return nil
+	}
+	return &SessionInfo{
+		Status:    string(s.Status),
+		StartedAt: s.StartedAt,
+		Workers:   s.Workers,
+	}
+}
+
 // collectWorkers gathers worker status information
 func (c *Collector) collectWorkers(ctx context.Context) []WorkerStatus {
 	workers, err := c.manager.List()
@@ -142,6 +171,10 @@ func (c *Collector) collectWorkers(ctx context.Context) []WorkerStatus {
 		default:
 			ws.Status = WorkerStatusUnknown
 		}
+		if w.Error != "" {
+			ws.Status = WorkerStatusError
+			ws.Error = w.Error
+		}
 
 		// Add task info if assigned
 		if w.TaskID != "" {
@@ -227,11 +260,60 @@ func (c *Collector) collectSync(ctx context.Context) SyncStatus {
 	if branches, err := c.bareRepo.ListTaskBranches(); err == nil {
 		syncStatus.TaskBranches = branches
 		syncStatus.TotalBranches = len(branches)
+
+		syncStatus.Overlaps = c.findOverlaps(ctx, branches)
 	}
 
 	return syncStatus
 }
 
+// SetOverlapInterval makes Collect look for overlapping branches at most
+// once per interval, reusing the last result in between. Zero looks on
+// every Collect.
+func (c *Collector) SetOverlapInterval(interval time.Duration) {
+	c.overlapInterval = interval
+}
+
+// findOverlaps flags in-progress branches that touch the same files
+func (c *Collector) findOverlaps(ctx context.Context, branches []BranchInfo) []BranchOverlap {
+	c.overlapMu.Lock()
+	defer c.overlapMu.Unlock()
+
+	if c.overlapInterval > 0 && !c.overlapsAt.IsZero() && time.Since(c.overlapsAt) < c.overlapInterval {
+		return c.overlaps
+	}
+
+	active := ActiveBranches(ctx, c.airyra, branches)
+	overlaps, err := c.bareRepo.FindOverlaps(active, c.cfg.Git.BaseBranch)
+	if err != nil {
+		return nil
+	}
+	c.overlaps, c.overlapsAt = overlaps, time.Now()
+	return overlaps
+}
+
+// ActiveBranches returns the names of task branches whose task is in
+// progress. If airyra cannot be reached every branch is returned.
+func ActiveBranches(ctx context.Context, client airyra.TaskClient, branches []BranchInfo) []string {
+	var inProgress map[string]bool
+	if client != nil {
+		if list, err := client.ListTasks(ctx, airyra.WithStatus(airyra.StatusInProgress), airyra.WithPerPage(100)); err == nil {
+			inProgress = make(map[string]bool, len(list.Tasks))
+			for _, task := range list.Tasks {
+				inProgress[task.ID] = task.Status == airyra.StatusInProgress
+			}
+		}
+	}
+
+	var names []string
+	for _, b := range branches {
+		if inProgress == nil || inProgress[b.TaskID] {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
+
 // collectServices gathers service status
 func (c *Collector) collectServices(ctx context.Context) ServiceStatus {
 	services := ServiceStatus{}
@@ -319,6 +401,11 @@ func (c *Collector) GetProjectDir() string {
 	return c.projectDir
 }
 
+// GetManager returns the worker manager (for external use)
+func (c *Collector) GetManager() *worker.Manager {
+	return c.manager
+}
+
 // GetConfig returns the config (for external use)
 func (c *Collector) GetConfig() *config.Config {
 	return c.cfg
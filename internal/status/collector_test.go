@@ -10,6 +10,7 @@ import (
 	"isollm/internal/barerepo"
 	"isollm/internal/config"
 	"isollm/internal/git"
+	"isollm/internal/state"
 	"isollm/internal/worker"
 )
 
@@ -253,6 +254,35 @@ func TestCollectSync(t *testing.T) {
 	})
 }
 
+func TestActiveBranches(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	working, _ := client.AddTask(ctx, "Working")
+	client.ClaimTask(ctx, working.ID)
+	done, _ := client.AddTask(ctx, "Done")
+	client.ClaimTask(ctx, done.ID)
+	client.CompleteTask(ctx, done.ID)
+
+	branches := []BranchInfo{
+		{Name: "isollm/" + working.ID, TaskID: working.ID},
+		{Name: "isollm/" + done.ID, TaskID: done.ID},
+	}
+
+	t.Run("filters to in-progress tasks", func(t *testing.T) {
+		got := ActiveBranches(ctx, client, branches)
+		if len(got) != 1 || got[0] != "isollm/"+working.ID {
+			t.Errorf("ActiveBranches() = %v, want [isollm/%s]", got, working.ID)
+		}
+	})
+
+	t.Run("without airyra keeps every branch", func(t *testing.T) {
+		got := ActiveBranches(ctx, nil, branches)
+		if len(got) != 2 {
+			t.Errorf("ActiveBranches() = %v, want both branches", got)
+		}
+	})
+}
+
 func TestCollectServices(t *testing.T) {
 	ctx := context.Background()
 
@@ -296,6 +326,32 @@ func TestCollectServices(t *testing.T) {
 	})
 }
 
+func TestCollectSession(t *testing.T) {
+	c, _, _ := testCollector(t)
+
+	if c.collectSession() != nil {
+		t.Error("collectSession() without state should be nil")
+	}
+
+	st := state.NewWithDir(t.TempDir())
+	c.state = st
+	if c.collectSession() != nil {
+		t.Error("collectSession() without a session should be nil")
+	}
+
+	st.CreateSession(&state.Session{
+		Status:  state.SessionStatusRunning,
+		Workers: []string{"isollm-worker-1"},
+	})
+	info := c.collectSession()
+	if info == nil {
+		t.Fatal("collectSession() = nil, want the session")
+	}
+	if info.Status != "running" || len(info.Workers) != 1 {
+		t.Errorf("collectSession() = %+v", info)
+	}
+}
+
 func TestCollect_Integration(t *testing.T) {
 	ctx := context.Background()
 
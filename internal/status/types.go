@@ -10,6 +10,7 @@ import (
 type Status struct {
 	Project       string          `json:"project"`
 	SessionActive bool            `json:"session_active"`
+	Session       *SessionInfo    `json:"session,omitempty"`
 	Workers       []WorkerStatus  `json:"workers"`
 	Tasks         TaskSummary     `json:"tasks"`
 	Sync          SyncStatus      `json:"sync"`
@@ -17,6 +18,13 @@ type Status struct {
 	Timestamp     time.Time       `json:"timestamp"`
 }
 
+// SessionInfo is the session recorded by isollm up
+type SessionInfo struct {
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"started_at"`
+	Workers   []string  `json:"workers,omitempty"`
+}
+
 // WorkerStatus represents the status of a single worker
 type WorkerStatus struct {
 	Name       string        `json:"name"`
@@ -26,6 +34,7 @@ type WorkerStatus struct {
 	TaskTitle  string        `json:"task_title,omitempty"`
 	TaskBranch string        `json:"task_branch,omitempty"`
 	Duration   time.Duration `json:"duration,omitempty"`
+	Error      string        `json:"error,omitempty"`
 }
 
 // TaskSummary contains counts of tasks by status
@@ -48,6 +57,7 @@ type SyncStatus struct {
 	HostAhead     int                 `json:"host_ahead"`
 	TaskBranches  []barerepo.BranchInfo `json:"task_branches,omitempty"`
 	TotalBranches int                 `json:"total_branches"`
+	Overlaps      []BranchOverlap     `json:"overlaps,omitempty"`
 }
 
 // ServiceStatus represents the status of external services
@@ -65,9 +75,24 @@ type ServiceInfo struct {
 // BranchInfo re-exports barerepo.BranchInfo for convenience
 type BranchInfo = barerepo.BranchInfo
 
+// BranchOverlap re-exports barerepo.BranchOverlap for convenience
+type BranchOverlap = barerepo.BranchOverlap
+
+// Conflicts returns the overlaps whose trial merge conflicts
+func (s SyncStatus) Conflicts() []BranchOverlap {
+	var conflicts []BranchOverlap
+	for _, o := range s.Overlaps {
+		if o.Conflict {
+			conflicts = append(conflicts, o)
+		}
+	}
+	return conflicts
+}
+
 // WorkerStatusRunning is the status string for a running worker
 const (
 	WorkerStatusRunning = "RUNNING"
 	WorkerStatusStopped = "STOPPED"
 	WorkerStatusUnknown = "UNKNOWN"
+	WorkerStatusError   = "ERROR" // Provisioning failed
 )
@@ -395,6 +395,20 @@ func TestTaskSummary_JSONSerialization(t *testing.T) {
 	}
 }
 
+func TestSyncStatus_Conflicts(t *testing.T) {
+	s := SyncStatus{
+		Overlaps: []BranchOverlap{
+			{BranchA: "isollm/ar-1", BranchB: "isollm/ar-2", Paths: []string{"a.go"}, Conflict: true},
+			{BranchA: "isollm/ar-1", BranchB: "isollm/ar-3", Paths: []string{"b.go"}},
+		},
+	}
+
+	conflicts := s.Conflicts()
+	if len(conflicts) != 1 || conflicts[0].BranchB != "isollm/ar-2" {
+		t.Errorf("Conflicts() = %+v, want only the ar-1/ar-2 pair", conflicts)
+	}
+}
+
 func TestWorkerStatusConstants(t *testing.T) {
 	// Verify constants have expected values
 	if WorkerStatusRunning != "RUNNING" {
@@ -0,0 +1,207 @@
+package taskspec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	sdk "airyra/pkg/airyra"
+
+	"isollm/internal/airyra"
+	"isollm/internal/state"
+)
+
+// LedgerFile is where imported task IDs are kept, under the state directory
+const LedgerFile = "imports.json"
+
+// Ledger maps the external IDs of imported tasks to their airyra task IDs,
+// so importing the same spec again only adds what is new
+type Ledger struct {
+	path  string
+	Tasks map[string]string `json:"tasks"`
+}
+
+// LoadLedger reads the ledger in a state directory. A missing ledger is
+// empty.
+func LoadLedger(stateDir string) (*Ledger, error) {
+	l := &Ledger{path: filepath.Join(stateDir, LedgerFile), Tasks: map[string]string{}}
+
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("invalid import ledger %s: %w", l.path, err)
+	}
+	if l.Tasks == nil {
+		l.Tasks = map[string]string{}
+	}
+	return l, nil
+}
+
+// Save writes the ledger atomically using temp file + rename
+func (l *Ledger) Save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal import ledger: %w", err)
+	}
+
+	if err := state.WriteFile(l.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write import ledger: %w", err)
+	}
+	return nil
+}
+
+// ExternalID identifies a task from a source across imports
+func ExternalID(source, key string) string {
+	return source + "#" + key
+}
+
+// Imported is one task of an import and what happened to it
+type Imported struct {
+	Key        string   `json:"key"`
+	ExternalID string   `json:"external_id"`
+	TaskID     string   `json:"task_id,omitempty"` // Empty for tasks a dry run would create
+	Title      string   `json:"title"`
+	DependsOn  []string `json:"depends_on,omitempty"` // Task IDs, or keys for tasks not yet created
+	Verify     []string `json:"verify,omitempty"`     // Task-specific checks, for the caller to record
+	Existing   bool     `json:"existing"`             // Imported before, left untouched
+}
+
+// Result is the outcome of an import, in dependency order
+type Result struct {
+	Source string     `json:"source"`
+	DryRun bool       `json:"dry_run"`
+	Tasks  []Imported `json:"tasks"`
+}
+
+// Created returns the number of tasks added (or, for a dry run, that would be)
+func (r *Result) Created() int {
+	n := 0
+	for _, t := range r.Tasks {
+		if !t.Existing {
+			n++
+		}
+	}
+	return n
+}
+
+// Import adds a spec's tasks and their dependencies to airyra. Tasks the
+// ledger records from an earlier import of the same source are skipped;
+// dependencies are only added for new tasks. If any step fails, the tasks
+// created so far are deleted so the queue is left as it was. With dryRun
+// nothing is changed. A nil ledger imports every task and records nothing.
+//
+// Each task is ready from when it is added until its dependencies are, and
+// tasks without dependencies stay ready; a dispatcher running meanwhile can
+// claim them, and a rollback deletes them regardless.
+func Import(ctx context.Context, client airyra.TaskClient, ledger *Ledger, source string, spec *Spec, dryRun bool) (*Result, error) {
+	ordered, err := spec.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Source: source, DryRun: dryRun}
+	ids := make(map[string]string, len(ordered)) // Key to task ID
+
+	for _, t := range ordered {
+		if ledger == nil {
+			break // Nothing imported before
+		}
+		id, ok := ledger.Tasks[ExternalID(source, t.Key)]
+		if !ok {
+			continue
+		}
+		if _, err := client.GetTask(ctx, id); err != nil {
+			if airyra.IsTaskNotFound(err) {
+				continue // Deleted since, import it again
+			}
+			return nil, fmt.Errorf("failed to check task %s for %q: %w", id, t.Key, err)
+		}
+		ids[t.Key] = id
+	}
+
+	var created []string
+	rollback := func(cause error) error {
+		var errs []error
+		for i := len(created) - 1; i >= 0; i-- {
+			if err := client.DeleteTask(ctx, created[i]); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete %s: %w", created[i], err))
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%w; rollback incomplete: %w", cause, errors.Join(errs...))
+		}
+		return fmt.Errorf("%w (rolled back %d tasks)", cause, len(created))
+	}
+
+	for _, t := range ordered {
+		imported := Imported{Key: t.Key, ExternalID: ExternalID(source, t.Key), Title: t.Title, Verify: t.Verify}
+
+		if id, ok := ids[t.Key]; ok {
+			imported.TaskID = id
+			imported.Existing = true
+			result.Tasks = append(result.Tasks, imported)
+			continue
+		}
+
+		for _, dep := range t.DependsOn {
+			if id, ok := ids[dep]; ok {
+				imported.DependsOn = append(imported.DependsOn, id)
+			} else {
+				imported.DependsOn = append(imported.DependsOn, dep)
+			}
+		}
+
+		if dryRun {
+			result.Tasks = append(result.Tasks, imported)
+			continue
+		}
+
+		priority, _ := airyra.PriorityFromString(t.Priority)
+		opts := []sdk.CreateTaskOption{sdk.WithPriority(priority)}
+		if body := t.body(); body != "" {
+			opts = append(opts, sdk.WithDescription(body))
+		}
+		if spec.Parent != "" {
+			opts = append(opts, sdk.WithParentID(spec.Parent))
+		}
+
+		task, err := client.AddTask(ctx, t.Title, opts...)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("failed to add task %q: %w", t.Key, err))
+		}
+		created = append(created, task.ID)
+		ids[t.Key] = task.ID
+		imported.TaskID = task.ID
+
+		for _, parentID := range imported.DependsOn {
+			if err := client.AddDependency(ctx, task.ID, parentID); err != nil {
+				return nil, rollback(fmt.Errorf("failed to add dependency of %q on %s: %w", t.Key, parentID, err))
+			}
+		}
+
+		result.Tasks = append(result.Tasks, imported)
+	}
+
+	if dryRun || ledger == nil || len(created) == 0 {
+		return result, nil
+	}
+
+	prev := maps.Clone(ledger.Tasks)
+	for _, t := range result.Tasks {
+		ledger.Tasks[t.ExternalID] = t.TaskID
+	}
+	if err := ledger.Save(); err != nil {
+		ledger.Tasks = prev
+		return nil, rollback(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package taskspec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	sdk "airyra/pkg/airyra"
+
+	"isollm/internal/airyra"
+)
+
+func testSpec() *Spec {
+	spec := &Spec{Tasks: []Task{
+		{Key: "deploy", Title: "Deploy", DependsOn: []string{"build"}},
+		{Key: "build", Title: "Build", Priority: "high"},
+	}}
+	spec.Validate()
+	return spec
+}
+
+func TestImport(t *testing.T) {
+	mock := airyra.NewMockClient()
+	dir := t.TempDir()
+	ledger, _ := LoadLedger(dir)
+	ctx := context.Background()
+
+	result, err := Import(ctx, mock, ledger, "plan.md", testSpec(), false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Created() != 2 || mock.GetTaskCount() != 2 {
+		t.Fatalf("Import() created %d tasks (%d in queue), want 2", result.Created(), mock.GetTaskCount())
+	}
+
+	build, deploy := result.Tasks[0], result.Tasks[1]
+	if build.Key != "build" || deploy.Key != "deploy" {
+		t.Fatalf("Import() order = %s, %s, want build before deploy", build.Key, deploy.Key)
+	}
+	deps, _ := mock.ListDependencies(ctx, deploy.TaskID)
+	if len(deps) != 1 || deps[0].ParentID != build.TaskID {
+		t.Errorf("deploy dependencies = %+v, want one on %s", deps, build.TaskID)
+	}
+
+	reloaded, err := LoadLedger(dir)
+	if err != nil {
+		t.Fatalf("LoadLedger() error = %v", err)
+	}
+	if got := reloaded.Tasks[ExternalID("plan.md", "deploy")]; got != deploy.TaskID {
+		t.Errorf("ledger[deploy] = %q, want %q", got, deploy.TaskID)
+	}
+}
+
+func TestImport_Reimport(t *testing.T) {
+	mock := airyra.NewMockClient()
+	ledger, _ := LoadLedger(t.TempDir())
+	ctx := context.Background()
+
+	first, _ := Import(ctx, mock, ledger, "plan.md", testSpec(), false)
+
+	spec := testSpec()
+	spec.Tasks = append(spec.Tasks, Task{Key: "announce", Title: "Announce", DependsOn: []string{"deploy"}})
+	second, err := Import(ctx, mock, ledger, "plan.md", spec, false)
+	if err != nil {
+		t.Fatalf("Import() again error = %v", err)
+	}
+	if second.Created() != 1 || mock.GetTaskCount() != 3 {
+		t.Fatalf("re-import created %d tasks (%d in queue), want 1 (3)", second.Created(), mock.GetTaskCount())
+	}
+
+	announce := second.Tasks[2]
+	if announce.Existing || len(announce.DependsOn) != 1 || announce.DependsOn[0] != first.Tasks[1].TaskID {
+		t.Errorf("announce = %+v, want new and depending on %s", announce, first.Tasks[1].TaskID)
+	}
+}
+
+func TestImport_ReimportDeletedTask(t *testing.T) {
+	mock := airyra.NewMockClient()
+	ledger, _ := LoadLedger(t.TempDir())
+	ctx := context.Background()
+
+	first, _ := Import(ctx, mock, ledger, "plan.md", testSpec(), false)
+	mock.DeleteTask(ctx, first.Tasks[0].TaskID)
+
+	second, err := Import(ctx, mock, ledger, "plan.md", testSpec(), false)
+	if err != nil {
+		t.Fatalf("Import() again error = %v", err)
+	}
+	if second.Created() != 1 || second.Tasks[0].Existing {
+		t.Errorf("re-import = %+v, want the deleted task recreated", second.Tasks)
+	}
+}
+
+func TestImport_DryRun(t *testing.T) {
+	mock := airyra.NewMockClient()
+	dir := t.TempDir()
+	ledger, _ := LoadLedger(dir)
+
+	result, err := Import(context.Background(), mock, ledger, "plan.md", testSpec(), true)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Created() != 2 || !result.DryRun {
+		t.Errorf("Import() = %+v, want a dry run of 2 tasks", result)
+	}
+	if mock.GetTaskCount() != 0 {
+		t.Errorf("dry run added %d tasks", mock.GetTaskCount())
+	}
+	if reloaded, _ := LoadLedger(dir); len(reloaded.Tasks) != 0 {
+		t.Errorf("dry run wrote ledger %+v", reloaded.Tasks)
+	}
+}
+
+func TestImport_NoLedger(t *testing.T) {
+	mock := airyra.NewMockClient()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := Import(ctx, mock, nil, "plan:ar-1", testSpec(), false)
+		if err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+		if result.Created() != 2 {
+			t.Errorf("Import() #%d created %d tasks, want 2", i+1, result.Created())
+		}
+	}
+	if mock.GetTaskCount() != 4 {
+		t.Errorf("task count = %d, want every import to add its tasks", mock.GetTaskCount())
+	}
+}
+
+func TestImport_RollsBackOnFailure(t *testing.T) {
+	mock := airyra.NewMockClient()
+	dir := t.TempDir()
+	ledger, _ := LoadLedger(dir)
+
+	calls := 0
+	mock.OnAddTask = func(ctx context.Context, title string, opts ...sdk.CreateTaskOption) (*airyra.Task, error) {
+		calls++
+		if calls == 2 {
+			return nil, errors.New("server went away")
+		}
+		task := &airyra.Task{ID: fmt.Sprintf("ar-%04d", calls), Title: title, Status: airyra.StatusOpen}
+		mock.AddTaskDirect(task)
+		return task, nil
+	}
+
+	if _, err := Import(context.Background(), mock, ledger, "plan.md", testSpec(), false); err == nil {
+		t.Fatal("Import() = nil, want error")
+	}
+	if mock.GetTaskCount() != 0 {
+		t.Errorf("%d tasks left after a failed import, want 0", mock.GetTaskCount())
+	}
+	if reloaded, _ := LoadLedger(dir); len(reloaded.Tasks) != 0 {
+		t.Errorf("failed import wrote ledger %+v", reloaded.Tasks)
+	}
+}
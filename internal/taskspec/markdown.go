@@ -0,0 +1,86 @@
+package taskspec
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// checkboxItem matches "- [ ] title" or "* [x] title" with its indent
+var checkboxItem = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.+)$`)
+
+// ParseMarkdown reads a checklist. Each unchecked "- [ ]" item is a task;
+// items nested under it are tasks it depends on. Indented text below an
+// item that is not a list item becomes its description. Checked items and
+// everything nested under them are skipped.
+func ParseMarkdown(data []byte) (*Spec, error) {
+	type open struct {
+		indent int
+		index  int // Into spec.Tasks, -1 for a skipped item
+		key    string
+	}
+
+	var (
+		spec  Spec
+		stack []open
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.ReplaceAll(scanner.Text(), "\t", "    ")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		m := checkboxItem.FindStringSubmatch(line)
+		if m == nil {
+			// Description text for the innermost item it is indented under
+			if len(stack) > 0 && indent > stack[len(stack)-1].indent {
+				if top := stack[len(stack)-1]; top.index >= 0 {
+					t := &spec.Tasks[top.index]
+					if t.Description != "" {
+						t.Description += "\n"
+					}
+					t.Description += strings.TrimSpace(line)
+				}
+				continue
+			}
+			stack = nil
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		var parent *open
+		if len(stack) > 0 {
+			parent = &stack[len(stack)-1]
+		}
+
+		if m[2] != " " || (parent != nil && parent.index < 0) {
+			stack = append(stack, open{indent: indent, index: -1})
+			continue
+		}
+
+		title := strings.TrimSpace(m[3])
+		key := Slug(title)
+		if parent != nil {
+			key = parent.key + "/" + key
+		}
+
+		spec.Tasks = append(spec.Tasks, Task{Key: key, Title: title})
+		if parent != nil {
+			p := &spec.Tasks[parent.index]
+			p.DependsOn = append(p.DependsOn, key)
+		}
+		stack = append(stack, open{indent: indent, index: len(spec.Tasks) - 1, key: key})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
@@ -0,0 +1,63 @@
+package taskspec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	input := `# Release plan
+
+- [ ] Ship the release
+  Cut the tag once everything below is in.
+  - [ ] Write changelog
+  - [ ] Fix flaky tests
+    - [ ] Stub the clock
+  - [x] Bump version
+    - [ ] Skipped with its parent
+- [ ] Announce it
+* [ ] Update the docs
+
+Some closing prose.
+`
+
+	spec, err := ParseMarkdown([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+
+	want := []Task{
+		{
+			Key:         "ship-the-release",
+			Title:       "Ship the release",
+			Description: "Cut the tag once everything below is in.",
+			DependsOn:   []string{"ship-the-release/write-changelog", "ship-the-release/fix-flaky-tests"},
+		},
+		{Key: "ship-the-release/write-changelog", Title: "Write changelog"},
+		{
+			Key:       "ship-the-release/fix-flaky-tests",
+			Title:     "Fix flaky tests",
+			DependsOn: []string{"ship-the-release/fix-flaky-tests/stub-the-clock"},
+		},
+		{Key: "ship-the-release/fix-flaky-tests/stub-the-clock", Title: "Stub the clock"},
+		{Key: "announce-it", Title: "Announce it"},
+		{Key: "update-the-docs", Title: "Update the docs"},
+	}
+
+	if !reflect.DeepEqual(spec.Tasks, want) {
+		t.Errorf("ParseMarkdown() =\n%+v\nwant\n%+v", spec.Tasks, want)
+	}
+	if err := spec.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestParseMarkdown_NoChecklist(t *testing.T) {
+	spec, err := ParseMarkdown([]byte("# Notes\n\n- a plain bullet\n"))
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+	if len(spec.Tasks) != 0 {
+		t.Errorf("ParseMarkdown() = %+v, want no tasks", spec.Tasks)
+	}
+}
@@ -0,0 +1,195 @@
+package taskspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"isollm/internal/airyra"
+)
+
+// Task is one task in a spec. Key identifies it within the spec and, for
+// re-imports, across imports; it defaults to a slug of the title.
+type Task struct {
+	Key         string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Title       string   `yaml:"title" json:"title"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Priority    string   `yaml:"priority,omitempty" json:"priority,omitempty"` // critical, high, normal, low, lowest
+	Labels      []string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DependsOn   []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"` // Keys of other tasks in the spec
+	Verify      []string `yaml:"verify,omitempty" json:"verify,omitempty"`         // Checks run after verify.commands before the task is done
+}
+
+// Spec is a set of tasks to add to the queue together
+type Spec struct {
+	Tasks  []Task `yaml:"tasks" json:"tasks"`
+	Parent string `yaml:"-" json:"-"` // Task ID set as the parent of every imported task
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug turns a title into a key: lower case words joined by dashes
+func Slug(title string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+// Load reads a spec from a markdown checklist (.md), YAML (.yaml, .yml) or
+// JSON (.json) file
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var spec *Spec
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".md", ".markdown":
+		spec, err = ParseMarkdown(data)
+	case ".yaml", ".yml":
+		spec, err = ParseYAML(data)
+	case ".json":
+		spec, err = ParseJSON(data)
+	default:
+		return nil, fmt.Errorf("unsupported task file %s: want .md, .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid task file %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// ParseYAML reads a spec with a top-level tasks: list, or a bare list
+func ParseYAML(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		var tasks []Task
+		if yaml.Unmarshal(data, &tasks) != nil {
+			return nil, err
+		}
+		spec.Tasks = tasks
+	}
+	return &spec, nil
+}
+
+// ParseJSON reads a spec with a top-level "tasks" array, or a bare array
+func ParseJSON(data []byte) (*Spec, error) {
+	var spec Spec
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		if err := json.Unmarshal(data, &spec.Tasks); err != nil {
+			return nil, err
+		}
+		return &spec, nil
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate fills in default keys and checks that keys are unique,
+// priorities are known and dependencies exist without forming a cycle
+func (s *Spec) Validate() error {
+	if len(s.Tasks) == 0 {
+		return fmt.Errorf("no tasks")
+	}
+
+	keys := make(map[string]bool, len(s.Tasks))
+	for i := range s.Tasks {
+		t := &s.Tasks[i]
+		if strings.TrimSpace(t.Title) == "" {
+			return fmt.Errorf("task %d has no title", i+1)
+		}
+		if t.Key == "" {
+			t.Key = Slug(t.Title)
+		}
+		if keys[t.Key] {
+			return fmt.Errorf("duplicate task id %q (give tasks with the same title an id)", t.Key)
+		}
+		keys[t.Key] = true
+		if _, err := airyra.PriorityFromString(t.Priority); err != nil {
+			return fmt.Errorf("task %q: %w", t.Key, err)
+		}
+		for _, cmd := range t.Verify {
+			if strings.TrimSpace(cmd) == "" {
+				return fmt.Errorf("task %q has an empty verify command", t.Key)
+			}
+		}
+	}
+
+	for _, t := range s.Tasks {
+		for _, dep := range t.DependsOn {
+			if !keys[dep] {
+				return fmt.Errorf("task %q depends on unknown task %q", t.Key, dep)
+			}
+			if dep == t.Key {
+				return fmt.Errorf("task %q depends on itself", t.Key)
+			}
+		}
+	}
+
+	_, err := s.Order()
+	return err
+}
+
+// Order returns the tasks with every task after the tasks it depends on
+func (s *Spec) Order() ([]Task, error) {
+	byKey := make(map[string]Task, len(s.Tasks))
+	for _, t := range s.Tasks {
+		byKey[t.Key] = t
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(s.Tasks))
+	ordered := make([]Task, 0, len(s.Tasks))
+
+	var visit func(key string, path []string) error
+	visit = func(key string, path []string) error {
+		switch state[key] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, key), " -> "))
+		}
+		state[key] = visiting
+		for _, dep := range byKey[key].DependsOn {
+			if err := visit(dep, append(path, key)); err != nil {
+				return err
+			}
+		}
+		state[key] = done
+		ordered = append(ordered, byKey[key])
+		return nil
+	}
+
+	for _, t := range s.Tasks {
+		if err := visit(t.Key, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// body returns the description sent to airyra, which has no labels, so
+// they are listed at the end
+func (t Task) body() string {
+	if len(t.Labels) == 0 {
+		return t.Description
+	}
+	labels := "Labels: " + strings.Join(t.Labels, ", ")
+	if t.Description == "" {
+		return labels
+	}
+	return strings.TrimRight(t.Description, "\n") + "\n\n" + labels
+}
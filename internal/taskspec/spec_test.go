@@ -0,0 +1,140 @@
+package taskspec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Add login endpoint", "add-login-endpoint"},
+		{"  Fix: the `cache` (again)!", "fix-the-cache-again"},
+		{"v2 API", "v2-api"},
+	}
+
+	for _, tt := range tests {
+		if got := Slug(tt.title); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	yamlSpec := `tasks:
+  - id: schema
+    title: Design the schema
+    priority: high
+  - title: Write migrations
+    labels: [db]
+    depends_on: [schema]
+`
+	jsonSpec := `[
+  {"id": "schema", "title": "Design the schema", "priority": "high"},
+  {"title": "Write migrations", "labels": ["db"], "depends_on": ["schema"]}
+]`
+
+	for name, content := range map[string]string{"tasks.yaml": yamlSpec, "tasks.json": jsonSpec} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			os.WriteFile(path, []byte(content), 0644)
+
+			spec, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(spec.Tasks) != 2 {
+				t.Fatalf("Load() = %d tasks, want 2", len(spec.Tasks))
+			}
+			second := spec.Tasks[1]
+			if second.Key != "write-migrations" {
+				t.Errorf("Key = %q, want the slugged title", second.Key)
+			}
+			if len(second.DependsOn) != 1 || second.DependsOn[0] != "schema" {
+				t.Errorf("DependsOn = %v, want [schema]", second.DependsOn)
+			}
+			if len(second.Labels) != 1 || second.Labels[0] != "db" {
+				t.Errorf("Labels = %v, want [db]", second.Labels)
+			}
+		})
+	}
+}
+
+func TestLoad_UnsupportedExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.txt")
+	os.WriteFile(path, []byte("- [ ] Task"), 0644)
+
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("Load() = %v, want unsupported file error", err)
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   []Task
+		wantErr string
+	}{
+		{"empty", nil, "no tasks"},
+		{"no title", []Task{{Key: "a"}}, "no title"},
+		{"duplicate", []Task{{Title: "Same"}, {Title: "Same"}}, "duplicate task id"},
+		{"bad priority", []Task{{Title: "A", Priority: "urgent"}}, "invalid priority"},
+		{"unknown dep", []Task{{Title: "A", DependsOn: []string{"b"}}}, "unknown task"},
+		{"self dep", []Task{{Key: "a", Title: "A", DependsOn: []string{"a"}}}, "depends on itself"},
+		{"cycle", []Task{
+			{Key: "a", Title: "A", DependsOn: []string{"b"}},
+			{Key: "b", Title: "B", DependsOn: []string{"a"}},
+		}, "cycle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &Spec{Tasks: tt.tasks}
+			if err := spec.Validate(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpec_Order(t *testing.T) {
+	spec := &Spec{Tasks: []Task{
+		{Key: "deploy", Title: "Deploy", DependsOn: []string{"build", "test"}},
+		{Key: "test", Title: "Test", DependsOn: []string{"build"}},
+		{Key: "build", Title: "Build"},
+	}}
+
+	ordered, err := spec.Order()
+	if err != nil {
+		t.Fatalf("Order() error = %v", err)
+	}
+
+	var keys []string
+	for _, task := range ordered {
+		keys = append(keys, task.Key)
+	}
+	if got := strings.Join(keys, ","); got != "build,test,deploy" {
+		t.Errorf("Order() = %s, want build,test,deploy", got)
+	}
+}
+
+func TestTask_Body(t *testing.T) {
+	tests := []struct {
+		task Task
+		want string
+	}{
+		{Task{Description: "Details"}, "Details"},
+		{Task{Labels: []string{"db", "api"}}, "Labels: db, api"},
+		{Task{Description: "Details\n", Labels: []string{"db"}}, "Details\n\nLabels: db"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.task.body(); got != tt.want {
+			t.Errorf("body() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"isollm/internal/config"
+	"isollm/internal/taskspec"
+)
+
+// MaxTasks caps how many tasks one expansion may produce, so a loose glob
+// does not flood the queue
+const MaxTasks = 200
+
+// Get returns a template from the config by name
+func Get(cfg *config.Config, name string) (config.TemplateConfig, error) {
+	t, ok := cfg.Templates[name]
+	if ok {
+		return t, nil
+	}
+	if len(cfg.Templates) == 0 {
+		return t, fmt.Errorf("unknown template %q: isollm.yaml has no templates", name)
+	}
+	return t, fmt.Errorf("unknown template %q (have: %s)", name, strings.Join(Names(cfg), ", "))
+}
+
+// Names returns the names of the config's templates, sorted
+func Names(cfg *config.Config) []string {
+	return slices.Sorted(maps.Keys(cfg.Templates))
+}
+
+// ParseSets parses key=value arguments
+func ParseSets(args []string) (map[string]string, error) {
+	sets := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid parameter %q, expected key=value", arg)
+		}
+		sets[key] = value
+	}
+	return sets, nil
+}
+
+// Values resolves an each expression: a glob matched relative to root,
+// such as internal/*, or a comma separated list
+func Values(root, expr string) ([]string, error) {
+	if !strings.ContainsAny(expr, "*?[") {
+		var values []string
+		for _, v := range strings.Split(expr, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(root, expr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", expr, err)
+	}
+
+	values := make([]string, 0, len(matches))
+	for _, match := range matches {
+		rel, err := filepath.Rel(root, match)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(filepath.Base(rel), ".") {
+			continue
+		}
+		if strings.HasSuffix(expr, "/") {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() {
+				continue
+			}
+		}
+		values = append(values, filepath.ToSlash(rel))
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+// Expand renders a template into a spec with one task per combination of
+// its each values. sets override the template's defaults and take a
+// parameter out of each; each adds or replaces expressions from the
+// template. Globs are matched relative to root.
+func Expand(name string, t config.TemplateConfig, sets, each map[string]string, root string) (*taskspec.Spec, error) {
+	params := maps.Clone(t.Params)
+	if params == nil {
+		params = map[string]string{}
+	}
+	maps.Copy(params, sets)
+
+	exprs := maps.Clone(t.Each)
+	if exprs == nil {
+		exprs = map[string]string{}
+	}
+	for key := range sets {
+		delete(exprs, key)
+	}
+	maps.Copy(exprs, each)
+
+	combos := []map[string]string{params}
+	for _, key := range slices.Sorted(maps.Keys(exprs)) {
+		values, err := Values(root, exprs[key])
+		if err != nil {
+			return nil, fmt.Errorf("template %s: each %s: %w", name, key, err)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("template %s: each %s: %q matched nothing", name, key, exprs[key])
+		}
+		if len(combos)*len(values) > MaxTasks {
+			return nil, fmt.Errorf("template %s expands to more than %d tasks", name, MaxTasks)
+		}
+
+		var next []map[string]string
+		for _, combo := range combos {
+			for _, value := range values {
+				c := maps.Clone(combo)
+				c[key] = value
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+
+	spec := &taskspec.Spec{}
+	for _, combo := range combos {
+		task, err := render(t, combo)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+		spec.Tasks = append(spec.Tasks, task)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return spec, nil
+}
+
+// render fills in a template for one set of parameters
+func render(t config.TemplateConfig, params map[string]string) (taskspec.Task, error) {
+	task := taskspec.Task{Priority: t.Priority}
+
+	var err error
+	if task.Title, err = execute("title", t.Title, params); err != nil {
+		return task, err
+	}
+	if task.Description, err = execute("description", t.Description, params); err != nil {
+		return task, err
+	}
+	for i, cmd := range t.Verify {
+		rendered, err := execute(fmt.Sprintf("verify[%d]", i), cmd, params)
+		if err != nil {
+			return task, err
+		}
+		task.Verify = append(task.Verify, rendered)
+	}
+	return task, nil
+}
+
+// execute renders one field. A parameter without a value is an error.
+func execute(field, text string, params map[string]string) (string, error) {
+	tmpl, err := template.New(field).Funcs(config.TemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", field, err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, params); err != nil {
+		return "", fmt.Errorf("%s: %w (set it with --set key=value)", field, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
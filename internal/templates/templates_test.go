@@ -0,0 +1,192 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"isollm/internal/config"
+)
+
+func lintTemplate() config.TemplateConfig {
+	return config.TemplateConfig{
+		Title:       "Fix lint in {{.pkg}}",
+		Description: "Run golangci-lint on ./{{.pkg}}/... and fix every finding in {{base .pkg}}.",
+		Priority:    "low",
+		Verify:      []string{"golangci-lint run ./{{.pkg}}/..."},
+	}
+}
+
+func projectTree(t *testing.T, paths ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, p := range paths {
+		full := filepath.Join(root, p)
+		if strings.HasSuffix(p, "/") {
+			os.MkdirAll(full, 0755)
+			continue
+		}
+		os.MkdirAll(filepath.Dir(full), 0755)
+		os.WriteFile(full, nil, 0644)
+	}
+	return root
+}
+
+func TestExpand_Set(t *testing.T) {
+	spec, err := Expand("lint", lintTemplate(), map[string]string{"pkg": "internal/cache"}, nil, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if len(spec.Tasks) != 1 {
+		t.Fatalf("Expand() = %d tasks, want 1", len(spec.Tasks))
+	}
+
+	task := spec.Tasks[0]
+	if task.Title != "Fix lint in internal/cache" {
+		t.Errorf("Title = %q", task.Title)
+	}
+	if task.Description != "Run golangci-lint on ./internal/cache/... and fix every finding in cache." {
+		t.Errorf("Description = %q", task.Description)
+	}
+	if task.Priority != "low" {
+		t.Errorf("Priority = %q, want low", task.Priority)
+	}
+	if want := []string{"golangci-lint run ./internal/cache/..."}; !reflect.DeepEqual(task.Verify, want) {
+		t.Errorf("Verify = %v, want %v", task.Verify, want)
+	}
+}
+
+func TestExpand_MissingParameter(t *testing.T) {
+	_, err := Expand("lint", lintTemplate(), nil, nil, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "--set") {
+		t.Errorf("Expand() without pkg error = %v, want a hint to --set it", err)
+	}
+}
+
+func TestExpand_Defaults(t *testing.T) {
+	tmpl := config.TemplateConfig{
+		Title:  "Add tests for {{.pkg}} ({{.kind}})",
+		Params: map[string]string{"kind": "unit"},
+	}
+
+	spec, err := Expand("tests", tmpl, map[string]string{"pkg": "api"}, nil, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if got := spec.Tasks[0].Title; got != "Add tests for api (unit)" {
+		t.Errorf("Title = %q", got)
+	}
+
+	spec, _ = Expand("tests", tmpl, map[string]string{"pkg": "api", "kind": "fuzz"}, nil, t.TempDir())
+	if got := spec.Tasks[0].Title; got != "Add tests for api (fuzz)" {
+		t.Errorf("Title with --set kind = %q", got)
+	}
+}
+
+func TestExpand_EachGlob(t *testing.T) {
+	root := projectTree(t, "internal/api/", "internal/cache/", "internal/.hidden/", "internal/doc.go")
+
+	spec, err := Expand("lint", lintTemplate(), nil, map[string]string{"pkg": "internal/*/"}, root)
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+
+	var titles []string
+	for _, task := range spec.Tasks {
+		titles = append(titles, task.Title)
+	}
+	want := []string{"Fix lint in internal/api", "Fix lint in internal/cache"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("titles = %v, want %v", titles, want)
+	}
+}
+
+func TestExpand_TemplateEach(t *testing.T) {
+	tmpl := lintTemplate()
+	tmpl.Each = map[string]string{"pkg": "api, cache"}
+
+	spec, err := Expand("lint", tmpl, nil, nil, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if len(spec.Tasks) != 2 {
+		t.Errorf("Expand() = %d tasks, want one per each value", len(spec.Tasks))
+	}
+
+	// --set takes the parameter out of each
+	spec, err = Expand("lint", tmpl, map[string]string{"pkg": "web"}, nil, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if len(spec.Tasks) != 1 || spec.Tasks[0].Title != "Fix lint in web" {
+		t.Errorf("Expand() with --set = %+v, want one task for web", spec.Tasks)
+	}
+}
+
+func TestExpand_Product(t *testing.T) {
+	tmpl := config.TemplateConfig{Title: "Port {{.pkg}} to {{.os}}"}
+	each := map[string]string{"pkg": "api,cache", "os": "linux,darwin,windows"}
+
+	spec, err := Expand("port", tmpl, nil, each, t.TempDir())
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if len(spec.Tasks) != 6 {
+		t.Errorf("Expand() = %d tasks, want 6", len(spec.Tasks))
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	root := projectTree(t, "internal/api/")
+
+	tests := []struct {
+		name string
+		tmpl config.TemplateConfig
+		each map[string]string
+	}{
+		{"glob matches nothing", lintTemplate(), map[string]string{"pkg": "cmd/*"}},
+		{"duplicate titles", config.TemplateConfig{Title: "Same for all"}, map[string]string{"pkg": "a,b"}},
+		{"too many tasks", config.TemplateConfig{Title: "{{.a}} {{.b}}"}, map[string]string{
+			"a": strings.Repeat("x,", 20), "b": strings.Repeat("y,", 20),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Expand("t", tt.tmpl, nil, tt.each, root); err == nil {
+				t.Error("Expand() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseSets(t *testing.T) {
+	sets, err := ParseSets([]string{"pkg=internal/api", "note=a=b"})
+	if err != nil {
+		t.Fatalf("ParseSets() error = %v", err)
+	}
+	if sets["pkg"] != "internal/api" || sets["note"] != "a=b" {
+		t.Errorf("ParseSets() = %v", sets)
+	}
+
+	if _, err := ParseSets([]string{"pkg"}); err == nil {
+		t.Error("ParseSets() without = error = nil, want error")
+	}
+}
+
+func TestGet(t *testing.T) {
+	cfg := &config.Config{Templates: map[string]config.TemplateConfig{
+		"lint":  lintTemplate(),
+		"tests": {Title: "Add tests for {{.pkg}}"},
+	}}
+
+	if _, err := Get(cfg, "lint"); err != nil {
+		t.Errorf("Get(lint) error = %v", err)
+	}
+	_, err := Get(cfg, "docs")
+	if err == nil || !strings.Contains(err.Error(), "lint, tests") {
+		t.Errorf("Get(docs) error = %v, want the available templates", err)
+	}
+}
@@ -0,0 +1,74 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"isollm/internal/state"
+)
+
+// Store keeps the checks particular to a task, run after the project's
+// verify commands, as one JSON file per task
+type Store struct {
+	dir string
+}
+
+// NewStore creates a Store for the project's .isollm directory
+func NewStore(projectRoot string) *Store {
+	return &Store{dir: filepath.Join(projectRoot, ".isollm", "verify")}
+}
+
+// NewStoreWithDir creates a Store with a custom directory (for testing)
+func NewStoreWithDir(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Set records the checks for a task. No commands removes them.
+func (s *Store) Set(taskID string, commands []string) error {
+	if len(commands) == 0 {
+		return s.Delete(taskID)
+	}
+
+	data, err := json.MarshalIndent(commands, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal verify commands: %w", err)
+	}
+
+	if err := state.WriteFile(s.path(taskID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write verify commands for %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// Get returns the checks for a task, or nil if it has none
+func (s *Store) Get(taskID string) ([]string, error) {
+	data, err := os.ReadFile(s.path(taskID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read verify commands for %s: %w", taskID, err)
+	}
+
+	var commands []string
+	if err := json.Unmarshal(data, &commands); err != nil {
+		return nil, fmt.Errorf("invalid verify commands for %s: %w", taskID, err)
+	}
+	return commands, nil
+}
+
+// Delete removes the checks for a task
+func (s *Store) Delete(taskID string) error {
+	err := os.Remove(s.path(taskID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// path returns the file for a task
+func (s *Store) path(taskID string) string {
+	return filepath.Join(s.dir, taskID+".json")
+}
@@ -0,0 +1,157 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"isollm/internal/airyra"
+	"isollm/internal/notes"
+)
+
+// maxNoteOutput caps how much command output is attached to a task
+const maxNoteOutput = 8 * 1024
+
+// ContainerExecer interface for executing commands in containers
+type ContainerExecer interface {
+	Exec(name string, cmd []string) ([]byte, error)
+}
+
+// Result is the outcome of one verification command
+type Result struct {
+	Command string
+	Output  string
+	Err     error
+}
+
+// Report collects the results of verifying a task branch.
+// Commands run in order and stop at the first failure.
+type Report struct {
+	Worker  string
+	Branch  string
+	Results []Result
+}
+
+// Passed returns true if every command succeeded
+func (r *Report) Passed() bool {
+	return r.Failed() == nil
+}
+
+// Failed returns the failing command, or nil
+func (r *Report) Failed() *Result {
+	for i := range r.Results {
+		if r.Results[i].Err != nil {
+			return &r.Results[i]
+		}
+	}
+	return nil
+}
+
+// Summary returns a human-readable description of the failure
+func (r *Report) Summary() string {
+	failed := r.Failed()
+	if failed == nil {
+		return fmt.Sprintf("Verification passed on %s", r.Branch)
+	}
+
+	output := strings.TrimSpace(failed.Output)
+	if len(output) > maxNoteOutput {
+		output = "...\n" + output[len(output)-maxNoteOutput:]
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Verification failed on %s (%s): `%s` (%v)\n", r.Branch, r.Worker, failed.Command, failed.Err))
+	if output != "" {
+		b.WriteString("\n")
+		b.WriteString(output)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// FailedError is returned when a task does not pass verification
+type FailedError struct {
+	TaskID string
+	Report *Report
+}
+
+func (e *FailedError) Error() string {
+	failed := e.Report.Failed()
+	return fmt.Sprintf("task %s failed verification: %s: %v", e.TaskID, failed.Command, failed.Err)
+}
+
+// Runner executes verification commands inside a worker container
+type Runner struct {
+	execer   ContainerExecer
+	dir      string
+	commands []string
+}
+
+// New creates a Runner that runs commands in dir inside the container
+func New(execer ContainerExecer, dir string, commands []string) *Runner {
+	return &Runner{
+		execer:   execer,
+		dir:      dir,
+		commands: commands,
+	}
+}
+
+// Run verifies the task branch checked out in a worker. It fails early if
+// the worker is on a different branch.
+func (r *Runner) Run(workerName, branch string) (*Report, error) {
+	out, err := r.execer.Exec(workerName, []string{
+		"git", "-C", r.dir, "rev-parse", "--abbrev-ref", "HEAD",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current branch in %s: %w", workerName, err)
+	}
+	if current := strings.TrimSpace(string(out)); current != branch {
+		return nil, fmt.Errorf("%s has %s checked out, not %s", workerName, current, branch)
+	}
+
+	report := &Report{Worker: workerName, Branch: branch}
+	for _, command := range r.commands {
+		out, err := r.execer.Exec(workerName, []string{
+			"sh", "-c", fmt.Sprintf("cd %s && (%s) 2>&1", r.dir, command),
+		})
+		report.Results = append(report.Results, Result{
+			Command: command,
+			Output:  string(out),
+			Err:     err,
+		})
+		if err != nil {
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// Gate verifies a task before it is marked done. When a check fails the
+// task is blocked, the output is attached as a note and a *FailedError is
+// returned.
+func (r *Runner) Gate(ctx context.Context, client airyra.TaskClient, store *notes.Store, workerName, taskID, branch string) error {
+	report, err := r.Run(workerName, branch)
+	if err != nil {
+		return fmt.Errorf("failed to verify task %s: %w", taskID, err)
+	}
+	if report.Passed() {
+		return nil
+	}
+
+	if _, err := client.BlockTask(ctx, taskID); err != nil {
+		return fmt.Errorf("failed to block task %s after failed verification: %w", taskID, err)
+	}
+
+	if store != nil {
+		if err := store.Add(taskID, notes.Note{
+			Kind:   notes.KindVerify,
+			Author: "isollm",
+			Text:   report.Summary(),
+		}); err != nil {
+			return fmt.Errorf("failed to attach verification output to %s: %w", taskID, err)
+		}
+	}
+
+	return &FailedError{TaskID: taskID, Report: report}
+}
@@ -0,0 +1,142 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"isollm/internal/airyra"
+	"isollm/internal/notes"
+)
+
+// fakeExecer answers git rev-parse with a branch and fails commands that
+// contain a configured substring
+type fakeExecer struct {
+	branch string
+	fail   string
+	ran    []string
+}
+
+func (f *fakeExecer) Exec(name string, cmd []string) ([]byte, error) {
+	if cmd[0] == "git" {
+		return []byte(f.branch + "\n"), nil
+	}
+	script := cmd[len(cmd)-1]
+	f.ran = append(f.ran, script)
+	if f.fail != "" && strings.Contains(script, f.fail) {
+		return []byte("--- FAIL: TestSomething\n"), errors.New("exit status 1")
+	}
+	return []byte("ok\n"), nil
+}
+
+func TestRun_AllPass(t *testing.T) {
+	ex := &fakeExecer{branch: "isollm/ar-0001"}
+	r := New(ex, "/home/dev/project", []string{"go build ./...", "go test ./..."})
+
+	report, err := r.Run("worker-1", "isollm/ar-0001")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !report.Passed() {
+		t.Errorf("Passed() = false, want true")
+	}
+	if len(ex.ran) != 2 {
+		t.Fatalf("ran %d commands, want 2", len(ex.ran))
+	}
+	if !strings.Contains(ex.ran[0], "cd /home/dev/project") || !strings.Contains(ex.ran[0], "go build ./...") {
+		t.Errorf("command = %q, want it to run go build in the project", ex.ran[0])
+	}
+}
+
+func TestRun_StopsAtFirstFailure(t *testing.T) {
+	ex := &fakeExecer{branch: "isollm/ar-0001", fail: "go test"}
+	r := New(ex, "/home/dev/project", []string{"go build ./...", "go test ./...", "golangci-lint run"})
+
+	report, err := r.Run("worker-1", "isollm/ar-0001")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Passed() {
+		t.Fatal("Passed() = true, want false")
+	}
+	if failed := report.Failed(); failed.Command != "go test ./..." {
+		t.Errorf("Failed().Command = %q, want go test ./...", failed.Command)
+	}
+	if len(ex.ran) != 2 {
+		t.Errorf("ran %d commands, want 2 (stop after failure)", len(ex.ran))
+	}
+	if !strings.Contains(report.Summary(), "--- FAIL: TestSomething") {
+		t.Errorf("Summary() = %q, want command output", report.Summary())
+	}
+}
+
+func TestRun_WrongBranch(t *testing.T) {
+	ex := &fakeExecer{branch: "main"}
+	r := New(ex, "/home/dev/project", []string{"true"})
+
+	if _, err := r.Run("worker-1", "isollm/ar-0001"); err == nil {
+		t.Error("Run() on wrong branch = nil error, want error")
+	}
+	if len(ex.ran) != 0 {
+		t.Errorf("ran %v on the wrong branch", ex.ran)
+	}
+}
+
+func TestSummary_TruncatesOutput(t *testing.T) {
+	report := &Report{
+		Branch:  "isollm/ar-0001",
+		Results: []Result{{Command: "make", Output: strings.Repeat("x", 3*maxNoteOutput) + "tail", Err: errors.New("exit status 2")}},
+	}
+
+	summary := report.Summary()
+	if len(summary) > maxNoteOutput+200 {
+		t.Errorf("Summary() length = %d, want it truncated", len(summary))
+	}
+	if !strings.HasSuffix(strings.TrimSpace(summary), "tail") {
+		t.Error("Summary() dropped the end of the output")
+	}
+}
+
+func TestGate_BlocksAndAttachesOutput(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+	client.ClaimTask(ctx, task.ID)
+	store := notes.NewWithDir(t.TempDir())
+
+	r := New(&fakeExecer{branch: "isollm/" + task.ID, fail: "test"}, "/home/dev/project", []string{"make test"})
+	err := r.Gate(ctx, client, store, "worker-1", task.ID, "isollm/"+task.ID)
+
+	var failed *FailedError
+	if !errors.As(err, &failed) {
+		t.Fatalf("Gate() error = %v, want *FailedError", err)
+	}
+
+	got, _ := client.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusBlocked {
+		t.Errorf("task status = %v, want %v", got.Status, airyra.StatusBlocked)
+	}
+
+	list, _ := store.List(task.ID)
+	if len(list) != 1 || list[0].Kind != notes.KindVerify || !strings.Contains(list[0].Text, "FAIL") {
+		t.Errorf("notes = %+v, want a verify note with the output", list)
+	}
+}
+
+func TestGate_Passes(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	task, _ := client.AddTask(ctx, "Task")
+	client.ClaimTask(ctx, task.ID)
+
+	r := New(&fakeExecer{branch: "isollm/" + task.ID}, "/home/dev/project", []string{"make test"})
+	if err := r.Gate(ctx, client, nil, "worker-1", task.ID, "isollm/"+task.ID); err != nil {
+		t.Fatalf("Gate() error = %v", err)
+	}
+
+	got, _ := client.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusInProgress {
+		t.Errorf("task status = %v, want %v", got.Status, airyra.StatusInProgress)
+	}
+}
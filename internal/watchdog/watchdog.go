@@ -0,0 +1,250 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"isollm/internal/airyra"
+	"isollm/internal/events"
+	"isollm/internal/notes"
+	"isollm/internal/worker"
+)
+
+const (
+	// DefaultInterval is the default time between watchdog passes
+	DefaultInterval = time.Minute
+)
+
+// WorkerManager is the subset of worker.Manager used by the watchdog
+type WorkerManager interface {
+	List() ([]worker.WorkerInfo, error)
+	SalvageBranch(workerName, branch string) error
+	ClearTask(name string) error
+	StopPane(name string) error
+	TaskBranch(taskID string) string
+}
+
+// BranchHistory reports commit activity on task branches (barerepo.BareRepo)
+type BranchHistory interface {
+	LastCommitTime(branchName string) (time.Time, error)
+}
+
+// Reclaim records a task taken back from a worker
+type Reclaim struct {
+	Worker string
+	TaskID string
+	Branch string
+	Reason string
+}
+
+// Watchdog force-releases tasks held by dead or silent workers
+type Watchdog struct {
+	mgr     WorkerManager
+	airyra  airyra.TaskClient
+	repo    BranchHistory
+	notes   *notes.Store
+	events  *events.Log
+	timeout time.Duration
+	out     io.Writer
+	now     func() time.Time
+}
+
+// New creates a Watchdog. A task is considered stale when neither its claim
+// nor its branch has moved for longer than timeout; a zero timeout only
+// reclaims tasks from workers whose container is not running.
+func New(mgr WorkerManager, client airyra.TaskClient, repo BranchHistory, store *notes.Store, timeout time.Duration, out io.Writer) *Watchdog {
+	return &Watchdog{
+		mgr:     mgr,
+		airyra:  client,
+		repo:    repo,
+		notes:   store,
+		timeout: timeout,
+		out:     out,
+		now:     time.Now,
+	}
+}
+
+// SetEvents records each reclaim in the session event log
+func (w *Watchdog) SetEvents(l *events.Log) {
+	w.events = l
+}
+
+// Run checks for stale tasks every interval until the context is cancelled
+func (w *Watchdog) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(ctx); err != nil {
+			fmt.Fprintf(w.out, "watchdog: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check performs a single pass and reclaims every stale task it finds
+func (w *Watchdog) Check(ctx context.Context) ([]Reclaim, error) {
+	if w.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+
+	workers, err := w.mgr.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+
+	tasks, err := airyra.ListAllTasks(ctx, w.airyra, airyra.WithStatus(airyra.StatusInProgress))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	var reclaimed []Reclaim
+	for _, wk := range workers {
+		for _, held := range w.held(wk, tasks) {
+			reason := w.staleReason(held.worker, held.task)
+			if reason == "" {
+				continue
+			}
+
+			r := Reclaim{Worker: wk.Name, TaskID: held.task.ID, Branch: held.worker.Branch, Reason: reason}
+			if err := w.reclaim(ctx, held, r); err != nil {
+				fmt.Fprintf(w.out, "watchdog: %s: %v\n", wk.Name, err)
+				continue
+			}
+
+			fmt.Fprintf(w.out, "Reclaimed %s from %s: %s\n", r.TaskID, r.Worker, r.Reason)
+			reclaimed = append(reclaimed, r)
+		}
+	}
+
+	return reclaimed, nil
+}
+
+// heldTask is an in-progress task with the worker holding it, whose
+// TaskID, Branch and ClaimedAt describe that task
+type heldTask struct {
+	worker   worker.WorkerInfo
+	task     *airyra.Task
+	assigned bool // Assigned by isollm rather than claimed in the container
+}
+
+// held returns the in-progress tasks a worker holds: the one isollm
+// assigned it and any its agent claimed from inside the container.
+// Blocked, finished or unknown tasks are left to the dispatcher.
+func (w *Watchdog) held(wk worker.WorkerInfo, tasks []*airyra.Task) []heldTask {
+	var held []heldTask
+	for _, t := range tasks {
+		if t.Status != airyra.StatusInProgress {
+			continue
+		}
+
+		switch {
+		case wk.TaskID != "" && t.ID == wk.TaskID:
+			held = append(held, heldTask{worker: wk, task: t, assigned: true})
+		case t.ClaimedBy != nil && *t.ClaimedBy == wk.Name:
+			// Claimed in the container, so isollm has no record of when
+			// or on which branch; CLAUDE.md names it after the task
+			claimed := wk
+			claimed.TaskID = t.ID
+			claimed.Branch = w.mgr.TaskBranch(t.ID)
+			claimed.ClaimedAt = time.Time{}
+			held = append(held, heldTask{worker: claimed, task: t})
+		}
+	}
+	return held
+}
+
+// staleReason returns why a worker's task should be reclaimed, or "" if
+// the worker still looks healthy
+func (w *Watchdog) staleReason(wk worker.WorkerInfo, task *airyra.Task) string {
+	if wk.Status != "RUNNING" {
+		return fmt.Sprintf("worker %s is %s", wk.Name, wk.Status)
+	}
+
+	if w.timeout <= 0 {
+		return ""
+	}
+
+	last := wk.ClaimedAt
+	if last.IsZero() && task.ClaimedAt != nil {
+		last = *task.ClaimedAt
+	}
+	if w.repo != nil && wk.Branch != "" {
+		if commit, err := w.repo.LastCommitTime(wk.Branch); err == nil && commit.After(last) {
+			last = commit
+		}
+	}
+	if last.IsZero() {
+		return ""
+	}
+
+	idle := w.now().Sub(last)
+	if idle <= w.timeout {
+		return ""
+	}
+	return fmt.Sprintf("no activity on %s for %s (timeout %s)", wk.Branch, idle.Round(time.Minute), w.timeout)
+}
+
+// reclaim stops the worker's Claude session, salvages its branch,
+// force-releases the task and records a note explaining why
+func (w *Watchdog) reclaim(ctx context.Context, held heldTask, r Reclaim) error {
+	wk := held.worker
+	text := r.Reason
+	if wk.Status == "RUNNING" {
+		// A silent session would otherwise keep working on a task that
+		// is about to go to someone else
+		if err := w.mgr.StopPane(wk.Name); err != nil {
+			fmt.Fprintf(w.out, "watchdog: %v\n", err)
+		}
+	}
+	if wk.Status == "RUNNING" && r.Branch != "" {
+		if err := w.mgr.SalvageBranch(wk.Name, r.Branch); err != nil {
+			fmt.Fprintf(w.out, "watchdog: failed to salvage %s: %v\n", r.Branch, err)
+		} else {
+			text += fmt.Sprintf("; work so far was pushed to %s", r.Branch)
+		}
+	}
+
+	if _, err := w.airyra.ReleaseTask(ctx, r.TaskID, true); err != nil {
+		return fmt.Errorf("failed to release %s: %w", r.TaskID, err)
+	}
+
+	if held.assigned {
+		if err := w.mgr.ClearTask(wk.Name); err != nil {
+			return fmt.Errorf("failed to clear task state: %w", err)
+		}
+	}
+
+	if w.events != nil {
+		w.events.Emit(events.Event{
+			Type:    events.TaskReclaimed,
+			Worker:  wk.Name,
+			TaskID:  r.TaskID,
+			Branch:  r.Branch,
+			Message: text,
+		})
+	}
+
+	if w.notes != nil {
+		if err := w.notes.Add(r.TaskID, notes.Note{
+			Kind:   notes.KindWatchdog,
+			Author: "isollm",
+			Text:   "Reclaimed from " + wk.Name + ": " + text,
+		}); err != nil {
+			fmt.Fprintf(w.out, "watchdog: failed to record note for %s: %v\n", r.TaskID, err)
+		}
+	}
+
+	return nil
+}
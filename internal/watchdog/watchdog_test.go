@@ -0,0 +1,275 @@
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"lxc-dev-manager/pkg/lxcmgr"
+
+	"isollm/internal/airyra"
+	"isollm/internal/notes"
+	"isollm/internal/worker"
+)
+
+type mockManager struct {
+	workers    []worker.WorkerInfo
+	salvaged   []string
+	salvageErr error
+	cleared    []string
+	stopped    []string
+}
+
+func (m *mockManager) List() ([]worker.WorkerInfo, error) {
+	return m.workers, nil
+}
+
+func (m *mockManager) SalvageBranch(workerName, branch string) error {
+	if m.salvageErr != nil {
+		return m.salvageErr
+	}
+	m.salvaged = append(m.salvaged, branch)
+	return nil
+}
+
+func (m *mockManager) ClearTask(name string) error {
+	m.cleared = append(m.cleared, name)
+	return nil
+}
+
+func (m *mockManager) StopPane(name string) error {
+	m.stopped = append(m.stopped, name)
+	return nil
+}
+
+func (m *mockManager) TaskBranch(taskID string) string {
+	return "isollm/" + taskID
+}
+
+type mockHistory map[string]time.Time
+
+func (h mockHistory) LastCommitTime(branch string) (time.Time, error) {
+	t, ok := h[branch]
+	if !ok {
+		return time.Time{}, errors.New("unknown branch")
+	}
+	return t, nil
+}
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// claimed adds an in-progress task and returns a worker holding it
+func claimed(t *testing.T, client *airyra.MockClient, name string, status lxcmgr.ContainerStatus, claimedAt time.Time) worker.WorkerInfo {
+	t.Helper()
+	ctx := context.Background()
+	task, _ := client.AddTask(ctx, "Task for "+name)
+	if _, err := client.ClaimTask(ctx, task.ID); err != nil {
+		t.Fatalf("ClaimTask() error = %v", err)
+	}
+	return worker.WorkerInfo{
+		Name:      name,
+		Status:    status,
+		TaskID:    task.ID,
+		Branch:    "isollm/" + task.ID,
+		ClaimedAt: claimedAt,
+	}
+}
+
+func newWatchdog(mgr *mockManager, client airyra.TaskClient, history mockHistory, store *notes.Store, timeout time.Duration) *Watchdog {
+	w := New(mgr, client, history, store, timeout, &bytes.Buffer{})
+	w.now = func() time.Time { return now }
+	return w
+}
+
+func TestCheck_ReclaimsFromStoppedWorker(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	wk := claimed(t, client, "worker-1", "STOPPED", now.Add(-time.Minute))
+
+	mgr := &mockManager{workers: []worker.WorkerInfo{wk}}
+	store := notes.NewWithDir(t.TempDir())
+	w := newWatchdog(mgr, client, nil, store, time.Hour)
+
+	reclaimed, err := w.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(reclaimed) != 1 || reclaimed[0].TaskID != wk.TaskID {
+		t.Fatalf("Check() = %+v, want %s reclaimed", reclaimed, wk.TaskID)
+	}
+
+	task, _ := client.GetTask(ctx, wk.TaskID)
+	if task.Status != airyra.StatusOpen {
+		t.Errorf("task status = %v, want %v", task.Status, airyra.StatusOpen)
+	}
+	if len(mgr.salvaged) != 0 {
+		t.Errorf("salvaged %v from a stopped worker, want none", mgr.salvaged)
+	}
+	if len(mgr.cleared) != 1 || mgr.cleared[0] != "worker-1" {
+		t.Errorf("cleared = %v, want [worker-1]", mgr.cleared)
+	}
+
+	got, _ := store.List(wk.TaskID)
+	if len(got) != 1 || got[0].Kind != notes.KindWatchdog || !strings.Contains(got[0].Text, "STOPPED") {
+		t.Errorf("notes = %+v, want one watchdog note mentioning STOPPED", got)
+	}
+}
+
+func TestCheck_ReclaimsSilentWorker(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	wk := claimed(t, client, "worker-1", "RUNNING", now.Add(-3*time.Hour))
+	history := mockHistory{wk.Branch: now.Add(-2 * time.Hour)}
+
+	mgr := &mockManager{workers: []worker.WorkerInfo{wk}}
+	store := notes.NewWithDir(t.TempDir())
+	w := newWatchdog(mgr, client, history, store, time.Hour)
+
+	reclaimed, err := w.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(reclaimed) != 1 {
+		t.Fatalf("Check() reclaimed %d tasks, want 1", len(reclaimed))
+	}
+	if !strings.Contains(reclaimed[0].Reason, "2h0m0s") {
+		t.Errorf("Reason = %q, want idle time since last commit", reclaimed[0].Reason)
+	}
+	if len(mgr.salvaged) != 1 || mgr.salvaged[0] != wk.Branch {
+		t.Errorf("salvaged = %v, want [%s]", mgr.salvaged, wk.Branch)
+	}
+	if len(mgr.stopped) != 1 || mgr.stopped[0] != "worker-1" {
+		t.Errorf("stopped = %v, want the silent session stopped", mgr.stopped)
+	}
+
+	got, _ := store.List(wk.TaskID)
+	if len(got) != 1 || !strings.Contains(got[0].Text, "pushed to "+wk.Branch) {
+		t.Errorf("notes = %+v, want note mentioning the salvaged branch", got)
+	}
+}
+
+func TestCheck_ReclaimsTaskClaimedInContainer(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	client.SetAgentID("worker-1")
+	task, _ := client.AddTask(ctx, "Claimed by the worker's agent")
+	if _, err := client.ClaimTask(ctx, task.ID); err != nil {
+		t.Fatalf("ClaimTask() error = %v", err)
+	}
+	client.SetAgentID("someone")
+	other, _ := client.AddTask(ctx, "Claimed by someone else")
+	client.ClaimTask(ctx, other.ID)
+
+	mgr := &mockManager{workers: []worker.WorkerInfo{{Name: "worker-1", Status: "STOPPED"}}}
+	w := newWatchdog(mgr, client, nil, nil, time.Hour)
+
+	reclaimed, err := w.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(reclaimed) != 1 || reclaimed[0].TaskID != task.ID || reclaimed[0].Branch != "isollm/"+task.ID {
+		t.Fatalf("Check() = %+v, want %s reclaimed from its task branch", reclaimed, task.ID)
+	}
+
+	got, _ := client.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusOpen {
+		t.Errorf("task status = %v, want %v", got.Status, airyra.StatusOpen)
+	}
+	if got, _ := client.GetTask(ctx, other.ID); got.Status != airyra.StatusInProgress {
+		t.Errorf("other agent's task status = %v, want it left alone", got.Status)
+	}
+	if len(mgr.cleared) != 0 {
+		t.Errorf("cleared = %v, want no host task state touched", mgr.cleared)
+	}
+}
+
+func TestCheck_RecentCommitKeepsTask(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	wk := claimed(t, client, "worker-1", "RUNNING", now.Add(-3*time.Hour))
+	history := mockHistory{wk.Branch: now.Add(-10 * time.Minute)}
+
+	mgr := &mockManager{workers: []worker.WorkerInfo{wk}}
+	w := newWatchdog(mgr, client, history, nil, time.Hour)
+
+	reclaimed, err := w.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(reclaimed) != 0 {
+		t.Errorf("Check() = %+v, want nothing reclaimed", reclaimed)
+	}
+
+	task, _ := client.GetTask(ctx, wk.TaskID)
+	if task.Status != airyra.StatusInProgress {
+		t.Errorf("task status = %v, want %v", task.Status, airyra.StatusInProgress)
+	}
+}
+
+func TestCheck_SkipsBlockedTasks(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	wk := claimed(t, client, "worker-1", "STOPPED", now.Add(-3*time.Hour))
+	client.BlockTask(ctx, wk.TaskID)
+
+	mgr := &mockManager{workers: []worker.WorkerInfo{wk}}
+	w := newWatchdog(mgr, client, nil, nil, time.Hour)
+
+	reclaimed, err := w.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(reclaimed) != 0 {
+		t.Errorf("Check() = %+v, want blocked task left alone", reclaimed)
+	}
+}
+
+func TestCheck_ZeroTimeoutOnlyReclaimsDeadWorkers(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	silent := claimed(t, client, "worker-1", "RUNNING", now.Add(-48*time.Hour))
+	dead := claimed(t, client, "worker-2", "STOPPED", now)
+
+	mgr := &mockManager{workers: []worker.WorkerInfo{silent, dead}}
+	w := newWatchdog(mgr, client, nil, nil, 0)
+
+	reclaimed, err := w.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(reclaimed) != 1 || reclaimed[0].Worker != "worker-2" {
+		t.Errorf("Check() = %+v, want only worker-2 reclaimed", reclaimed)
+	}
+}
+
+func TestCheck_SalvageFailureStillReclaims(t *testing.T) {
+	ctx := context.Background()
+	client := airyra.NewMockClient()
+	wk := claimed(t, client, "worker-1", "RUNNING", now.Add(-2*time.Hour))
+
+	mgr := &mockManager{workers: []worker.WorkerInfo{wk}, salvageErr: errors.New("push rejected")}
+	out := &bytes.Buffer{}
+	w := New(mgr, client, nil, nil, time.Hour, out)
+	w.now = func() time.Time { return now }
+
+	reclaimed, err := w.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(reclaimed) != 1 {
+		t.Fatalf("Check() reclaimed %d tasks, want 1", len(reclaimed))
+	}
+	if !strings.Contains(out.String(), "push rejected") {
+		t.Errorf("output %q does not mention the salvage failure", out.String())
+	}
+}
+
+func TestCheck_NoAiryra(t *testing.T) {
+	w := New(&mockManager{}, nil, nil, nil, time.Hour, &bytes.Buffer{})
+	if _, err := w.Check(context.Background()); err == nil {
+		t.Error("Check() with nil airyra client = nil, want error")
+	}
+}
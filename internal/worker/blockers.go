@@ -0,0 +1,232 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"isollm/internal/airyra"
+	"isollm/internal/claude"
+	"isollm/internal/events"
+	"isollm/internal/notes"
+)
+
+// BlockedTask is a blocked task and the question holding it up
+type BlockedTask struct {
+	Task     *airyra.Task `json:"task"`
+	Worker   string       `json:"worker,omitempty"`   // Worker holding the task, empty if none
+	Question *notes.Note  `json:"question,omitempty"` // Nil if no reason was given
+}
+
+// BlockRequest returns the reason Claude gave for blocking its task. ok is
+// false if Claude has not asked. The request stays until ClearBlockRequest,
+// so it is not lost if blocking the task fails.
+func (m *Manager) BlockRequest(workerName string) (reason string, ok bool) {
+	workerName = m.normalizeName(workerName)
+	out, err := m.client.Exec(workerName, []string{"cat", claude.BlockedMarkerPath})
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
+
+// ClearBlockRequest removes Claude's request to block its task once the
+// task has been blocked
+func (m *Manager) ClearBlockRequest(workerName string) error {
+	workerName = m.normalizeName(workerName)
+	if _, err := m.client.Exec(workerName, []string{"rm", "-f", claude.BlockedMarkerPath}); err != nil {
+		return fmt.Errorf("failed to clear block request in %s: %w", workerName, err)
+	}
+	return nil
+}
+
+// BlockTask blocks a task with a reason, acting as the worker holding it
+// when there is one
+func (m *Manager) BlockTask(ctx context.Context, taskID, reason string) (*airyra.Task, error) {
+	if m.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+
+	workerName, err := m.workerFor(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return m.block(ctx, workerName, taskID, reason, "")
+}
+
+// block marks a task blocked and records the reason as a note
+func (m *Manager) block(ctx context.Context, workerName, taskID, reason, author string) (*airyra.Task, error) {
+	client, err := m.holderClient(workerName)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.BlockTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to block task: %w", err)
+	}
+
+	if reason != "" && m.notes != nil {
+		if err := m.notes.Add(taskID, notes.Note{Kind: notes.KindBlocker, Author: author, Text: reason}); err != nil {
+			return nil, fmt.Errorf("failed to record blocker: %w", err)
+		}
+	}
+
+	m.emitTask(events.TaskBlocked, workerName, taskID, reason)
+	return task, nil
+}
+
+// UnblockTask unblocks a task without answering its question
+func (m *Manager) UnblockTask(ctx context.Context, taskID string) (*airyra.Task, error) {
+	if m.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+
+	workerName, err := m.workerFor(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := m.holderClient(workerName)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.UnblockTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unblock task: %w", err)
+	}
+
+	m.emitTask(events.TaskUnblocked, workerName, taskID, "")
+	return task, nil
+}
+
+// BlockedTasks returns the blocked tasks with their open questions
+func (m *Manager) BlockedTasks(ctx context.Context) ([]BlockedTask, error) {
+	if m.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+
+	list, err := m.airyra.ListTasks(ctx, airyra.WithStatus(airyra.StatusBlocked), airyra.WithPerPage(100))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocked tasks: %w", err)
+	}
+
+	holders, err := m.taskHolders()
+	if err != nil {
+		return nil, err
+	}
+
+	blocked := make([]BlockedTask, 0, len(list.Tasks))
+	for _, t := range list.Tasks {
+		b := BlockedTask{Task: t, Worker: holders[t.ID]}
+		if m.notes != nil {
+			taskNotes, err := m.notes.List(t.ID)
+			if err != nil {
+				return nil, err
+			}
+			b.Question = notes.OpenQuestion(taskNotes)
+		}
+		blocked = append(blocked, b)
+	}
+	return blocked, nil
+}
+
+// AnswerTask records an answer to a blocked task's question and unblocks
+// it. It returns the task as it was answered: the worker holding it, if
+// any, gets the answer with DeliverAnswer.
+func (m *Manager) AnswerTask(ctx context.Context, taskID, answer string) (*BlockedTask, error) {
+	if m.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+
+	workerName, err := m.workerFor(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var question *notes.Note
+	if m.notes != nil {
+		taskNotes, err := m.notes.List(taskID)
+		if err != nil {
+			return nil, err
+		}
+		question = notes.OpenQuestion(taskNotes)
+	}
+
+	client, err := m.holderClient(workerName)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.UnblockTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unblock task: %w", err)
+	}
+
+	if m.notes != nil {
+		if err := m.notes.Add(taskID, notes.Note{Kind: notes.KindAnswer, Text: answer}); err != nil {
+			return nil, fmt.Errorf("failed to record answer: %w", err)
+		}
+	}
+
+	m.emitTask(events.TaskUnblocked, workerName, taskID, answer)
+	return &BlockedTask{Task: task, Worker: workerName, Question: question}, nil
+}
+
+// DeliverAnswer appends a question and its answer to the CLAUDE.md in a
+// worker's project, where Claude is told to look for it
+func (m *Manager) DeliverAnswer(workerName, taskID, question, answer string) error {
+	workerName = m.normalizeName(workerName)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n## Answer to blocker on %s\n\n", taskID)
+	if question != "" {
+		fmt.Fprintf(&b, "**Question**: %s\n\n", question)
+	}
+	fmt.Fprintf(&b, "**Answer**: %s\n\n", answer)
+	b.WriteString("The task is unblocked. Carry on with it.\n")
+
+	claudeMD := path.Join(ProjectPath, "CLAUDE.md")
+	if _, err := m.client.Exec(workerName, []string{
+		"sh", "-c", fmt.Sprintf("printf '%%s' %s >> %s", shellQuote(b.String()), claudeMD),
+	}); err != nil {
+		return fmt.Errorf("failed to write answer to %s: %w", workerName, err)
+	}
+	return nil
+}
+
+// workerFor returns the worker holding a task, or "" if none does
+func (m *Manager) workerFor(taskID string) (string, error) {
+	holders, err := m.taskHolders()
+	if err != nil {
+		return "", err
+	}
+	return holders[taskID], nil
+}
+
+// holderClient returns an airyra client acting as the worker holding a
+// task, or the host's client if no worker holds it
+func (m *Manager) holderClient(workerName string) (airyra.TaskClient, error) {
+	if workerName == "" {
+		return m.airyra, nil
+	}
+	return m.clientFor(workerName)
+}
+
+// taskHolders maps task IDs to the workers holding them
+func (m *Manager) taskHolders() (map[string]string, error) {
+	states, err := m.TaskStates()
+	if err != nil {
+		return nil, err
+	}
+
+	holders := make(map[string]string, len(states))
+	for _, s := range states {
+		if s.TaskID != "" {
+			holders[s.TaskID] = s.WorkerName
+		}
+	}
+	return holders, nil
+}
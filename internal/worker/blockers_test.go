@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"isollm/internal/airyra"
+	"isollm/internal/notes"
+)
+
+func TestManager_BlockAndAnswer(t *testing.T) {
+	mgr, mock := testManager(t)
+	mgr.notes = notes.NewWithDir(t.TempDir())
+	ctx := context.Background()
+
+	mock.AddTask(ctx, "Task 1")
+	task, _ := mgr.ClaimNextTask(ctx, "worker-1")
+
+	if err := mgr.BlockWorkerTask(ctx, "worker-1", "Which API version should I target?"); err != nil {
+		t.Fatalf("BlockWorkerTask() error = %v", err)
+	}
+
+	blocked, err := mgr.BlockedTasks(ctx)
+	if err != nil {
+		t.Fatalf("BlockedTasks() error = %v", err)
+	}
+	if len(blocked) != 1 {
+		t.Fatalf("BlockedTasks() = %d tasks, want 1", len(blocked))
+	}
+	b := blocked[0]
+	if b.Task.ID != task.ID || b.Worker != "worker-1" {
+		t.Errorf("BlockedTasks()[0] = %s on %q, want %s on worker-1", b.Task.ID, b.Worker, task.ID)
+	}
+	if b.Question == nil || b.Question.Text != "Which API version should I target?" || b.Question.Author != "worker-1" {
+		t.Errorf("Question = %+v, want the worker's question", b.Question)
+	}
+
+	answered, err := mgr.AnswerTask(ctx, task.ID, "v2")
+	if err != nil {
+		t.Fatalf("AnswerTask() error = %v", err)
+	}
+	if answered.Worker != "worker-1" {
+		t.Errorf("AnswerTask() worker = %q, want worker-1", answered.Worker)
+	}
+	if answered.Question == nil || answered.Question.Text != "Which API version should I target?" {
+		t.Errorf("AnswerTask() question = %+v, want the worker's question", answered.Question)
+	}
+
+	got, _ := mock.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusInProgress {
+		t.Errorf("after answer, task.Status = %v, want %v", got.Status, airyra.StatusInProgress)
+	}
+
+	taskNotes, _ := mgr.notes.List(task.ID)
+	if q := notes.OpenQuestion(taskNotes); q != nil {
+		t.Errorf("question still open after answer: %+v", q)
+	}
+	if last := taskNotes[len(taskNotes)-1]; last.Kind != notes.KindAnswer || last.Text != "v2" {
+		t.Errorf("last note = %+v, want the answer", last)
+	}
+}
+
+func TestManager_BlockTask_UsesHolder(t *testing.T) {
+	mgr, mock := testManager(t)
+	mgr.notes = notes.NewWithDir(t.TempDir())
+	ctx := context.Background()
+
+	mock.AddTask(ctx, "Task 1")
+	task, _ := mgr.ClaimNextTask(ctx, "worker-1")
+
+	if _, err := mgr.BlockTask(ctx, task.ID, "Waiting on the design review"); err != nil {
+		t.Fatalf("BlockTask() error = %v", err)
+	}
+
+	got, _ := mock.GetTask(ctx, task.ID)
+	if got.Status != airyra.StatusBlocked {
+		t.Errorf("task.Status = %v, want %v", got.Status, airyra.StatusBlocked)
+	}
+	taskNotes, _ := mgr.notes.List(task.ID)
+	if len(taskNotes) != 1 || taskNotes[0].Kind != notes.KindBlocker || taskNotes[0].Author != "" {
+		t.Errorf("notes = %+v, want one unattributed blocker note", taskNotes)
+	}
+}
+
+func TestManager_AnswerTask_NotBlocked(t *testing.T) {
+	mgr, mock := testManager(t)
+	mgr.notes = notes.NewWithDir(t.TempDir())
+	ctx := context.Background()
+
+	task, _ := mock.AddTask(ctx, "Task 1")
+
+	if _, err := mgr.AnswerTask(ctx, task.ID, "v2"); err == nil {
+		t.Error("AnswerTask() on an open task = nil, want error")
+	}
+	if taskNotes, _ := mgr.notes.List(task.ID); len(taskNotes) != 0 {
+		t.Errorf("notes = %+v, want none when the unblock fails", taskNotes)
+	}
+}
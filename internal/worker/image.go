@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"isollm/internal/config"
+)
+
+// imageKeyLen is the number of hex digits of the hash used in image aliases
+const imageKeyLen = 12
+
+// ImageKey returns a hash of everything that goes into a provisioned
+// worker: the base image, the provision block (including the contents of
+// copied files) and the setup script. Workers are created from the cached
+// image only while the key is unchanged.
+func ImageKey(cfg *config.Config, projectDir string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "image %s\n", cfg.Image)
+	fmt.Fprintf(h, "setup %q\n", cfg.Setup)
+
+	for _, pkg := range cfg.Provision.Packages {
+		fmt.Fprintf(h, "package %s\n", pkg)
+	}
+
+	for _, f := range cfg.Provision.Files {
+		data, err := os.ReadFile(resolveHostPath(projectDir, f.Src))
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", f.Src, err)
+		}
+		fmt.Fprintf(h, "file %s %s %s %x\n", f.Src, f.Dst, f.Mode, sha256.Sum256(data))
+	}
+
+	keys := make([]string, 0, len(cfg.Provision.Env))
+	for k := range cfg.Provision.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "env %s=%q\n", k, cfg.Provision.Env[k])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:imageKeyLen], nil
+}
+
+// imagePrefix is the alias prefix of the project's cached images
+func (m *Manager) imagePrefix() string {
+	return fmt.Sprintf("isollm-%s-", m.cfg.Project)
+}
+
+// imageAlias returns the alias of the cached image for the current config
+func (m *Manager) imageAlias() (string, error) {
+	key, err := ImageKey(m.cfg, m.projectDir)
+	if err != nil {
+		return "", err
+	}
+	return m.imagePrefix() + key, nil
+}
+
+// CachedImages returns the aliases of the project's cached images
+func (m *Manager) CachedImages() ([]string, error) {
+	out, err := m.lxc("image", "list", "--format", "csv", "--columns", "l")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	var aliases []string
+	for _, line := range strings.Split(out, "\n") {
+		alias := strings.Trim(line, `"`)
+		// Extra aliases are shown as "alias (N more)"
+		if i := strings.IndexByte(alias, ' '); i >= 0 {
+			alias = alias[:i]
+		}
+		// The fixed-length key keeps "isollm-a-" from matching "isollm-a-b-"
+		if strings.HasPrefix(alias, m.imagePrefix()) && len(alias) == len(m.imagePrefix())+imageKeyLen {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases, nil
+}
+
+// cachedImage returns the cached image new workers can be created from,
+// or "" if there is none for the current config
+func (m *Manager) cachedImage() string {
+	alias, err := m.imageAlias()
+	if err != nil {
+		return ""
+	}
+	if _, err := m.lxc("image", "info", alias); err != nil {
+		return ""
+	}
+	return alias
+}
+
+// cacheImage publishes a freshly provisioned worker's clean snapshot as
+// the project's cached image, replacing images built from an older config
+func (m *Manager) cacheImage(name string, log io.Writer) error {
+	alias, err := m.imageAlias()
+	if err != nil {
+		return err
+	}
+
+	old, err := m.CachedImages()
+	if err != nil {
+		return err
+	}
+	for _, a := range old {
+		if a == alias {
+			return nil // Another worker got there first
+		}
+		m.lxc("image", "delete", a)
+	}
+
+	fmt.Fprintf(log, "==> cache image %s\n", alias)
+	if _, err := m.lxc("publish", name+"/"+CleanSnapshotName, "--alias", alias); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", name, err)
+	}
+	return nil
+}
+
+// RemoveCachedImages deletes the project's cached images so the next
+// worker is provisioned from scratch and cached again. It returns the
+// aliases removed.
+func (m *Manager) RemoveCachedImages() ([]string, error) {
+	aliases, err := m.CachedImages()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range aliases {
+		if _, err := m.lxc("image", "delete", a); err != nil {
+			return nil, fmt.Errorf("failed to delete image %s: %w", a, err)
+		}
+	}
+	return aliases, nil
+}
@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"isollm/internal/config"
+)
+
+func TestImageKey(t *testing.T) {
+	dir := t.TempDir()
+	npmrc := filepath.Join(dir, "npmrc")
+	os.WriteFile(npmrc, []byte("a"), 0644)
+
+	base := func() *config.Config {
+		return &config.Config{
+			Image: "ubuntu:24.04",
+			Setup: "npm install",
+			Provision: config.ProvisionConfig{
+				Packages: []string{"nodejs"},
+				Files:    []config.FileCopy{{Src: "npmrc", Dst: ".npmrc"}},
+				Env:      map[string]string{"A": "1", "B": "2"},
+			},
+		}
+	}
+
+	key, err := ImageKey(base(), dir)
+	if err != nil {
+		t.Fatalf("ImageKey() error = %v", err)
+	}
+	if again, _ := ImageKey(base(), dir); again != key {
+		t.Errorf("ImageKey() not stable: %s != %s", again, key)
+	}
+
+	changes := map[string]func(c *config.Config){
+		"image":   func(c *config.Config) { c.Image = "ubuntu:22.04" },
+		"setup":   func(c *config.Config) { c.Setup = "npm ci" },
+		"package": func(c *config.Config) { c.Provision.Packages = append(c.Provision.Packages, "git") },
+		"env":     func(c *config.Config) { c.Provision.Env["A"] = "3" },
+		"mode":    func(c *config.Config) { c.Provision.Files[0].Mode = "0600" },
+	}
+	for name, change := range changes {
+		cfg := base()
+		change(cfg)
+		if got, _ := ImageKey(cfg, dir); got == key {
+			t.Errorf("changing %s kept key %s", name, key)
+		}
+	}
+
+	os.WriteFile(npmrc, []byte("b"), 0644)
+	if got, _ := ImageKey(base(), dir); got == key {
+		t.Error("changing a copied file kept the key")
+	}
+
+	os.Remove(npmrc)
+	if _, err := ImageKey(base(), dir); err == nil {
+		t.Error("ImageKey() error = nil for missing file")
+	}
+}
+
+func TestCachedImage(t *testing.T) {
+	mgr, _ := testManager(t)
+	mgr.projectDir = t.TempDir()
+	alias, _ := mgr.imageAlias()
+
+	lxc := &fakeLXC{outputs: map[string]string{}}
+	mgr.lxc = func(args ...string) (string, error) {
+		if args[0] == "image" && args[1] == "info" && lxc.outputs["image info "+args[2]] == "" {
+			return "", os.ErrNotExist
+		}
+		return lxc.run(args...)
+	}
+
+	if got := mgr.cachedImage(); got != "" {
+		t.Errorf("cachedImage() = %q with no image", got)
+	}
+
+	lxc.outputs["image info "+alias] = "Fingerprint: abc"
+	if got := mgr.cachedImage(); got != alias {
+		t.Errorf("cachedImage() = %q, want %q", got, alias)
+	}
+}
+
+func TestRemoveCachedImages(t *testing.T) {
+	mgr, _ := testManager(t)
+	lxc := &fakeLXC{outputs: map[string]string{
+		"image list --format csv --columns l": strings.Join([]string{
+			"isollm-test-project-0123456789ab",
+			`"isollm-test-project-ba9876543210 (1 more)"`,
+			"isollm-other-0123456789ab",
+			"isollm-test-project-x-0123456789ab",
+			"",
+		}, "\n"),
+	}}
+	mgr.lxc = lxc.run
+
+	removed, err := mgr.RemoveCachedImages()
+	if err != nil {
+		t.Fatalf("RemoveCachedImages() error = %v", err)
+	}
+	want := []string{"isollm-test-project-0123456789ab", "isollm-test-project-ba9876543210"}
+	if strings.Join(removed, ",") != strings.Join(want, ",") {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+	for _, call := range lxc.calls {
+		if strings.Contains(call, "isollm-other") || strings.Contains(call, "-x-") {
+			t.Errorf("deleted another project's image: %s", call)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"isollm/internal/config"
+)
+
+// LXCRunner runs the lxc CLI. Resource limits are not exposed by
+// lxc-dev-manager, so they are set on the container directly.
+type LXCRunner func(args ...string) (string, error)
+
+// runLXC runs lxc and returns its trimmed output
+func runLXC(args ...string) (string, error) {
+	out, err := exec.Command("lxc", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("lxc %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// limitKeys maps resource limits to LXD instance config keys
+func limitKeys(r config.ResourcesConfig) [][2]string {
+	var keys [][2]string
+	if r.CPU > 0 {
+		keys = append(keys, [2]string{"limits.cpu", strconv.Itoa(r.CPU)})
+	}
+	if r.Memory != "" {
+		keys = append(keys, [2]string{"limits.memory", r.Memory})
+	}
+	if r.Processes > 0 {
+		keys = append(keys, [2]string{"limits.processes", strconv.Itoa(r.Processes)})
+	}
+	return keys
+}
+
+// ConfiguredLimits returns the resources block of the project config,
+// which new workers are created with
+func (m *Manager) ConfiguredLimits() config.ResourcesConfig {
+	return m.cfg.Resources
+}
+
+// SetLimits applies resource limits to a worker. Only the limits that are
+// set are changed; the rest are left as they are.
+func (m *Manager) SetLimits(name string, r config.ResourcesConfig) error {
+	name = m.normalizeName(name)
+
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	for _, kv := range limitKeys(r) {
+		if _, err := m.lxc("config", "set", name, kv[0], kv[1]); err != nil {
+			return fmt.Errorf("failed to set %s on %s: %w", kv[0], name, err)
+		}
+	}
+
+	if r.Disk != "" {
+		// The root disk usually comes from a profile and must be overridden
+		// on the instance before its size can be set
+		size := "size=" + r.Disk
+		if _, err := m.lxc("config", "device", "set", name, "root", size); err != nil {
+			if _, err := m.lxc("config", "device", "override", name, "root", size); err != nil {
+				return fmt.Errorf("failed to set disk quota on %s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Limits returns the resource limits currently set on a worker
+func (m *Manager) Limits(name string) (config.ResourcesConfig, error) {
+	name = m.normalizeName(name)
+
+	var r config.ResourcesConfig
+	get := func(key string) (string, error) {
+		v, err := m.lxc("config", "get", name, key)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s on %s: %w", key, name, err)
+		}
+		return v, nil
+	}
+
+	cpu, err := get("limits.cpu")
+	if err != nil {
+		return r, err
+	}
+	if cpu != "" {
+		r.CPU, _ = strconv.Atoi(cpu)
+	}
+
+	if r.Memory, err = get("limits.memory"); err != nil {
+		return r, err
+	}
+
+	processes, err := get("limits.processes")
+	if err != nil {
+		return r, err
+	}
+	if processes != "" {
+		r.Processes, _ = strconv.Atoi(processes)
+	}
+
+	// Fails when the root disk is inherited from a profile, i.e. no quota
+	if disk, err := m.lxc("config", "device", "get", name, "root", "size"); err == nil {
+		r.Disk = disk
+	}
+
+	return r, nil
+}
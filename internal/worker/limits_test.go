@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"isollm/internal/config"
+)
+
+// fakeLXC records lxc invocations and answers from a map of
+// "args joined by space" -> output. Unknown "device set" calls fail, as
+// they do for a root disk inherited from a profile.
+type fakeLXC struct {
+	calls   []string
+	outputs map[string]string
+}
+
+func (f *fakeLXC) run(args ...string) (string, error) {
+	call := strings.Join(args, " ")
+	f.calls = append(f.calls, call)
+	if out, ok := f.outputs[call]; ok {
+		return out, nil
+	}
+	if strings.HasPrefix(call, "config device set") || strings.HasPrefix(call, "config device get") {
+		return "", errors.New("device not found")
+	}
+	return "", nil
+}
+
+func TestSetLimits(t *testing.T) {
+	mgr, _ := testManager(t)
+	lxc := &fakeLXC{}
+	mgr.lxc = lxc.run
+
+	err := mgr.SetLimits("1", config.ResourcesConfig{CPU: 2, Memory: "4GiB", Disk: "20GiB", Processes: 500})
+	if err != nil {
+		t.Fatalf("SetLimits() error = %v", err)
+	}
+
+	want := []string{
+		"config set worker-1 limits.cpu 2",
+		"config set worker-1 limits.memory 4GiB",
+		"config set worker-1 limits.processes 500",
+		"config device set worker-1 root size=20GiB",
+		"config device override worker-1 root size=20GiB",
+	}
+	if strings.Join(lxc.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lxc calls =\n%s\nwant\n%s", strings.Join(lxc.calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSetLimits_OnlyChangesGivenLimits(t *testing.T) {
+	mgr, _ := testManager(t)
+	lxc := &fakeLXC{}
+	mgr.lxc = lxc.run
+
+	if err := mgr.SetLimits("worker-1", config.ResourcesConfig{Memory: "50%"}); err != nil {
+		t.Fatalf("SetLimits() error = %v", err)
+	}
+	if len(lxc.calls) != 1 || lxc.calls[0] != "config set worker-1 limits.memory 50%" {
+		t.Errorf("lxc calls = %v, want only limits.memory", lxc.calls)
+	}
+}
+
+func TestSetLimits_Invalid(t *testing.T) {
+	mgr, _ := testManager(t)
+	lxc := &fakeLXC{}
+	mgr.lxc = lxc.run
+
+	if err := mgr.SetLimits("worker-1", config.ResourcesConfig{Memory: "lots"}); err == nil {
+		t.Fatal("SetLimits() error = nil, want validation error")
+	}
+	if len(lxc.calls) != 0 {
+		t.Errorf("lxc called with invalid limits: %v", lxc.calls)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	mgr, _ := testManager(t)
+	lxc := &fakeLXC{outputs: map[string]string{
+		"config get worker-1 limits.cpu":       "4",
+		"config get worker-1 limits.memory":    "8GiB",
+		"config device get worker-1 root size": "30GiB",
+		"config get worker-1 limits.processes": "",
+	}}
+	mgr.lxc = lxc.run
+
+	got, err := mgr.Limits("worker-1")
+	if err != nil {
+		t.Fatalf("Limits() error = %v", err)
+	}
+	want := config.ResourcesConfig{CPU: 4, Memory: "8GiB", Disk: "30GiB"}
+	if got != want {
+		t.Errorf("Limits() = %+v, want %+v", got, want)
+	}
+}
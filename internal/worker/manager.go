@@ -6,15 +6,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"lxc-dev-manager/pkg/lxcmgr"
 
 	"isollm/internal/airyra"
+	"isollm/internal/claude"
 	"isollm/internal/config"
+	"isollm/internal/events"
+	"isollm/internal/hooks"
+	"isollm/internal/logs"
+	"isollm/internal/notes"
+	"isollm/internal/plans"
+	"isollm/internal/state"
+	"isollm/internal/verify"
 )
 
 const (
@@ -32,11 +42,27 @@ const (
 // It handles worker naming and task state only - all container
 // management is delegated to lxc-dev-manager.
 type Manager struct {
-	client   *lxcmgr.Client
-	cfg      *config.Config
-	stateDir string
-	bareRepo string
-	airyra   airyra.TaskClient // May be nil if airyra is not running
+	client      *lxcmgr.Client
+	cfg         *config.Config
+	projectDir  string
+	stateDir    string      // The project's .isollm directory
+	workerState state.State // Task assignments and provisioning failures
+	bareRepo    string
+	airyra      airyra.TaskClient // May be nil if airyra is not running
+	notes       *notes.Store
+	plans       *plans.Store  // Tasks to be split by a planner worker
+	checks      *verify.Store // Task-specific verify commands
+	logs        *logs.Store
+	events      *events.Log // May be nil (tests)
+	lxc         LXCRunner
+
+	// createMu serializes container creation, which updates the
+	// lxc-dev-manager project file
+	createMu sync.Mutex
+
+	// agentClient creates an airyra client acting as the given agent.
+	// When nil, the host client is used (tests).
+	agentClient func(agentID string) (airyra.TaskClient, error)
 }
 
 // WorkerInfo contains combined information about a worker
@@ -48,6 +74,7 @@ type WorkerInfo struct {
 	TaskID    string
 	Branch    string
 	ClaimedAt time.Time
+	Error     string // Set when provisioning failed; the worker is unusable
 }
 
 // NewManager creates a Manager for the given project directory
@@ -90,17 +117,34 @@ func NewManager(projectDir string, cfg *config.Config) (*Manager, error) {
 	// Initialize airyra client for task operations (non-fatal if not running)
 	airyraClient, _ := airyra.NewClientFromConfig(cfg)
 
+	eventLog := events.New(projectDir)
+	hooks.Attach(eventLog, cfg)
+
 	return &Manager{
-		client:   client,
-		cfg:      cfg,
-		stateDir: filepath.Join(projectDir, config.StateDir, "tasks"),
-		bareRepo: bareRepo,
-		airyra:   airyraClient,
+		client:      client,
+		cfg:         cfg,
+		projectDir:  projectDir,
+		stateDir:    filepath.Join(projectDir, config.StateDir),
+		workerState: state.New(projectDir),
+		bareRepo:    bareRepo,
+		airyra:      airyraClient,
+		notes:       notes.New(projectDir),
+		plans:       plans.New(projectDir),
+		checks:      verify.NewStore(projectDir),
+		logs:        logs.New(projectDir),
+		events:      eventLog,
+		lxc:         runLXC,
+		agentClient: func(agentID string) (airyra.TaskClient, error) {
+			return airyra.NewClient(cfg, agentID)
+		},
 	}, nil
 }
 
 // CreateWorker creates a new worker container.
 // If name is empty, the next available worker name is used.
+// The first worker is provisioned from cfg.Image and published as the
+// project's cached image; later workers are copied from it while the
+// provisioning config is unchanged.
 func (m *Manager) CreateWorker(name string) error {
 	if name == "" {
 		name = m.nextWorkerName()
@@ -110,25 +154,67 @@ func (m *Manager) CreateWorker(name string) error {
 		name = WorkerPrefix + name
 	}
 
-	// 1. Create container with dev user
-	err := m.client.CreateContainer(name, m.cfg.Image,
-		lxcmgr.WithUser("dev", "dev"),
-	)
+	// 1. Create container, from the cached image if there is one. The
+	// cached image already has the dev user.
+	image := m.cachedImage()
+	var err error
+	m.createMu.Lock()
+	if image != "" {
+		err = m.client.CreateContainer(name, image)
+	} else {
+		err = m.client.CreateContainer(name, m.cfg.Image,
+			lxcmgr.WithUser("dev", "dev"),
+		)
+	}
+	m.createMu.Unlock()
 	if err != nil {
 		return fmt.Errorf("failed to create container: %w", err)
 	}
 
-	// 2. Start the container
+	// From here on the container exists; a failure leaves it in place,
+	// marked as failed, so it can be inspected and is not handed tasks
+	if err := m.setupWorker(name, image); err != nil {
+		m.markError(name, err)
+		m.emit(events.Event{Type: events.WorkerFailed, Worker: name, Message: err.Error()})
+		return err
+	}
+
+	// Forget any failure recorded for an earlier worker of the same name
+	if err := m.workerState.DeleteWorker(name); err != nil {
+		return fmt.Errorf("failed to clear worker state: %w", err)
+	}
+
+	created := "from " + m.cfg.Image
+	if image != "" {
+		created = "from cached image " + image
+	}
+	m.emit(events.Event{Type: events.WorkerCreated, Worker: name, Message: created})
+	return nil
+}
+
+// setupWorker readies a created container: it applies limits, starts it,
+// mounts the repo and logs, clones and provisions the project, takes the
+// clean snapshot and then applies the network policy. image is the cached
+// image the container was created from, or "".
+func (m *Manager) setupWorker(name, image string) error {
+	// 2. Apply resource limits before anything runs in it
+	if !m.cfg.Resources.IsZero() {
+		if err := m.SetLimits(name, m.cfg.Resources); err != nil {
+			return fmt.Errorf("failed to apply resource limits: %w", err)
+		}
+	}
+
+	// 3. Start the container
 	if err := m.client.Start(name); err != nil {
 		return fmt.Errorf("failed to start container: %w", err)
 	}
 
-	// 3. Wait for container to be ready
+	// 4. Wait for container to be ready
 	if err := m.client.WaitForReady(name, 60*time.Second); err != nil {
 		return fmt.Errorf("container not ready: %w", err)
 	}
 
-	// 4. Mount bare repo with UID shifting
+	// 5. Mount bare repo with UID shifting
 	if err := m.client.Mount(name, m.bareRepo, RepoMountPath,
 		lxcmgr.WithMountName("repo"),
 		lxcmgr.WithReadWrite(),
@@ -137,59 +223,169 @@ func (m *Manager) CreateWorker(name string) error {
 		return fmt.Errorf("failed to mount bare repo: %w", err)
 	}
 
-	// 5. Clone repo in container
-	_, err = m.client.Exec(name, []string{
-		"git", "clone", RepoMountPath, ProjectPath,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to clone repo in container: %w", err)
+	// 6. Mount the host log directory so pane output lands on the host
+	logDir := m.logs.WorkerDir(name)
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := m.client.Mount(name, logDir, claude.LogMountPath,
+		lxcmgr.WithMountName("logs"),
+		lxcmgr.WithReadWrite(),
+		lxcmgr.WithShift(),
+	); err != nil {
+		return fmt.Errorf("failed to mount log directory: %w", err)
 	}
 
-	// 6. Set git config in the cloned repo
-	_, err = m.client.Exec(name, []string{
-		"git", "-C", ProjectPath, "config", "user.name", "Worker",
-	})
+	log, err := m.provisionLog(name)
 	if err != nil {
-		return fmt.Errorf("failed to set git user.name: %w", err)
+		return err
 	}
+	defer log.Close()
 
-	_, err = m.client.Exec(name, []string{
-		"git", "-C", ProjectPath, "config", "user.email", "worker@isollm.local",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set git user.email: %w", err)
+	if image != "" {
+		// 7. The cached image is already cloned and provisioned; bring
+		// the clone up to date
+		fmt.Fprintf(log, "==> created from cached image %s\n", image)
+		if err := m.updateClone(name); err != nil {
+			return err
+		}
+	} else {
+		// 7. Clone repo in container
+		_, err := m.client.Exec(name, []string{
+			"git", "clone", RepoMountPath, ProjectPath,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to clone repo in container: %w", err)
+		}
+
+		// 8. Set git config in the cloned repo
+		_, err = m.client.Exec(name, []string{
+			"git", "-C", ProjectPath, "config", "user.name", "Worker",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set git user.name: %w", err)
+		}
+
+		_, err = m.client.Exec(name, []string{
+			"git", "-C", ProjectPath, "config", "user.email", "worker@isollm.local",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set git user.email: %w", err)
+		}
+
+		// 9. Provision, so the clean snapshot includes everything
+		if err := NewProvisioner(m, m.projectDir, m.cfg, log).Run(name); err != nil {
+			return fmt.Errorf("failed to provision %s (see 'isollm worker logs %s --task %s'): %w",
+				name, name, logs.ProvisionLog, err)
+		}
 	}
 
-	// 7. Create "clean" snapshot for reset
-	if err := m.client.CreateSnapshot(name, CleanSnapshotName, "Clean state after repo clone"); err != nil {
+	// 10. Create "clean" snapshot for reset
+	if err := m.client.CreateSnapshot(name, CleanSnapshotName, "Clean state after provisioning"); err != nil {
 		return fmt.Errorf("failed to create clean snapshot: %w", err)
 	}
 
+	// 11. Cache the provisioned worker for the next ones. The worker is
+	// fine without it, so a failure is only logged.
+	if image == "" {
+		if err := m.cacheImage(name, log); err != nil {
+			fmt.Fprintf(log, "warning: %v\n", err)
+		}
+	}
+
+	// 12. Restrict outbound traffic only now, so provisioning can reach
+	// package mirrors. It is not part of the snapshot or the cached image.
+	return m.restrictNetwork(name)
+}
+
+// CreateWorkers creates workers in parallel, at most parallel at a time,
+// calling report as each one finishes. A failure does not stop the others.
+// Without a cached image the first worker is created on its own, so the
+// rest are copied from the image it leaves behind.
+func (m *Manager) CreateWorkers(names []string, parallel int, report func(Result)) []Result {
+	if len(names) > 1 && m.cachedImage() == "" {
+		first := ForEach(names[:1], 1, m.CreateWorker, report)
+		return append(first, ForEach(names[1:], parallel, m.CreateWorker, report)...)
+	}
+	return ForEach(names, parallel, m.CreateWorker, report)
+}
+
+// provisionLog opens the worker's provision log for appending
+func (m *Manager) provisionLog(name string) (*os.File, error) {
+	if err := m.logs.Prepare(name, logs.ProvisionLog); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(m.logs.Path(name, logs.ProvisionLog), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open provision log: %w", err)
+	}
+	return f, nil
+}
+
+// updateClone fast-forwards a worker's project clone to the bare repo
+func (m *Manager) updateClone(name string) error {
+	if _, err := m.client.Exec(name, []string{
+		"git", "-C", ProjectPath, "fetch", "origin",
+	}); err != nil {
+		return fmt.Errorf("failed to fetch in %s: %w", name, err)
+	}
+	if _, err := m.client.Exec(name, []string{
+		"git", "-C", ProjectPath, "reset", "--hard", "@{upstream}",
+	}); err != nil {
+		return fmt.Errorf("failed to update clone in %s: %w", name, err)
+	}
 	return nil
 }
 
+// markError records that a worker failed to be set up or provisioned. The
+// container is kept, without a clean snapshot, so it can be inspected.
+func (m *Manager) markError(name string, cause error) {
+	now := time.Now()
+	m.workerState.UpdateWorker(name, func(w *state.WorkerState) {
+		w.Status = state.WorkerStatusError
+		w.StartedAt = now
+		w.LastError = cause.Error()
+		w.ErrorTime = now
+	})
+}
+
 // Start starts a stopped worker
 func (m *Manager) Start(name string) error {
 	name = m.normalizeName(name)
-	return m.client.Start(name)
+	if err := m.client.Start(name); err != nil {
+		return err
+	}
+	m.emit(events.Event{Type: events.WorkerStarted, Worker: name})
+	return nil
 }
 
 // Stop stops a running worker
 func (m *Manager) Stop(name string) error {
 	name = m.normalizeName(name)
-	return m.client.Stop(name)
+	if err := m.client.Stop(name); err != nil {
+		return err
+	}
+	m.emit(events.Event{Type: events.WorkerStopped, Worker: name})
+	return nil
 }
 
 // Remove removes a worker container
 func (m *Manager) Remove(name string, force bool) error {
 	name = m.normalizeName(name)
 
-	// Clear any task state
-	if err := ClearTaskState(m.stateDir, name); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to clear task state: %w", err)
+	if err := m.client.Remove(name, force); err != nil {
+		return err
+	}
+
+	if err := m.workerState.DeleteWorker(name); err != nil {
+		return fmt.Errorf("failed to clear worker state: %w", err)
 	}
 
-	return m.client.Remove(name, force)
+	// The ACL can only be deleted once no container uses it
+	m.removeNetworkPolicy(name)
+
+	m.emit(events.Event{Type: events.WorkerRemoved, Worker: name})
+	return nil
 }
 
 // Reset resets a worker to its clean snapshot state
@@ -197,11 +393,19 @@ func (m *Manager) Reset(name string) error {
 	name = m.normalizeName(name)
 
 	// Clear task state
-	if err := ClearTaskState(m.stateDir, name); err != nil && !os.IsNotExist(err) {
+	if err := m.clearTask(name); err != nil {
 		return fmt.Errorf("failed to clear task state: %w", err)
 	}
 
-	return m.client.Reset(name, CleanSnapshotName)
+	if err := m.client.Reset(name, CleanSnapshotName); err != nil {
+		return err
+	}
+	// The snapshot predates the network policy
+	if err := m.restrictNetwork(name); err != nil {
+		return err
+	}
+	m.emit(events.Event{Type: events.WorkerReset, Worker: name})
+	return nil
 }
 
 // Shell opens an interactive shell in a worker
@@ -238,13 +442,15 @@ func (m *Manager) List() ([]WorkerInfo, error) {
 		}
 
 		// Load task state if available
-		state, err := LoadTaskState(m.stateDir, c.Name)
-		if err == nil && state != nil {
-			info.TaskID = state.TaskID
-			info.Branch = state.Branch
-			info.ClaimedAt = state.ClaimedAt
+		taskState, err := m.getTask(c.Name)
+		if err == nil && taskState != nil {
+			info.TaskID = taskState.TaskID
+			info.Branch = taskState.Branch
+			info.ClaimedAt = taskState.ClaimedAt
 		}
 
+		info.Error = m.provisionError(c.Name)
+
 		workers = append(workers, info)
 	}
 
@@ -256,6 +462,24 @@ func (m *Manager) List() ([]WorkerInfo, error) {
 	return workers, nil
 }
 
+// provisionError returns why a worker failed to provision, or ""
+func (m *Manager) provisionError(name string) string {
+	if m.workerState == nil {
+		return ""
+	}
+	ws, err := m.workerState.LoadWorker(name)
+	if err != nil || ws.Status != state.WorkerStatusError {
+		return ""
+	}
+	return ws.LastError
+}
+
+// ProvisionError returns why a worker failed to provision, or "" if it is
+// usable
+func (m *Manager) ProvisionError(name string) string {
+	return m.provisionError(m.normalizeName(name))
+}
+
 // Status returns the status of a worker
 func (m *Manager) Status(name string) (lxcmgr.ContainerStatus, error) {
 	name = m.normalizeName(name)
@@ -284,26 +508,49 @@ func (m *Manager) StartProxy(name string) (*lxcmgr.ProxyManager, error) {
 func (m *Manager) AssignTask(name, taskID, branch string) error {
 	name = m.normalizeName(name)
 
-	state := &TaskState{
-		WorkerName: name,
-		TaskID:     taskID,
-		Branch:     branch,
-		ClaimedAt:  time.Now(),
-	}
-
-	return SaveTaskState(m.stateDir, state)
+	return m.workerState.UpdateWorker(name, func(w *state.WorkerState) {
+		w.CurrentTask = &state.TaskRef{ID: taskID, ClaimedAt: time.Now()}
+		w.CurrentBranch = branch
+		w.LastActivity = time.Now()
+		if w.Status != state.WorkerStatusError {
+			w.Status = state.WorkerStatusBusy
+		}
+	})
 }
 
 // ClearTask clears the task assignment from a worker
 func (m *Manager) ClearTask(name string) error {
-	name = m.normalizeName(name)
-	return ClearTaskState(m.stateDir, name)
+	return m.clearTask(m.normalizeName(name))
+}
+
+// clearTask clears a worker's task assignment
+func (m *Manager) clearTask(name string) error {
+	return m.workerState.UpdateWorker(name, func(w *state.WorkerState) {
+		w.CurrentTask = nil
+		w.CurrentBranch = ""
+		w.LastActivity = time.Now()
+		if w.Status == state.WorkerStatusBusy || w.Status == "" {
+			w.Status = state.WorkerStatusIdle
+		}
+	})
 }
 
-// GetTask returns the current task state for a worker
+// GetTask returns the current task state for a worker, or nil if it has
+// no task
 func (m *Manager) GetTask(name string) (*TaskState, error) {
-	name = m.normalizeName(name)
-	return LoadTaskState(m.stateDir, name)
+	return m.getTask(m.normalizeName(name))
+}
+
+// getTask returns a worker's task assignment, or nil if it has none
+func (m *Manager) getTask(name string) (*TaskState, error) {
+	w, err := m.workerState.LoadWorker(name)
+	if errors.Is(err, state.ErrNoWorker) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return taskStateOf(w), nil
 }
 
 // ListSnapshots returns all snapshots for a worker
@@ -357,14 +604,113 @@ func (m *Manager) ClaimNextTask(ctx context.Context, workerName string) (*airyra
 	}
 
 	// Update local state
-	branchName := fmt.Sprintf("%s%s", m.cfg.Git.BranchPrefix, task.ID)
-	if err := m.AssignTask(workerName, task.ID, branchName); err != nil {
+	if err := m.AssignTask(workerName, task.ID, m.TaskBranch(task.ID)); err != nil {
 		// Log but don't fail - airyra is authoritative
 	}
 
+	m.emitTask(events.TaskClaimed, workerName, task.ID, task.Title)
+	return task, nil
+}
+
+// ClaimTask claims a specific task on behalf of a worker.
+// The claim is made with the worker's own agent ID so that the worker can
+// later complete, block or release the task from inside its container.
+func (m *Manager) ClaimTask(ctx context.Context, workerName, taskID string) (*airyra.Task, error) {
+	workerName = m.normalizeName(workerName)
+
+	client, err := m.clientFor(workerName)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.ClaimTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+
+	if err := m.AssignTask(workerName, task.ID, m.TaskBranch(task.ID)); err != nil {
+		return nil, fmt.Errorf("failed to save task state: %w", err)
+	}
+
+	m.emitTask(events.TaskClaimed, workerName, task.ID, task.Title)
 	return task, nil
 }
 
+// CreateTaskBranch checks out a fresh task branch from the base branch
+// inside a worker's project clone
+func (m *Manager) CreateTaskBranch(workerName, branch string) error {
+	workerName = m.normalizeName(workerName)
+
+	if _, err := m.client.Exec(workerName, []string{
+		"git", "-C", ProjectPath, "fetch", "origin",
+	}); err != nil {
+		return fmt.Errorf("failed to fetch in %s: %w", workerName, err)
+	}
+
+	base := "origin/" + m.cfg.Git.BaseBranch
+	if _, err := m.client.Exec(workerName, []string{
+		"git", "-C", ProjectPath, "checkout", "-B", branch, base,
+	}); err != nil {
+		return fmt.Errorf("failed to create branch %s in %s: %w", branch, workerName, err)
+	}
+
+	return nil
+}
+
+// TaskBranch returns the branch name used for a task
+func (m *Manager) TaskBranch(taskID string) string {
+	return m.cfg.Git.BranchPrefix + taskID
+}
+
+// SalvageBranch commits any uncommitted work in a worker's project clone
+// and pushes the branch to the bare repo so it survives a reclaim
+func (m *Manager) SalvageBranch(workerName, branch string) error {
+	workerName = m.normalizeName(workerName)
+
+	status, err := m.client.Exec(workerName, []string{
+		"git", "-C", ProjectPath, "status", "--porcelain",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check status in %s: %w", workerName, err)
+	}
+
+	if strings.TrimSpace(string(status)) != "" {
+		if _, err := m.client.Exec(workerName, []string{
+			"git", "-C", ProjectPath, "add", "-A",
+		}); err != nil {
+			return fmt.Errorf("failed to stage changes in %s: %w", workerName, err)
+		}
+		// CLAUDE.md is rewritten by isollm for each task, not part of the
+		// work, even when the project tracks a CLAUDE.md of its own
+		if _, err := m.client.Exec(workerName, []string{
+			"git", "-C", ProjectPath, "reset", "-q", "--", "CLAUDE.md",
+		}); err != nil {
+			return fmt.Errorf("failed to unstage CLAUDE.md in %s: %w", workerName, err)
+		}
+
+		// Nothing is left to commit when CLAUDE.md was the only change
+		if _, err := m.client.Exec(workerName, []string{
+			"git", "-C", ProjectPath, "diff", "--cached", "--quiet",
+		}); err != nil {
+			if _, err := m.client.Exec(workerName, []string{
+				"git", "-C", ProjectPath, "commit", "--no-verify",
+				"-m", "isollm salvage: uncommitted work from " + workerName,
+			}); err != nil {
+				return fmt.Errorf("failed to commit salvaged work in %s: %w", workerName, err)
+			}
+		}
+	}
+
+	if _, err := m.client.Exec(workerName, []string{
+		"git", "-C", ProjectPath, "push", "-u", "origin", branch,
+	}); err != nil {
+		return fmt.Errorf("failed to push %s from %s: %w", branch, workerName, err)
+	}
+
+	m.emit(events.Event{Type: events.BranchSalvaged, Worker: workerName, Branch: branch})
+	return nil
+}
+
 // ReleaseWorkerTask releases the task a worker is working on
 func (m *Manager) ReleaseWorkerTask(ctx context.Context, workerName string) error {
 	if m.airyra == nil {
@@ -378,15 +724,23 @@ func (m *Manager) ReleaseWorkerTask(ctx context.Context, workerName string) erro
 		return nil // No task to release
 	}
 
-	_, err = m.airyra.ReleaseTask(ctx, state.TaskID, false)
+	client, err := m.clientFor(workerName)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.ReleaseTask(ctx, state.TaskID, false)
 	if err != nil && !airyra.IsNotOwner(err) {
 		return fmt.Errorf("failed to release task: %w", err)
 	}
 
+	m.emitTask(events.TaskReleased, workerName, state.TaskID, "")
 	return m.ClearTask(workerName)
 }
 
-// CompleteWorkerTask marks the worker's current task as done
+// CompleteWorkerTask marks the worker's current task as done.
+// If verify commands are configured they must pass first; on failure the
+// task is blocked and a *verify.FailedError is returned.
 func (m *Manager) CompleteWorkerTask(ctx context.Context, workerName string) error {
 	if m.airyra == nil {
 		return fmt.Errorf("airyra client not initialized")
@@ -399,16 +753,80 @@ func (m *Manager) CompleteWorkerTask(ctx context.Context, workerName string) err
 		return fmt.Errorf("worker has no assigned task")
 	}
 
-	_, err = m.airyra.CompleteTask(ctx, state.TaskID)
+	client, err := m.clientFor(workerName)
+	if err != nil {
+		return err
+	}
+
+	commands, err := m.verifyCommands(state.TaskID)
+	if err != nil {
+		return err
+	}
+	if len(commands) > 0 {
+		runner := verify.New(m, ProjectPath, commands)
+		if err := runner.Gate(ctx, client, m.notes, workerName, state.TaskID, state.Branch); err != nil {
+			var failed *verify.FailedError
+			if errors.As(err, &failed) {
+				m.emitTask(events.TaskBlocked, workerName, state.TaskID, failed.Error())
+			}
+			return err
+		}
+	}
+
+	_, err = client.CompleteTask(ctx, state.TaskID)
 	if err != nil {
 		return fmt.Errorf("failed to complete task: %w", err)
 	}
 
+	m.emitTask(events.TaskCompleted, workerName, state.TaskID, "")
 	return m.ClearTask(workerName)
 }
 
-// BlockWorkerTask marks the worker's current task as blocked
-func (m *Manager) BlockWorkerTask(ctx context.Context, workerName string) error {
+// TaskVerifyCommands returns the checks particular to a task, run after
+// the project's verify commands. Errors reading them are ignored.
+func (m *Manager) TaskVerifyCommands(taskID string) []string {
+	if m.checks == nil {
+		return nil
+	}
+	commands, _ := m.checks.Get(taskID)
+	return commands
+}
+
+// verifyCommands returns every check a task must pass before it is done
+func (m *Manager) verifyCommands(taskID string) ([]string, error) {
+	commands := m.cfg.Verify.Commands
+	if m.checks == nil {
+		return commands, nil
+	}
+	extra, err := m.checks.Get(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return append(slices.Clone(commands), extra...), nil
+}
+
+// DoneRequested reports whether Claude asked for its task to be completed.
+// The request stays until ClearDoneRequest, so a completion that fails
+// for a passing reason is tried again.
+func (m *Manager) DoneRequested(workerName string) bool {
+	workerName = m.normalizeName(workerName)
+	_, err := m.client.Exec(workerName, []string{"test", "-e", claude.DoneMarkerPath})
+	return err == nil
+}
+
+// ClearDoneRequest removes Claude's request to complete its task once the
+// task is completed or blocked by verification
+func (m *Manager) ClearDoneRequest(workerName string) error {
+	workerName = m.normalizeName(workerName)
+	if _, err := m.client.Exec(workerName, []string{"rm", "-f", claude.DoneMarkerPath}); err != nil {
+		return fmt.Errorf("failed to clear done request in %s: %w", workerName, err)
+	}
+	return nil
+}
+
+// BlockWorkerTask marks the worker's current task as blocked, recording
+// the reason Claude gave
+func (m *Manager) BlockWorkerTask(ctx context.Context, workerName, reason string) error {
 	if m.airyra == nil {
 		return fmt.Errorf("airyra client not initialized")
 	}
@@ -420,12 +838,43 @@ func (m *Manager) BlockWorkerTask(ctx context.Context, workerName string) error
 		return fmt.Errorf("worker has no assigned task")
 	}
 
-	_, err = m.airyra.BlockTask(ctx, state.TaskID)
-	if err != nil {
-		return fmt.Errorf("failed to block task: %w", err)
+	_, err = m.block(ctx, workerName, state.TaskID, reason, workerName)
+	return err
+}
+
+// emit records an event. The event log is a record only, so a failure to
+// write it never fails the operation.
+func (m *Manager) emit(e events.Event) {
+	if m.events != nil {
+		m.events.Emit(e)
 	}
+}
 
-	return nil
+// emitTask records a task event for a worker
+func (m *Manager) emitTask(t events.Type, workerName, taskID, message string) {
+	m.emit(events.Event{
+		Type:    t,
+		Worker:  workerName,
+		TaskID:  taskID,
+		Branch:  m.TaskBranch(taskID),
+		Message: message,
+	})
+}
+
+// clientFor returns an airyra client acting as the given worker
+func (m *Manager) clientFor(workerName string) (airyra.TaskClient, error) {
+	if m.agentClient == nil {
+		if m.airyra == nil {
+			return nil, fmt.Errorf("airyra client not initialized")
+		}
+		return m.airyra, nil
+	}
+
+	client, err := m.agentClient(workerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create airyra client for %s: %w", workerName, err)
+	}
+	return client, nil
 }
 
 // HasAiryra returns true if the airyra client is available
@@ -454,6 +903,7 @@ func (m *Manager) GetStateDir() string {
 // SetStateDir sets the state directory path (for testing)
 func (m *Manager) SetStateDir(dir string) {
 	m.stateDir = dir
+	m.workerState = state.NewWithDir(dir)
 }
 
 // LXCClient returns the underlying LXC client for direct access
@@ -491,22 +941,32 @@ func (m *Manager) ExecInWorker(workerName string, command string, args ...string
 
 // nextWorkerName returns the next available worker name
 func (m *Manager) nextWorkerName() string {
-	containers, err := m.client.List()
-	if err != nil {
-		return WorkerPrefix + "1"
+	return m.NextWorkerNames(1)[0]
+}
+
+// NextWorkerNames returns the next n unused worker-N names
+func (m *Manager) NextWorkerNames(n int) []string {
+	if n <= 0 {
+		return nil
 	}
 
 	maxNum := 0
-	for _, c := range containers {
-		if strings.HasPrefix(c.Name, WorkerPrefix) {
-			numStr := strings.TrimPrefix(c.Name, WorkerPrefix)
-			if num, err := strconv.Atoi(numStr); err == nil && num > maxNum {
-				maxNum = num
+	if containers, err := m.client.List(); err == nil {
+		for _, c := range containers {
+			if strings.HasPrefix(c.Name, WorkerPrefix) {
+				numStr := strings.TrimPrefix(c.Name, WorkerPrefix)
+				if num, err := strconv.Atoi(numStr); err == nil && num > maxNum {
+					maxNum = num
+				}
 			}
 		}
 	}
 
-	return WorkerPrefix + strconv.Itoa(maxNum+1)
+	names := make([]string, n)
+	for i := range names {
+		names[i] = WorkerPrefix + strconv.Itoa(maxNum+i+1)
+	}
+	return names
 }
 
 // getBareRepoPath returns the standard bare repo path for a project
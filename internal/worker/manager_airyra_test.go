@@ -11,6 +11,8 @@ import (
 
 	"isollm/internal/airyra"
 	"isollm/internal/config"
+	"isollm/internal/events"
+	"isollm/internal/state"
 )
 
 // testManager creates a Manager with a mock airyra client for testing.
@@ -18,8 +20,7 @@ import (
 func testManager(t *testing.T) (*Manager, *airyra.MockClient) {
 	t.Helper()
 
-	tmpDir := t.TempDir()
-	stateDir := filepath.Join(tmpDir, "tasks")
+	stateDir := t.TempDir()
 
 	mock := airyra.NewMockClient()
 
@@ -31,9 +32,10 @@ func testManager(t *testing.T) (*Manager, *airyra.MockClient) {
 	}
 
 	mgr := &Manager{
-		cfg:      cfg,
-		stateDir: stateDir,
-		airyra:   mock,
+		cfg:         cfg,
+		stateDir:    stateDir,
+		workerState: state.NewWithDir(stateDir),
+		airyra:      mock,
 	}
 
 	return mgr, mock
@@ -174,6 +176,76 @@ func TestManager_ClaimNextTask_RaceCondition(t *testing.T) {
 	}
 }
 
+func TestManager_ClaimTask(t *testing.T) {
+	mgr, mock := testManager(t)
+	ctx := context.Background()
+
+	mock.AddTask(ctx, "Task 1")
+	task2, _ := mock.AddTask(ctx, "Task 2")
+
+	task, err := mgr.ClaimTask(ctx, "worker-1", task2.ID)
+	if err != nil {
+		t.Fatalf("ClaimTask() error = %v", err)
+	}
+	if task.ID != task2.ID {
+		t.Errorf("ClaimTask() claimed %q, want %q", task.ID, task2.ID)
+	}
+
+	state, _ := mgr.GetTask("worker-1")
+	if state == nil || state.TaskID != task2.ID {
+		t.Fatalf("Local state = %+v, want task %s", state, task2.ID)
+	}
+	if state.Branch != "isollm/"+task2.ID {
+		t.Errorf("Local state Branch = %q, want %q", state.Branch, "isollm/"+task2.ID)
+	}
+}
+
+func TestManager_ClaimTask_UsesWorkerAgent(t *testing.T) {
+	mgr, mock := testManager(t)
+	ctx := context.Background()
+
+	var agents []string
+	mgr.agentClient = func(agentID string) (airyra.TaskClient, error) {
+		agents = append(agents, agentID)
+		return mock, nil
+	}
+
+	task, _ := mock.AddTask(ctx, "Task 1")
+	if _, err := mgr.ClaimTask(ctx, "1", task.ID); err != nil {
+		t.Fatalf("ClaimTask() error = %v", err)
+	}
+
+	if len(agents) != 1 || agents[0] != "worker-1" {
+		t.Errorf("agent clients created for %v, want [worker-1]", agents)
+	}
+}
+
+func TestManager_ClaimTask_AlreadyClaimed(t *testing.T) {
+	mgr, mock := testManager(t)
+	ctx := context.Background()
+
+	task, _ := mock.AddTask(ctx, "Task 1")
+	mock.ClaimTask(ctx, task.ID)
+
+	_, err := mgr.ClaimTask(ctx, "worker-1", task.ID)
+	if !airyra.IsAlreadyClaimed(err) {
+		t.Errorf("ClaimTask() error = %v, want already claimed", err)
+	}
+
+	state, _ := mgr.GetTask("worker-1")
+	if state != nil {
+		t.Errorf("Local state = %+v, want nil after failed claim", state)
+	}
+}
+
+func TestManager_TaskBranch(t *testing.T) {
+	mgr, _ := testManager(t)
+
+	if got := mgr.TaskBranch("ar-0001"); got != "isollm/ar-0001" {
+		t.Errorf("TaskBranch() = %q, want %q", got, "isollm/ar-0001")
+	}
+}
+
 func TestManager_ReleaseWorkerTask(t *testing.T) {
 	mgr, mock := testManager(t)
 	ctx := context.Background()
@@ -286,7 +358,7 @@ func TestManager_BlockWorkerTask(t *testing.T) {
 	task, _ := mgr.ClaimNextTask(ctx, "worker-1")
 
 	// Block it
-	err := mgr.BlockWorkerTask(ctx, "worker-1")
+	err := mgr.BlockWorkerTask(ctx, "worker-1", "")
 	if err != nil {
 		t.Fatalf("BlockWorkerTask() error = %v", err)
 	}
@@ -308,7 +380,7 @@ func TestManager_BlockWorkerTask_NoTask(t *testing.T) {
 	mgr, _ := testManager(t)
 	ctx := context.Background()
 
-	err := mgr.BlockWorkerTask(ctx, "worker-1")
+	err := mgr.BlockWorkerTask(ctx, "worker-1", "")
 	if err == nil {
 		t.Error("BlockWorkerTask() with no task = nil, want error")
 	}
@@ -327,12 +399,12 @@ func TestManager_WorkerNameNormalization(t *testing.T) {
 	}
 
 	// State should be saved with normalized name
-	state, _ := LoadTaskState(mgr.stateDir, "worker-1")
-	if state == nil {
-		t.Error("State not found with normalized worker name")
+	ts, _ := mgr.getTask("worker-1")
+	if ts == nil {
+		t.Fatal("State not found with normalized worker name")
 	}
-	if state.TaskID != task.ID {
-		t.Errorf("State TaskID = %q, want %q", state.TaskID, task.ID)
+	if ts.TaskID != task.ID {
+		t.Errorf("State TaskID = %q, want %q", ts.TaskID, task.ID)
 	}
 }
 
@@ -345,25 +417,37 @@ func TestManager_LocalStatePersistence(t *testing.T) {
 	task, _ := mgr.ClaimNextTask(ctx, "worker-1")
 
 	// Verify state file exists
-	statePath := filepath.Join(mgr.stateDir, "worker-1.json")
+	statePath := filepath.Join(mgr.stateDir, "workers", "worker-1.json")
 	if _, err := os.Stat(statePath); os.IsNotExist(err) {
 		t.Error("State file not created")
 	}
 
 	// Load and verify state
-	state, err := LoadTaskState(mgr.stateDir, "worker-1")
+	ws, err := state.NewWithDir(mgr.stateDir).LoadWorker("worker-1")
 	if err != nil {
-		t.Fatalf("LoadTaskState() error = %v", err)
+		t.Fatalf("LoadWorker() error = %v", err)
 	}
-	if state.TaskID != task.ID {
-		t.Errorf("State TaskID = %q, want %q", state.TaskID, task.ID)
+	if !ws.HasTask() || ws.CurrentTask.ID != task.ID {
+		t.Errorf("State CurrentTask = %+v, want %q", ws.CurrentTask, task.ID)
 	}
-	if state.Branch != "isollm/"+task.ID {
-		t.Errorf("State Branch = %q, want %q", state.Branch, "isollm/"+task.ID)
+	if ws.CurrentBranch != "isollm/"+task.ID {
+		t.Errorf("State CurrentBranch = %q, want %q", ws.CurrentBranch, "isollm/"+task.ID)
 	}
-	if state.ClaimedAt.IsZero() {
+	if ws.CurrentTask.ClaimedAt.IsZero() {
 		t.Error("State ClaimedAt is zero")
 	}
+	if ws.Status != state.WorkerStatusBusy {
+		t.Errorf("State Status = %q, want busy", ws.Status)
+	}
+
+	// Clearing the task keeps the worker's state
+	if err := mgr.ClearTask("worker-1"); err != nil {
+		t.Fatalf("ClearTask() error = %v", err)
+	}
+	ws, _ = state.NewWithDir(mgr.stateDir).LoadWorker("worker-1")
+	if ws.HasTask() || ws.Status != state.WorkerStatusIdle {
+		t.Errorf("after ClearTask() = %+v, want idle without a task", ws)
+	}
 }
 
 func TestManager_MultipleWorkers(t *testing.T) {
@@ -432,3 +516,31 @@ func TestManager_ClaimNextTask_Timeout(t *testing.T) {
 	_ = err
 }
 
+
+func TestManager_RecordsTaskEvents(t *testing.T) {
+	mgr, mock := testManager(t)
+	mgr.events = events.NewWithDir(t.TempDir())
+	ctx := context.Background()
+
+	mock.AddTask(ctx, "Task 1")
+	task, _ := mgr.ClaimNextTask(ctx, "worker-1")
+	if err := mgr.ReleaseWorkerTask(ctx, "worker-1"); err != nil {
+		t.Fatalf("ReleaseWorkerTask() error = %v", err)
+	}
+
+	got, err := mgr.events.Read(events.Filter{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("recorded %d events, want 2: %+v", len(got), got)
+	}
+	if got[0].Type != events.TaskClaimed || got[1].Type != events.TaskReleased {
+		t.Errorf("event types = %s, %s; want claimed, released", got[0].Type, got[1].Type)
+	}
+	for _, e := range got {
+		if e.Worker != "worker-1" || e.TaskID != task.ID || e.Branch != "isollm/"+task.ID {
+			t.Errorf("event = %+v, want worker-1 on %s", e, task.ID)
+		}
+	}
+}
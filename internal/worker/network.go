@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"fmt"
+	"net"
+
+	"isollm/internal/claude"
+)
+
+const (
+	// NetworkOpen leaves worker networking untouched
+	NetworkOpen = "open"
+	// NetworkAiryraOnly lets workers reach only the airyra host
+	NetworkAiryraOnly = "airyra-only"
+	// NetworkAllowlist lets workers reach the airyra host and the
+	// configured hosts and CIDRs
+	NetworkAllowlist = "allowlist"
+
+	// nicDevice is the worker's network interface from the default profile
+	nicDevice = "eth0"
+)
+
+// lookupHost resolves allowlist hostnames (replaced in tests)
+var lookupHost = net.LookupHost
+
+// EgressDestinations returns the CIDRs a worker may connect to under a
+// policy, or nil for an open network. The airyra host is always included.
+// Hostnames are resolved once, when the policy is applied.
+func EgressDestinations(policy string, allow []string, airyraHost string) ([]string, error) {
+	var entries []string
+	switch policy {
+	case NetworkOpen, "":
+		return nil, nil
+	case NetworkAiryraOnly:
+		entries = []string{airyraHost}
+	case NetworkAllowlist:
+		entries = append([]string{airyraHost}, allow...)
+	default:
+		return nil, fmt.Errorf("unknown network policy %q", policy)
+	}
+
+	seen := make(map[string]bool)
+	var dests []string
+	add := func(cidr string) {
+		if !seen[cidr] {
+			seen[cidr] = true
+			dests = append(dests, cidr)
+		}
+	}
+
+	for _, entry := range entries {
+		if _, ipnet, err := net.ParseCIDR(entry); err == nil {
+			add(ipnet.String())
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			add(hostCIDR(ip))
+			continue
+		}
+		addrs, err := lookupHost(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", entry, err)
+		}
+		for _, addr := range addrs {
+			if ip := net.ParseIP(addr); ip != nil {
+				add(hostCIDR(ip))
+			}
+		}
+	}
+
+	return dests, nil
+}
+
+// hostCIDR returns the single-address CIDR for an IP
+func hostCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.String() + "/32"
+	}
+	return ip.String() + "/128"
+}
+
+// aclName returns the name of the network ACL holding a worker's policy
+func (m *Manager) aclName(name string) string {
+	return fmt.Sprintf("isollm-%s-%s", m.cfg.Project, name)
+}
+
+// restrictNetwork applies the network policy to a worker, keeping the
+// airyra host reachable
+func (m *Manager) restrictNetwork(name string) error {
+	airyraHost, err := claude.GetHostIP()
+	if err != nil {
+		airyraHost = m.cfg.Airyra.Host
+	}
+	if err := m.ApplyNetworkPolicy(name, airyraHost); err != nil {
+		return fmt.Errorf("failed to apply network policy: %w", err)
+	}
+	return nil
+}
+
+// ApplyNetworkPolicy restricts a worker's outbound traffic to the
+// destinations allowed by the network policy in isollm.yaml. The policy is
+// enforced on the host with an LXD network ACL on the worker's NIC, so it
+// cannot be changed from inside the container. Inbound traffic is left
+// alone so port forwarding keeps working.
+func (m *Manager) ApplyNetworkPolicy(name, airyraHost string) error {
+	name = m.normalizeName(name)
+
+	dests, err := EgressDestinations(m.cfg.Network.Policy, m.cfg.Network.Allow, airyraHost)
+	if err != nil {
+		return err
+	}
+	if dests == nil {
+		return nil
+	}
+
+	acl := m.aclName(name)
+
+	// Drop any ACL left behind by an earlier worker with the same name
+	m.lxc("network", "acl", "delete", acl)
+	if _, err := m.lxc("network", "acl", "create", acl); err != nil {
+		return fmt.Errorf("failed to create network ACL %s: %w", acl, err)
+	}
+	for _, dest := range dests {
+		if _, err := m.lxc("network", "acl", "rule", "add", acl, "egress",
+			"action=allow", "destination="+dest); err != nil {
+			return fmt.Errorf("failed to allow %s in %s: %w", dest, acl, err)
+		}
+	}
+
+	settings := []string{
+		"security.acls=" + acl,
+		"security.acls.default.egress.action=drop",
+		"security.acls.default.ingress.action=allow",
+	}
+	args := append([]string{"config", "device", "set", name, nicDevice}, settings...)
+	if _, err := m.lxc(args...); err != nil {
+		// The NIC comes from a profile and must be overridden on the instance
+		args[2] = "override"
+		if _, err := m.lxc(args...); err != nil {
+			return fmt.Errorf("failed to attach network ACL to %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// removeNetworkPolicy deletes a worker's network ACL, if it has one
+func (m *Manager) removeNetworkPolicy(name string) {
+	if m.cfg.Network.Policy == "" || m.cfg.Network.Policy == NetworkOpen {
+		return
+	}
+	m.lxc("network", "acl", "delete", m.aclName(name))
+}
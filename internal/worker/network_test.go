@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEgressDestinations(t *testing.T) {
+	orig := lookupHost
+	lookupHost = func(host string) ([]string, error) {
+		return []string{"104.16.1.34", "2606:4700::6810:122"}, nil
+	}
+	defer func() { lookupHost = orig }()
+
+	tests := []struct {
+		name   string
+		policy string
+		allow  []string
+		want   []string
+	}{
+		{"open", NetworkOpen, []string{"10.0.0.0/8"}, nil},
+		{"airyra only", NetworkAiryraOnly, []string{"10.0.0.0/8"}, []string{"10.10.0.1/32"}},
+		{
+			"allowlist",
+			NetworkAllowlist,
+			[]string{"192.168.1.0/24", "1.1.1.1", "registry.npmjs.org", "10.10.0.1"},
+			[]string{"10.10.0.1/32", "192.168.1.0/24", "1.1.1.1/32", "104.16.1.34/32", "2606:4700::6810:122/128"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EgressDestinations(tt.policy, tt.allow, "10.10.0.1")
+			if err != nil {
+				t.Fatalf("EgressDestinations() error = %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("EgressDestinations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyNetworkPolicy(t *testing.T) {
+	mgr, _ := testManager(t)
+	mgr.cfg.Network.Policy = NetworkAiryraOnly
+	lxc := &fakeLXC{}
+	mgr.lxc = lxc.run
+
+	if err := mgr.ApplyNetworkPolicy("1", "10.10.0.1"); err != nil {
+		t.Fatalf("ApplyNetworkPolicy() error = %v", err)
+	}
+
+	calls := strings.Join(lxc.calls, "\n")
+	for _, want := range []string{
+		"network acl create isollm-test-project-worker-1",
+		"network acl rule add isollm-test-project-worker-1 egress action=allow destination=10.10.0.1/32",
+		"config device override worker-1 eth0 security.acls=isollm-test-project-worker-1 security.acls.default.egress.action=drop",
+	} {
+		if !strings.Contains(calls, want) {
+			t.Errorf("lxc calls missing %q:\n%s", want, calls)
+		}
+	}
+}
+
+func TestApplyNetworkPolicy_Open(t *testing.T) {
+	mgr, _ := testManager(t)
+	mgr.cfg.Network.Policy = NetworkOpen
+	lxc := &fakeLXC{}
+	mgr.lxc = lxc.run
+
+	if err := mgr.ApplyNetworkPolicy("worker-1", "10.10.0.1"); err != nil {
+		t.Fatalf("ApplyNetworkPolicy() error = %v", err)
+	}
+	if len(lxc.calls) != 0 {
+		t.Errorf("open policy ran lxc: %v", lxc.calls)
+	}
+}
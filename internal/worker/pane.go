@@ -0,0 +1,20 @@
+package worker
+
+import "isollm/internal/claude"
+
+// RunInPane starts cmd in the worker's zellij pane. It fails with
+// claude.ErrPaneBusy while the pane still runs a command.
+func (m *Manager) RunInPane(name string, cmd []string) error {
+	return claude.RunInPane(m, m.normalizeName(name), cmd)
+}
+
+// PaneBusy reports whether the worker's pane is running a command
+func (m *Manager) PaneBusy(name string) bool {
+	return claude.PaneBusy(m, m.normalizeName(name))
+}
+
+// StopPane stops the command running in the worker's pane, such as a
+// Claude session whose task is finished or taken away
+func (m *Manager) StopPane(name string) error {
+	return claude.StopPane(m, m.normalizeName(name))
+}
@@ -0,0 +1,217 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"isollm/internal/airyra"
+	"isollm/internal/claude"
+	"isollm/internal/events"
+	"isollm/internal/plans"
+	"isollm/internal/taskspec"
+)
+
+// ErrPlanRejected is returned by SplitWorkerTask when the plan is invalid
+// and the task has been blocked with the reason
+var ErrPlanRejected = errors.New("plan rejected")
+
+// PlanState returns how far a task has got with planning, or "" if it is
+// not a planning task
+func (m *Manager) PlanState(taskID string) plans.State {
+	if m.plans == nil {
+		return ""
+	}
+	p, err := m.plans.Get(taskID)
+	if err != nil || p == nil {
+		return ""
+	}
+	return p.State
+}
+
+// Plan returns the subtasks a planner wrote for its task. ok is false if
+// the planner has not written any. The plan stays until ClearPlan, so it
+// is not lost if splitting the task fails.
+func (m *Manager) Plan(workerName string) (plan []byte, ok bool) {
+	workerName = m.normalizeName(workerName)
+	out, err := m.client.Exec(workerName, []string{"cat", claude.PlanMarkerPath})
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// ClearPlan removes the subtasks a planner wrote once its task has been
+// split, or blocked because the plan was rejected
+func (m *Manager) ClearPlan(workerName string) error {
+	workerName = m.normalizeName(workerName)
+	if _, err := m.client.Exec(workerName, []string{"rm", "-f", claude.PlanMarkerPath}); err != nil {
+		return fmt.Errorf("failed to clear plan in %s: %w", workerName, err)
+	}
+	return nil
+}
+
+// SplitWorkerTask adds the subtasks a planner worker wrote as children of
+// its task, makes the task depend on all of them and gives it back to the
+// queue, where it waits until they are done for CompletePlans to finish
+// it. An invalid plan blocks the task with the reason instead and returns
+// ErrPlanRejected. The worker's
+// task assignment is left for the caller to clear.
+func (m *Manager) SplitWorkerTask(ctx context.Context, workerName string, data []byte) ([]string, error) {
+	if m.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+	if m.plans == nil {
+		return nil, fmt.Errorf("plan store not initialized")
+	}
+
+	workerName = m.normalizeName(workerName)
+
+	state, err := m.GetTask(workerName)
+	if err != nil || state == nil || state.TaskID == "" {
+		return nil, fmt.Errorf("worker has no assigned task")
+	}
+	taskID := state.TaskID
+
+	plan, err := m.plans.Get(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil || plan.State != plans.StatePending {
+		return nil, fmt.Errorf("task %s is not waiting to be planned", taskID)
+	}
+
+	spec, err := taskspec.ParseYAML(data)
+	if err == nil {
+		err = spec.Validate()
+	}
+	if err != nil {
+		reason := fmt.Sprintf("%v: %v", ErrPlanRejected, err)
+		if _, blockErr := m.block(ctx, workerName, taskID, reason, "isollm"); blockErr != nil {
+			return nil, fmt.Errorf("%s (and failed to block the task: %w)", reason, blockErr)
+		}
+		return nil, fmt.Errorf("task %s blocked: %w: %v", taskID, ErrPlanRejected, err)
+	}
+
+	spec.Parent = taskID
+	result, err := taskspec.Import(ctx, m.airyra, nil, "plan:"+taskID, spec, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add subtasks of %s: %w", taskID, err)
+	}
+
+	children := make([]string, 0, len(result.Tasks))
+	for _, t := range result.Tasks {
+		children = append(children, t.TaskID)
+	}
+
+	undo := func(cause error) error {
+		for _, id := range children {
+			m.airyra.DeleteTask(ctx, id)
+		}
+		return cause
+	}
+
+	for _, t := range result.Tasks {
+		if m.checks == nil {
+			break
+		}
+		if err := m.checks.Set(t.TaskID, t.Verify); err != nil {
+			return nil, undo(err)
+		}
+	}
+
+	for _, child := range children {
+		if err := m.airyra.AddDependency(ctx, taskID, child); err != nil {
+			return nil, undo(fmt.Errorf("failed to make %s wait for %s: %w", taskID, child, err))
+		}
+	}
+
+	client, err := m.clientFor(workerName)
+	if err != nil {
+		return nil, undo(err)
+	}
+	if _, err := client.ReleaseTask(ctx, taskID, false); err != nil {
+		return nil, undo(fmt.Errorf("failed to release %s: %w", taskID, err))
+	}
+
+	plan.State = plans.StateSplit
+	plan.Children = children
+	plan.Worker = workerName
+	plan.SplitAt = time.Now()
+	if err := m.plans.Save(plan); err != nil {
+		return children, err
+	}
+
+	m.emitTask(events.TaskSplit, workerName, taskID, fmt.Sprintf("%d subtasks", len(children)))
+	return children, nil
+}
+
+// CompletePlans completes split tasks whose subtasks are all done (or
+// deleted) and returns their IDs
+func (m *Manager) CompletePlans(ctx context.Context) ([]string, error) {
+	if m.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+	if m.plans == nil {
+		return nil, nil
+	}
+
+	list, err := m.plans.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var completed []string
+	for _, p := range list {
+		if p.State != plans.StateSplit {
+			continue
+		}
+
+		done, err := m.childrenDone(ctx, p.Children)
+		if err != nil {
+			return completed, err
+		}
+		if !done {
+			continue
+		}
+
+		_, err = m.airyra.ClaimTask(ctx, p.TaskID)
+		switch {
+		case airyra.IsAlreadyClaimed(err):
+			continue // Someone took it by hand, try again later
+		case airyra.IsTaskNotFound(err):
+			// Deleted, just forget the plan
+		case err != nil:
+			return completed, fmt.Errorf("failed to claim %s: %w", p.TaskID, err)
+		default:
+			if _, err := m.airyra.CompleteTask(ctx, p.TaskID); err != nil {
+				return completed, fmt.Errorf("failed to complete %s: %w", p.TaskID, err)
+			}
+			m.emitTask(events.TaskCompleted, "", p.TaskID, fmt.Sprintf("all %d subtasks done", len(p.Children)))
+			completed = append(completed, p.TaskID)
+		}
+
+		if err := m.plans.Delete(p.TaskID); err != nil {
+			return completed, fmt.Errorf("failed to remove plan for %s: %w", p.TaskID, err)
+		}
+	}
+	return completed, nil
+}
+
+// childrenDone reports whether every subtask is done or has been deleted
+func (m *Manager) childrenDone(ctx context.Context, children []string) (bool, error) {
+	for _, id := range children {
+		task, err := m.airyra.GetTask(ctx, id)
+		if airyra.IsTaskNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to get subtask %s: %w", id, err)
+		}
+		if task.Status != airyra.StatusDone {
+			return false, nil
+		}
+	}
+	return true, nil
+}
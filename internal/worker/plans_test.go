@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"isollm/internal/airyra"
+	"isollm/internal/notes"
+	"isollm/internal/plans"
+)
+
+const testPlan = `
+tasks:
+  - id: api
+    title: Add the API
+  - id: ui
+    title: Add the UI
+    depends_on: [api]
+`
+
+func planManager(t *testing.T) (*Manager, *airyra.MockClient) {
+	t.Helper()
+	mgr, mock := testManager(t)
+	mgr.notes = notes.NewWithDir(t.TempDir())
+	mgr.plans = plans.NewWithDir(t.TempDir())
+	return mgr, mock
+}
+
+func TestManager_SplitAndCompletePlan(t *testing.T) {
+	mgr, mock := planManager(t)
+	ctx := context.Background()
+
+	parent, _ := mock.AddTask(ctx, "Build the feature")
+	if err := mgr.plans.Mark(parent.ID); err != nil {
+		t.Fatalf("Mark() error = %v", err)
+	}
+	mgr.ClaimNextTask(ctx, "worker-1")
+
+	if got := mgr.PlanState(parent.ID); got != plans.StatePending {
+		t.Errorf("PlanState() = %q, want %q", got, plans.StatePending)
+	}
+
+	children, err := mgr.SplitWorkerTask(ctx, "worker-1", []byte(testPlan))
+	if err != nil {
+		t.Fatalf("SplitWorkerTask() error = %v", err)
+	}
+	if len(children) != 2 {
+		t.Fatalf("SplitWorkerTask() = %v, want 2 subtasks", children)
+	}
+
+	got, _ := mock.GetTask(ctx, parent.ID)
+	if got.Status != airyra.StatusOpen {
+		t.Errorf("parent status = %v, want %v", got.Status, airyra.StatusOpen)
+	}
+	deps, _ := mock.ListDependencies(ctx, parent.ID)
+	if len(deps) != 2 {
+		t.Errorf("parent depends on %d tasks, want 2", len(deps))
+	}
+	if got := mgr.PlanState(parent.ID); got != plans.StateSplit {
+		t.Errorf("PlanState() = %q, want %q", got, plans.StateSplit)
+	}
+
+	// Not done until every subtask is
+	mock.ClaimTask(ctx, children[0])
+	mock.CompleteTask(ctx, children[0])
+	if done, err := mgr.CompletePlans(ctx); err != nil || len(done) != 0 {
+		t.Fatalf("CompletePlans() = %v, %v; want nothing completed", done, err)
+	}
+
+	mock.DeleteTask(ctx, children[1])
+	done, err := mgr.CompletePlans(ctx)
+	if err != nil {
+		t.Fatalf("CompletePlans() error = %v", err)
+	}
+	if len(done) != 1 || done[0] != parent.ID {
+		t.Errorf("CompletePlans() = %v, want [%s]", done, parent.ID)
+	}
+	got, _ = mock.GetTask(ctx, parent.ID)
+	if got.Status != airyra.StatusDone {
+		t.Errorf("parent status = %v, want %v", got.Status, airyra.StatusDone)
+	}
+	if p, _ := mgr.plans.Get(parent.ID); p != nil {
+		t.Errorf("plan = %+v, want it removed", p)
+	}
+}
+
+func TestManager_SplitWorkerTask_RejectsInvalidPlan(t *testing.T) {
+	mgr, mock := planManager(t)
+	ctx := context.Background()
+
+	parent, _ := mock.AddTask(ctx, "Build the feature")
+	mgr.plans.Mark(parent.ID)
+	mgr.ClaimNextTask(ctx, "worker-1")
+
+	invalid := "tasks:\n  - id: a\n    title: A\n    depends_on: [missing]\n"
+	if _, err := mgr.SplitWorkerTask(ctx, "worker-1", []byte(invalid)); err == nil {
+		t.Fatal("SplitWorkerTask() with an unknown dependency = nil, want error")
+	}
+
+	if n := mock.GetTaskCount(); n != 1 {
+		t.Errorf("task count = %d, want no subtasks added", n)
+	}
+	got, _ := mock.GetTask(ctx, parent.ID)
+	if got.Status != airyra.StatusBlocked {
+		t.Errorf("parent status = %v, want %v", got.Status, airyra.StatusBlocked)
+	}
+	if got := mgr.PlanState(parent.ID); got != plans.StatePending {
+		t.Errorf("PlanState() = %q, want %q", got, plans.StatePending)
+	}
+}
+
+func TestManager_SplitWorkerTask_NotPlanning(t *testing.T) {
+	mgr, mock := planManager(t)
+	ctx := context.Background()
+
+	mock.AddTask(ctx, "Ordinary task")
+	mgr.ClaimNextTask(ctx, "worker-1")
+
+	if _, err := mgr.SplitWorkerTask(ctx, "worker-1", []byte(testPlan)); err == nil {
+		t.Error("SplitWorkerTask() on a task without a plan = nil, want error")
+	}
+	if n := mock.GetTaskCount(); n != 1 {
+		t.Errorf("task count = %d, want no subtasks added", n)
+	}
+}
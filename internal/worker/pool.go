@@ -0,0 +1,75 @@
+package worker
+
+import "sync"
+
+// DefaultParallel is how many workers are created or started at once
+const DefaultParallel = 4
+
+// Result is the outcome of an operation on one worker
+type Result struct {
+	Name string
+	Err  error
+}
+
+// ForEach runs fn for each worker, at most parallel at a time. report, if
+// set, is called as each one finishes and never concurrently, so it can
+// print progress. Results are returned in the order of names.
+func ForEach(names []string, parallel int, fn func(name string) error, report func(Result)) []Result {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]Result, len(names))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r := Result{Name: name, Err: fn(name)}
+
+			mu.Lock()
+			results[i] = r
+			if report != nil {
+				report(r)
+			}
+			mu.Unlock()
+		}(i, name)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// Succeeded returns the names of the workers whose operation succeeded
+func Succeeded(results []Result) []string {
+	var names []string
+	for _, r := range results {
+		if r.Err == nil {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// Partition splits workers into the names of running and stopped ones.
+// Workers that failed to provision are unusable until they are removed and
+// are returned as failed instead.
+func Partition(workers []WorkerInfo) (running, stopped, failed []string) {
+	for _, w := range workers {
+		switch {
+		case w.Error != "":
+			failed = append(failed, w.Name)
+		case w.Status == "RUNNING":
+			running = append(running, w.Name)
+		default:
+			stopped = append(stopped, w.Name)
+		}
+	}
+	return running, stopped, failed
+}
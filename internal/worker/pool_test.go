@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	names := []string{"worker-1", "worker-2", "worker-3", "worker-4", "worker-5"}
+
+	var running, peak int32
+	var reported []string
+	results := ForEach(names, 2, func(name string) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		if name == "worker-3" {
+			return errors.New("container not ready")
+		}
+		return nil
+	}, func(r Result) {
+		reported = append(reported, r.Name)
+	})
+
+	if peak > 2 {
+		t.Errorf("ran %d at once, want at most 2", peak)
+	}
+	if len(reported) != len(names) {
+		t.Errorf("reported %v, want every worker", reported)
+	}
+	for i, r := range results {
+		if r.Name != names[i] {
+			t.Errorf("results[%d] = %s, want %s", i, r.Name, names[i])
+		}
+	}
+	if results[2].Err == nil {
+		t.Error("worker-3 error not returned")
+	}
+
+	got := strings.Join(Succeeded(results), ",")
+	if got != "worker-1,worker-2,worker-4,worker-5" {
+		t.Errorf("Succeeded() = %s", got)
+	}
+}
+
+func TestForEach_Empty(t *testing.T) {
+	if results := ForEach(nil, 0, func(string) error { return nil }, nil); len(results) != 0 {
+		t.Errorf("ForEach(nil) = %v", results)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	workers := []WorkerInfo{
+		{Name: "worker-1", Status: "RUNNING"},
+		{Name: "worker-2", Status: "STOPPED"},
+		{Name: "worker-3", Status: "RUNNING"},
+		{Name: "worker-4", Status: "RUNNING", Error: "clone failed"},
+	}
+
+	running, stopped, failed := Partition(workers)
+	if strings.Join(running, ",") != "worker-1,worker-3" {
+		t.Errorf("running = %v, want [worker-1 worker-3]", running)
+	}
+	if strings.Join(stopped, ",") != "worker-2" {
+		t.Errorf("stopped = %v, want [worker-2]", stopped)
+	}
+	if strings.Join(failed, ",") != "worker-4" {
+		t.Errorf("failed = %v, want [worker-4]", failed)
+	}
+}
+
+func TestPartition_AllRunning(t *testing.T) {
+	workers := []WorkerInfo{
+		{Name: "worker-1", Status: "RUNNING"},
+		{Name: "worker-2", Status: "RUNNING"},
+	}
+
+	// Running workers must be reused, not started again or replaced
+	running, stopped, _ := Partition(workers)
+	if len(running) != len(workers) || len(stopped) != 0 {
+		t.Errorf("Partition() = running %v, stopped %v; want every worker running", running, stopped)
+	}
+}
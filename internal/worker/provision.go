@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"isollm/internal/config"
+)
+
+const (
+	// ProvisionEnvPath holds the provision.env variables in workers
+	ProvisionEnvPath = "/home/dev/.isollm-provision-env"
+	// SetupScriptPath is where setup_script is written in workers
+	SetupScriptPath = "/home/dev/.isollm-setup.sh"
+	// devHome is the dev user's home directory in workers
+	devHome = "/home/dev"
+)
+
+// Execer runs commands in a worker container (Manager, or a fake in tests)
+type Execer interface {
+	Exec(name string, cmd []string) ([]byte, error)
+}
+
+// Provisioner runs the provisioning pipeline in a freshly cloned worker:
+// packages, files, environment and finally the setup script
+type Provisioner struct {
+	execer     Execer
+	projectDir string
+	cfg        config.ProvisionConfig
+	script     string
+	log        io.Writer
+}
+
+// NewProvisioner creates a Provisioner that writes command output to log.
+// Relative file sources are resolved against projectDir.
+func NewProvisioner(execer Execer, projectDir string, cfg *config.Config, log io.Writer) *Provisioner {
+	return &Provisioner{
+		execer:     execer,
+		projectDir: projectDir,
+		cfg:        cfg.Provision,
+		script:     cfg.Setup,
+		log:        log,
+	}
+}
+
+// Run provisions a worker, stopping at the first failing step
+func (p *Provisioner) Run(name string) error {
+	steps := []struct {
+		desc string
+		skip bool
+		run  func(name string) error
+	}{
+		{"install packages", len(p.cfg.Packages) == 0, p.installPackages},
+		{"copy files", len(p.cfg.Files) == 0, p.copyFiles},
+		{"write environment", len(p.cfg.Env) == 0, p.writeEnv},
+		{"run setup script", strings.TrimSpace(p.script) == "", p.runScript},
+	}
+
+	for _, step := range steps {
+		if step.skip {
+			continue
+		}
+		fmt.Fprintf(p.log, "==> %s\n", step.desc)
+		if err := step.run(name); err != nil {
+			fmt.Fprintf(p.log, "FAILED: %v\n", err)
+			return fmt.Errorf("failed to %s: %w", step.desc, err)
+		}
+	}
+	return nil
+}
+
+// exec runs a command and copies its output to the log
+func (p *Provisioner) exec(name string, cmd []string) error {
+	out, err := p.execer.Exec(name, cmd)
+	if len(out) > 0 {
+		p.log.Write(out)
+	}
+	return err
+}
+
+func (p *Provisioner) installPackages(name string) error {
+	script := fmt.Sprintf("apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y %s",
+		strings.Join(p.cfg.Packages, " "))
+	return p.exec(name, []string{"sh", "-c", "(" + script + ") 2>&1"})
+}
+
+func (p *Provisioner) copyFiles(name string) error {
+	for _, f := range p.cfg.Files {
+		src := p.hostPath(f.Src)
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", src, err)
+		}
+
+		mode := f.Mode
+		if mode == "" {
+			mode = "0644"
+		}
+		if _, err := strconv.ParseUint(mode, 8, 32); err != nil {
+			return fmt.Errorf("invalid mode %q for %s", mode, f.Dst)
+		}
+
+		dst := containerPath(f.Dst)
+		fmt.Fprintf(p.log, "%s -> %s\n", f.Src, dst)
+		if err := p.writeFile(name, dst, data, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Provisioner) writeEnv(name string) error {
+	keys := make([]string, 0, len(p.cfg.Env))
+	for k := range p.cfg.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("# Written by isollm from provision.env\n")
+	for _, k := range keys {
+		b.WriteString(fmt.Sprintf("export %s=%s\n", k, shellQuote(p.cfg.Env[k])))
+	}
+
+	if err := p.writeFile(name, ProvisionEnvPath, []byte(b.String()), "0644"); err != nil {
+		return err
+	}
+
+	// Source it from .bashrc once
+	marker := "# isollm provision environment"
+	return p.exec(name, []string{"sh", "-c", fmt.Sprintf(
+		"grep -q %s %s/.bashrc || printf '\\n%%s\\n%%s\\n' %s %s >> %s/.bashrc",
+		shellQuote(marker), devHome, shellQuote(marker), shellQuote(". "+ProvisionEnvPath), devHome,
+	)})
+}
+
+func (p *Provisioner) runScript(name string) error {
+	script := fmt.Sprintf("set -e\ncd %s\n[ -f %s ] && . %s\n%s\n",
+		ProjectPath, ProvisionEnvPath, ProvisionEnvPath, p.script)
+	if err := p.writeFile(name, SetupScriptPath, []byte(script), "0755"); err != nil {
+		return err
+	}
+	return p.exec(name, []string{"su", "-l", "dev", "-c", "bash " + SetupScriptPath + " 2>&1"})
+}
+
+// writeFile writes data to a path in the container, owned by dev
+func (p *Provisioner) writeFile(name, dst string, data []byte, mode string) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	script := fmt.Sprintf("mkdir -p %s && printf '%%s' %s | base64 -d > %s && chmod %s %s && chown dev:dev %s",
+		shellQuote(path.Dir(dst)), encoded, shellQuote(dst), mode, shellQuote(dst), shellQuote(dst))
+	if err := p.exec(name, []string{"sh", "-c", script}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
+
+// hostPath resolves a file source on the host
+func (p *Provisioner) hostPath(src string) string {
+	return resolveHostPath(p.projectDir, src)
+}
+
+// resolveHostPath resolves a file source against the home directory or
+// the project directory
+func resolveHostPath(projectDir, src string) string {
+	if strings.HasPrefix(src, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, src[2:])
+		}
+	}
+	if filepath.IsAbs(src) {
+		return src
+	}
+	return filepath.Join(projectDir, src)
+}
+
+// containerPath resolves a file destination in the container
+func containerPath(dst string) string {
+	if path.IsAbs(dst) {
+		return dst
+	}
+	return path.Join(devHome, strings.TrimPrefix(dst, "~/"))
+}
+
+// shellQuote quotes s for use as a single word in sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"isollm/internal/config"
+	"isollm/internal/state"
+)
+
+// fakeExecer records commands and fails those containing a substring
+type fakeExecer struct {
+	cmds []string
+	fail string
+}
+
+func (f *fakeExecer) Exec(name string, cmd []string) ([]byte, error) {
+	joined := strings.Join(cmd, " ")
+	f.cmds = append(f.cmds, joined)
+	if f.fail != "" && strings.Contains(joined, f.fail) {
+		return []byte("E: Unable to locate package\n"), errors.New("exit status 100")
+	}
+	return []byte("ok\n"), nil
+}
+
+func TestProvisioner_RunsStepsInOrder(t *testing.T) {
+	projectDir := t.TempDir()
+	os.WriteFile(filepath.Join(projectDir, "worker.npmrc"), []byte("registry=https://example.test\n"), 0644)
+
+	cfg := &config.Config{
+		Setup: "npm install",
+		Provision: config.ProvisionConfig{
+			Packages: []string{"nodejs", "npm"},
+			Files:    []config.FileCopy{{Src: "worker.npmrc", Dst: ".npmrc", Mode: "0600"}},
+			Env:      map[string]string{"NODE_ENV": "development"},
+		},
+	}
+
+	ex := &fakeExecer{}
+	var log bytes.Buffer
+	if err := NewProvisioner(ex, projectDir, cfg, &log).Run("worker-1"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	all := strings.Join(ex.cmds, "\n")
+	order := []string{
+		"apt-get install -y nodejs npm",
+		"/home/dev/.npmrc",
+		ProvisionEnvPath,
+		"su -l dev -c bash " + SetupScriptPath,
+	}
+	last := -1
+	for _, want := range order {
+		i := strings.Index(all, want)
+		if i < 0 {
+			t.Fatalf("commands missing %q:\n%s", want, all)
+		}
+		if i < last {
+			t.Errorf("%q ran out of order", want)
+		}
+		last = i
+	}
+
+	content := base64.StdEncoding.EncodeToString([]byte("registry=https://example.test\n"))
+	if !strings.Contains(all, content) || !strings.Contains(all, "chmod 0600") {
+		t.Error("file was not copied with its content and mode")
+	}
+	env := base64.StdEncoding.EncodeToString([]byte("# Written by isollm from provision.env\nexport NODE_ENV='development'\n"))
+	if !strings.Contains(all, env) {
+		t.Error("environment file missing NODE_ENV")
+	}
+	if !strings.Contains(log.String(), "==> run setup script") {
+		t.Errorf("log = %q, want step headers", log.String())
+	}
+}
+
+func TestProvisioner_NothingConfigured(t *testing.T) {
+	ex := &fakeExecer{}
+	if err := NewProvisioner(ex, t.TempDir(), &config.Config{}, &bytes.Buffer{}).Run("worker-1"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(ex.cmds) != 0 {
+		t.Errorf("ran %v with nothing to provision", ex.cmds)
+	}
+}
+
+func TestProvisioner_StopsAtFailure(t *testing.T) {
+	cfg := &config.Config{
+		Setup:     "make deps",
+		Provision: config.ProvisionConfig{Packages: []string{"no-such-package"}},
+	}
+
+	ex := &fakeExecer{fail: "apt-get"}
+	var log bytes.Buffer
+	err := NewProvisioner(ex, t.TempDir(), cfg, &log).Run("worker-1")
+	if err == nil || !strings.Contains(err.Error(), "install packages") {
+		t.Fatalf("Run() error = %v, want install packages failure", err)
+	}
+	if len(ex.cmds) != 1 {
+		t.Errorf("ran %d commands, want to stop after the failure", len(ex.cmds))
+	}
+	if !strings.Contains(log.String(), "Unable to locate package") || !strings.Contains(log.String(), "FAILED") {
+		t.Errorf("log = %q, want the command output and failure", log.String())
+	}
+}
+
+func TestProvisioner_MissingSourceFile(t *testing.T) {
+	cfg := &config.Config{
+		Provision: config.ProvisionConfig{Files: []config.FileCopy{{Src: "missing", Dst: ".x"}}},
+	}
+	if err := NewProvisioner(&fakeExecer{}, t.TempDir(), cfg, &bytes.Buffer{}).Run("worker-1"); err == nil {
+		t.Fatal("Run() error = nil, want error for missing source")
+	}
+}
+
+func TestManager_ProvisionError(t *testing.T) {
+	mgr, _ := testManager(t)
+	mgr.workerState = state.NewWithDir(t.TempDir())
+
+	if got := mgr.ProvisionError("worker-1"); got != "" {
+		t.Errorf("ProvisionError() = %q before any failure", got)
+	}
+
+	mgr.markError("worker-1", errors.New("failed to install packages: exit status 100"))
+	if got := mgr.ProvisionError("1"); !strings.Contains(got, "install packages") {
+		t.Errorf("ProvisionError() = %q, want the recorded failure", got)
+	}
+}
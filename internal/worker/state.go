@@ -1,94 +1,47 @@
 package worker
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
+
+	"isollm/internal/state"
 )
 
-// TaskState represents the task assignment for a worker.
-// This is the only state isollm tracks locally - container status,
-// IP, ports, etc. are queried from lxc-dev-manager.
+// TaskState is a worker's task assignment, as kept in its state.WorkerState.
+// Container status, IP, ports, etc. are queried from lxc-dev-manager.
 type TaskState struct {
-	WorkerName string    `json:"worker_name"`
-	TaskID     string    `json:"task_id,omitempty"`
-	Branch     string    `json:"branch,omitempty"`
-	ClaimedAt  time.Time `json:"claimed_at,omitempty"`
+	WorkerName string
+	TaskID     string
+	Branch     string
+	ClaimedAt  time.Time
 }
 
-// SaveTaskState saves the task state to disk
-func SaveTaskState(stateDir string, state *TaskState) error {
-	if err := os.MkdirAll(stateDir, 0755); err != nil {
-		return fmt.Errorf("failed to create state directory: %w", err)
-	}
-
-	path := filepath.Join(stateDir, state.WorkerName+".json")
-
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal task state: %w", err)
+// taskStateOf returns the task assignment held in a worker's state, or nil
+// if it has none
+func taskStateOf(w *state.WorkerState) *TaskState {
+	if w == nil || !w.HasTask() {
+		return nil
 	}
-
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write task state: %w", err)
+	return &TaskState{
+		WorkerName: w.Name,
+		TaskID:     w.CurrentTask.ID,
+		Branch:     w.CurrentBranch,
+		ClaimedAt:  w.CurrentTask.ClaimedAt,
 	}
-
-	return nil
 }
 
-// LoadTaskState loads the task state from disk
-func LoadTaskState(stateDir, workerName string) (*TaskState, error) {
-	path := filepath.Join(stateDir, workerName+".json")
-
-	data, err := os.ReadFile(path)
+// TaskStates returns the task assignments of all workers that have one
+func (m *Manager) TaskStates() ([]*TaskState, error) {
+	result, err := m.workerState.LoadAllWorkers()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to read task state: %w", err)
-	}
-
-	var state TaskState
-	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal task state: %w", err)
-	}
-
-	return &state, nil
-}
-
-// ClearTaskState removes the task state file
-func ClearTaskState(stateDir, workerName string) error {
-	path := filepath.Join(stateDir, workerName+".json")
-	return os.Remove(path)
-}
-
-// ListTaskStates returns all task states in the directory
-func ListTaskStates(stateDir string) ([]*TaskState, error) {
-	entries, err := os.ReadDir(stateDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to read state directory: %w", err)
+		return nil, fmt.Errorf("failed to load worker state: %w", err)
 	}
 
 	var states []*TaskState
-	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
-			continue
-		}
-
-		workerName := entry.Name()[:len(entry.Name())-5] // strip .json
-		state, err := LoadTaskState(stateDir, workerName)
-		if err != nil {
-			continue
-		}
-		if state != nil {
-			states = append(states, state)
+	for _, w := range result.Workers {
+		if ts := taskStateOf(w); ts != nil {
+			states = append(states, ts)
 		}
 	}
-
 	return states, nil
 }
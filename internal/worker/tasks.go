@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"isollm/internal/airyra"
+	"isollm/internal/events"
+)
+
+// ReleaseTask gives a task back to the queue, acting as the worker holding
+// it when there is one and freeing that worker. With force the task is
+// released whoever claimed it.
+func (m *Manager) ReleaseTask(ctx context.Context, taskID string, force bool) (*airyra.Task, error) {
+	if m.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+
+	workerName, err := m.workerFor(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := m.holderClient(workerName)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.ReleaseTask(ctx, taskID, force)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release task: %w", err)
+	}
+
+	if err := m.free(workerName); err != nil {
+		return task, err
+	}
+
+	m.emitTask(events.TaskReleased, workerName, taskID, "")
+	return task, nil
+}
+
+// ReopenTask puts a blocked, in-progress or done task back in the queue,
+// freeing the worker holding it
+func (m *Manager) ReopenTask(ctx context.Context, taskID string) (*airyra.Task, error) {
+	if m.airyra == nil {
+		return nil, fmt.Errorf("airyra client not initialized")
+	}
+
+	task, err := m.airyra.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if task.Status == airyra.StatusOpen {
+		return nil, fmt.Errorf("task %s is already open", taskID)
+	}
+
+	workerName, err := m.workerFor(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := m.holderClient(workerName)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Status == airyra.StatusBlocked {
+		if _, err := client.UnblockTask(ctx, taskID); err != nil {
+			return nil, fmt.Errorf("failed to unblock task: %w", err)
+		}
+	}
+
+	task, err = client.ReleaseTask(ctx, taskID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reopen task: %w", err)
+	}
+
+	if err := m.free(workerName); err != nil {
+		return task, err
+	}
+
+	m.emitTask(events.TaskReleased, workerName, taskID, "reopened")
+	return task, nil
+}
+
+// DeleteTask removes a task from the queue along with its notes, plan and
+// verify commands, freeing the worker holding it
+func (m *Manager) DeleteTask(ctx context.Context, taskID string) error {
+	if m.airyra == nil {
+		return fmt.Errorf("airyra client not initialized")
+	}
+
+	workerName, err := m.workerFor(taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := m.airyra.DeleteTask(ctx, taskID); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	if err := m.free(workerName); err != nil {
+		return err
+	}
+	if m.notes != nil {
+		if err := m.notes.Delete(taskID); err != nil {
+			return fmt.Errorf("failed to delete notes for %s: %w", taskID, err)
+		}
+	}
+	if m.plans != nil {
+		if err := m.plans.Delete(taskID); err != nil {
+			return fmt.Errorf("failed to delete plan for %s: %w", taskID, err)
+		}
+	}
+	if m.checks != nil {
+		if err := m.checks.Delete(taskID); err != nil {
+			return fmt.Errorf("failed to delete verify commands for %s: %w", taskID, err)
+		}
+	}
+	return nil
+}
+
+// free clears a worker's task assignment. It does nothing for "".
+func (m *Manager) free(workerName string) error {
+	if workerName == "" {
+		return nil
+	}
+	if err := m.ClearTask(workerName); err != nil {
+		return fmt.Errorf("failed to clear task state for %s: %w", workerName, err)
+	}
+	return nil
+}
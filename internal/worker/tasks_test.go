@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"isollm/internal/airyra"
+	"isollm/internal/notes"
+)
+
+func TestManager_ReleaseTask_FreesHolder(t *testing.T) {
+	mgr, mock := testManager(t)
+	ctx := context.Background()
+
+	mock.AddTask(ctx, "Task 1")
+	task, _ := mgr.ClaimNextTask(ctx, "worker-1")
+
+	released, err := mgr.ReleaseTask(ctx, task.ID, false)
+	if err != nil {
+		t.Fatalf("ReleaseTask() error = %v", err)
+	}
+	if released.Status != airyra.StatusOpen {
+		t.Errorf("ReleaseTask() status = %v, want %v", released.Status, airyra.StatusOpen)
+	}
+	if state, _ := mgr.GetTask("worker-1"); state != nil {
+		t.Errorf("worker still holds %+v after release", state)
+	}
+}
+
+func TestManager_ReleaseTask_NotOwner(t *testing.T) {
+	mgr, mock := testManager(t)
+	ctx := context.Background()
+
+	task, _ := mock.AddTask(ctx, "Task 1")
+	mock.SetAgentID("someone-else")
+	mock.ClaimTask(ctx, task.ID)
+	mock.SetAgentID("test-agent")
+
+	if _, err := mgr.ReleaseTask(ctx, task.ID, false); !airyra.IsNotOwner(err) {
+		t.Errorf("ReleaseTask() = %v, want NotOwner", err)
+	}
+	if _, err := mgr.ReleaseTask(ctx, task.ID, true); err != nil {
+		t.Errorf("ReleaseTask(force) error = %v", err)
+	}
+}
+
+func TestManager_ReopenTask(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(mgr *Manager, mock *airyra.MockClient, id string)
+	}{
+		{"in progress", func(mgr *Manager, mock *airyra.MockClient, id string) {}},
+		{"blocked", func(mgr *Manager, mock *airyra.MockClient, id string) {
+			mgr.BlockWorkerTask(context.Background(), "worker-1", "")
+		}},
+		{"done", func(mgr *Manager, mock *airyra.MockClient, id string) {
+			mock.CompleteTask(context.Background(), id)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr, mock := testManager(t)
+			ctx := context.Background()
+
+			mock.AddTask(ctx, "Task 1")
+			task, _ := mgr.ClaimNextTask(ctx, "worker-1")
+			tt.setup(mgr, mock, task.ID)
+
+			reopened, err := mgr.ReopenTask(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("ReopenTask() error = %v", err)
+			}
+			if reopened.Status != airyra.StatusOpen || reopened.ClaimedBy != nil {
+				t.Errorf("ReopenTask() = %v claimed by %v, want open and unclaimed", reopened.Status, reopened.ClaimedBy)
+			}
+			if state, _ := mgr.GetTask("worker-1"); state != nil {
+				t.Errorf("worker still holds %+v after reopen", state)
+			}
+		})
+	}
+}
+
+func TestManager_ReopenTask_AlreadyOpen(t *testing.T) {
+	mgr, mock := testManager(t)
+	ctx := context.Background()
+
+	task, _ := mock.AddTask(ctx, "Task 1")
+	if _, err := mgr.ReopenTask(ctx, task.ID); err == nil {
+		t.Error("ReopenTask() on an open task = nil, want error")
+	}
+}
+
+func TestManager_DeleteTask(t *testing.T) {
+	mgr, mock := testManager(t)
+	mgr.notes = notes.NewWithDir(t.TempDir())
+	ctx := context.Background()
+
+	mock.AddTask(ctx, "Task 1")
+	task, _ := mgr.ClaimNextTask(ctx, "worker-1")
+	mgr.notes.Add(task.ID, notes.Note{Kind: notes.KindBlocker, Text: "question"})
+
+	if err := mgr.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := mock.GetTask(ctx, task.ID); !airyra.IsTaskNotFound(err) {
+		t.Errorf("GetTask() after delete = %v, want TaskNotFound", err)
+	}
+	if state, _ := mgr.GetTask("worker-1"); state != nil {
+		t.Errorf("worker still holds %+v after delete", state)
+	}
+	if got, _ := mgr.notes.List(task.ID); len(got) != 0 {
+		t.Errorf("notes = %+v after delete, want none", got)
+	}
+}
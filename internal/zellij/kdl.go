@@ -142,7 +142,7 @@ func generateWorkerPane(worker WorkerPane, focus bool) string {
 
 	// Worker panes run lxc exec to enter the container
 	cmd := "lxc"
-	args := []string{"exec", worker.ContainerName, "--", "su", "-l", "dev"}
+	args := workerPaneArgs(worker)
 
 	b.WriteString("            pane {\n")
 	b.WriteString(fmt.Sprintf("                name \"%s\"\n", escapeKDLString(worker.Name)))
@@ -169,7 +169,7 @@ func generateWorkerPaneNested(worker WorkerPane, focus bool) string {
 
 	// Worker panes run lxc exec to enter the container
 	cmd := "lxc"
-	args := []string{"exec", worker.ContainerName, "--", "su", "-l", "dev"}
+	args := workerPaneArgs(worker)
 
 	b.WriteString("                pane {\n")
 	b.WriteString(fmt.Sprintf("                    name \"%s\"\n", escapeKDLString(worker.Name)))
@@ -190,6 +190,16 @@ func generateWorkerPaneNested(worker WorkerPane, focus bool) string {
 	return b.String()
 }
 
+// workerPaneArgs returns the lxc arguments that run a worker pane's command
+// in its container, a login shell unless the pane sets one
+func workerPaneArgs(worker WorkerPane) []string {
+	args := []string{"exec", worker.ContainerName, "--"}
+	if len(worker.Command) > 0 {
+		return append(args, worker.Command...)
+	}
+	return append(args, "su", "-l", "dev")
+}
+
 // escapeKDLString escapes special characters in a KDL string
 func escapeKDLString(s string) string {
 	s = strings.ReplaceAll(s, "\\", "\\\\")
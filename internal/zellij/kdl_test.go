@@ -625,6 +625,23 @@ func TestGenerateKDL_WorkerPaneStructure(t *testing.T) {
 	}
 }
 
+func TestGenerateKDL_WorkerPaneCommand(t *testing.T) {
+	cfg := SessionConfig{
+		Name:   "command-test",
+		Layout: LayoutModeGrid,
+		Workers: []WorkerPane{
+			{Name: "worker-1", ContainerName: "worker-1", Command: []string{"su", "-l", "dev", "-c", "echo \"hi\"\n\tsleep 1"}},
+		},
+	}
+
+	kdl := GenerateKDL(cfg)
+
+	want := `args "exec" "worker-1" "--" "su" "-l" "dev" "-c" "echo \"hi\"\n\tsleep 1"`
+	if !strings.Contains(kdl, want) {
+		t.Errorf("expected pane command args %s, got:\n%s", want, kdl)
+	}
+}
+
 func TestGenerateKDL_FocusOnFirstPane(t *testing.T) {
 	cfg := SessionConfig{
 		Name:   "focus-test",
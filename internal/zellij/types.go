@@ -68,6 +68,7 @@ type SessionConfig struct {
 type WorkerPane struct {
 	Name          string // Worker name (e.g., "worker-1")
 	ContainerName string // LXC container name
+	Command       []string // Run in the container instead of a login shell
 }
 
 // Session represents an active zellij session